@@ -27,6 +27,37 @@ func (s *StudentRecordContract) isFaculty(ctx contractapi.TransactionContextInte
 	return true
 }
 
+// isStudentSelf checks if the client identity belongs to the student with the given ID
+func (s *StudentRecordContract) isStudentSelf(ctx contractapi.TransactionContextInterface, clientID cid.ClientIdentity, studentID string) bool {
+	// Get the attribute named "studentID" from the client's certificate
+	studentIDAttribute, found, _ := clientID.GetAttributeValue("studentID")
+
+	// Check if the "studentID" attribute is present and matches the student
+	return found && studentIDAttribute == studentID
+}
+
+// callerFacultyID returns the faculty ID in the client identity's certificate, the returned bool is false if it has none
+func (s *StudentRecordContract) callerFacultyID(ctx contractapi.TransactionContextInterface, clientID cid.ClientIdentity) (string, bool) {
+	// Get the attribute named "facultyID" from the client's certificate
+	facultyIDAttribute, found, _ := clientID.GetAttributeValue("facultyID")
+	return facultyIDAttribute, found && facultyIDAttribute != ""
+}
+
+// isDepartmentHead checks if the client identity belongs to the faculty heading the given department
+func (s *StudentRecordContract) isDepartmentHead(ctx contractapi.TransactionContextInterface, clientID cid.ClientIdentity, departmentID string) bool {
+	// Get the attribute named "facultyID" from the client's certificate
+	facultyIDAttribute, found, _ := clientID.GetAttributeValue("facultyID")
+
+	if !found || facultyIDAttribute == "" {
+		return false
+	}
+
+	// Check if the "facultyID" attribute matches the head of the department
+	var department Department
+	exists, err := readEntity(ctx, departmentObjectType, departmentID, &department)
+	return err == nil && exists && facultyIDAttribute == department.HeadID
+}
+
 // // isFacultyOfCourse checks if the client identity matches the faculty ID of the given course.
 // func (s *StudentRecordContract) isFacultyOfCourse(ctx contractapi.TransactionContextInterface, clientID cid.ClientIdentity, courseID string) bool {
 // 	// Get the attribute named "facultyID" from the client's certificate
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Faculty designations
+const (
+	designationProfessor          = "professor"
+	designationAssociateProfessor = "associate professor"
+	designationAssistantProfessor = "assistant professor"
+	designationAdvisor            = "advisor"
+	designationHoD                = "HoD"
+)
+
+// facultyDesignations are the designations a faculty can hold
+// HoD is given and taken away by SetDepartmentHead
+var facultyDesignations = []string{designationProfessor, designationAssociateProfessor, designationAssistantProfessor, designationAdvisor, designationHoD}
+
+// SetFacultyDesignations replaces the designations of a faculty, given as a JSON list
+func (s *StudentRecordContract) SetFacultyDesignations(ctx contractapi.TransactionContextInterface, facultyID string, designationsJSON string) error {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can set faculty designations")
+	}
+
+	var designations []string
+	if err := json.Unmarshal([]byte(designationsJSON), &designations); err != nil {
+		return fmt.Errorf("unmarhsal error")
+	}
+
+	var faculty Faculty
+	exists, err := readEntity(ctx, facultyObjectType, facultyID, &faculty)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("Faculty with ID %s does not exist", facultyID)
+	}
+
+	newDesignations := make([]string, 0, len(designations))
+	for _, designation := range designations {
+		if !contains(facultyDesignations, designation) {
+			return fmt.Errorf("Invalid designation %s: must be one of %v", designation, facultyDesignations)
+		}
+		if designation == designationHoD {
+			return fmt.Errorf("The HoD designation is given with SetDepartmentHead")
+		}
+		if contains(newDesignations, designation) {
+			return fmt.Errorf("Designation %s is listed more than once", designation)
+		}
+		newDesignations = append(newDesignations, designation)
+	}
+	// A department head keeps the HoD designation
+	if contains(faculty.Designations, designationHoD) {
+		newDesignations = append(newDesignations, designationHoD)
+	}
+
+	faculty.Designations = newDesignations
+	faculty.Version++
+	err = putCatalogState(ctx, facultyObjectType, facultyID, faculty)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Set designations of faculty %s: %s", facultyID, strings.Join(newDesignations, ", "))
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetJointAppointments replaces the departments, other than its own, a faculty is appointed to, given as a JSON list
+// A faculty jointly appointed to a department can teach and co-teach its courses and head it
+func (s *StudentRecordContract) SetJointAppointments(ctx contractapi.TransactionContextInterface, facultyID string, departmentsJSON string) error {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can set joint appointments")
+	}
+
+	var departmentIDs []string
+	if err := json.Unmarshal([]byte(departmentsJSON), &departmentIDs); err != nil {
+		return fmt.Errorf("unmarhsal error")
+	}
+
+	var faculty Faculty
+	exists, err := readEntity(ctx, facultyObjectType, facultyID, &faculty)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("Faculty with ID %s does not exist", facultyID)
+	}
+
+	jointDepartments := make([]string, 0, len(departmentIDs))
+	for _, departmentID := range departmentIDs {
+		var department Department
+		departmentExists, err := readEntity(ctx, departmentObjectType, departmentID, &department)
+		if err != nil {
+			return err
+		}
+		if !departmentExists {
+			return fmt.Errorf("Department ID %s is not valid", departmentID)
+		}
+		if departmentID == faculty.DepartmentID {
+			return fmt.Errorf("Department %s is the own department of faculty %s", departmentID, facultyID)
+		}
+		if contains(jointDepartments, departmentID) {
+			return fmt.Errorf("Department %s is listed more than once", departmentID)
+		}
+		// Existing appointments to an archived department can be kept, but not new ones made
+		if department.Archived && !contains(faculty.JointDepartments, departmentID) {
+			return fmt.Errorf("Department %s is archived", departmentID)
+		}
+		jointDepartments = append(jointDepartments, departmentID)
+	}
+
+	updatedFaculty := faculty
+	updatedFaculty.JointDepartments = jointDepartments
+	err = ensureAppointmentsCoverDuties(ctx, updatedFaculty)
+	if err != nil {
+		return err
+	}
+
+	updatedFaculty.Version++
+	err = putCatalogState(ctx, facultyObjectType, facultyID, updatedFaculty)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Set joint appointments of faculty %s: %s", facultyID, strings.Join(jointDepartments, ", "))
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetDepartmentHead makes a faculty appointed to a department its head, taking the HoD designation from the previous head
+func (s *StudentRecordContract) SetDepartmentHead(ctx contractapi.TransactionContextInterface, departmentID string, facultyID string) error {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can set department heads")
+	}
+
+	var department Department
+	exists, err := readEntity(ctx, departmentObjectType, departmentID, &department)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("Department with ID %s does not exist", departmentID)
+	}
+	if department.Archived {
+		return fmt.Errorf("Department %s is archived", departmentID)
+	}
+	var faculty Faculty
+	facultyExists, err := readEntity(ctx, facultyObjectType, facultyID, &faculty)
+	if err != nil {
+		return err
+	}
+	if !facultyExists {
+		return fmt.Errorf("Faculty ID %s is not valid", facultyID)
+	}
+	if faculty.Archived {
+		return fmt.Errorf("Faculty %s is archived", facultyID)
+	}
+	if !inDepartment(faculty, departmentID) {
+		return fmt.Errorf("Faculty with ID %s is not associated with department %s", facultyID, departmentID)
+	}
+	if department.HeadID == facultyID {
+		return fmt.Errorf("Faculty %s already heads department %s", facultyID, departmentID)
+	}
+
+	previousHeadID := department.HeadID
+	department.HeadID = facultyID
+	department.Version++
+	err = putCatalogState(ctx, departmentObjectType, departmentID, department)
+	if err != nil {
+		return err
+	}
+
+	// The previous head keeps the HoD designation only if they head another department
+	// The department is read back from the ledger with its previous head, as this transaction has not committed yet
+	if previousHeadID != "" {
+		var previousHead Faculty
+		headExists, err := readEntity(ctx, facultyObjectType, previousHeadID, &previousHead)
+		if err != nil {
+			return err
+		}
+		otherDepartments, err := headedDepartments(ctx, previousHeadID)
+		if err != nil {
+			return err
+		}
+		if headExists && !containsOtherThan(otherDepartments, departmentID) {
+			err = s.setHoDDesignation(ctx, previousHead, false)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if !contains(faculty.Designations, designationHoD) {
+		err = s.setHoDDesignation(ctx, faculty, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Set head of department %s: %s", departmentID, facultyID)
+	if previousHeadID != "" {
+		entry += fmt.Sprintf(" (previously %s)", previousHeadID)
+	}
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetCourseCoInstructors replaces the faculty teaching a course along with its instructor, given as a JSON list
+// Co-instructors must be appointed to the department of the course
+func (s *StudentRecordContract) SetCourseCoInstructors(ctx contractapi.TransactionContextInterface, courseID string, facultyIDsJSON string) error {
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) && !s.isFaculty(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin or faculty can set co-instructors")
+	}
+
+	var facultyIDs []string
+	if err := json.Unmarshal([]byte(facultyIDsJSON), &facultyIDs); err != nil {
+		return fmt.Errorf("unmarhsal error")
+	}
+
+	var course Course
+	exists, err := readEntity(ctx, courseObjectType, courseID, &course)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("Course with ID %s does not exist", courseID)
+	}
+
+	coInstructors := make([]string, 0, len(facultyIDs))
+	for _, facultyID := range facultyIDs {
+		var faculty Faculty
+		facultyExists, err := readEntity(ctx, facultyObjectType, facultyID, &faculty)
+		if err != nil {
+			return err
+		}
+		if !facultyExists {
+			return fmt.Errorf("Faculty ID %s is not valid", facultyID)
+		}
+		if facultyID == course.FacultyID {
+			return fmt.Errorf("Faculty %s is the instructor of course %s", facultyID, courseID)
+		}
+		if contains(coInstructors, facultyID) {
+			return fmt.Errorf("Faculty %s is listed more than once", facultyID)
+		}
+		if faculty.Archived && !contains(course.CoInstructors, facultyID) {
+			return fmt.Errorf("Faculty %s is archived", facultyID)
+		}
+		if !inDepartment(faculty, course.DepartmentID) {
+			return fmt.Errorf("Faculty with ID %s is not associated with department %s", facultyID, course.DepartmentID)
+		}
+		coInstructors = append(coInstructors, facultyID)
+	}
+
+	course.CoInstructors = coInstructors
+	course.Version++
+	err = putCatalogState(ctx, courseObjectType, courseID, course)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Set co-instructors of course %s: %s", courseID, strings.Join(coInstructors, ", "))
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetDepartmentFaculty returns the faculty of a department, its own and jointly appointed, ordered by ID
+func (s *StudentRecordContract) GetDepartmentFaculty(ctx contractapi.TransactionContextInterface, departmentID string) ([]Faculty, error) {
+	departmentJSON, err := getEntityState(ctx, departmentObjectType, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	if departmentJSON == nil {
+		return nil, fmt.Errorf("Department with ID %s does not exist", departmentID)
+	}
+
+	faculties := make([]Faculty, 0)
+	err = forEachEntity(ctx, facultyObjectType, func(value []byte) error {
+		var faculty Faculty
+		if err := json.Unmarshal(value, &faculty); err != nil {
+			return err
+		}
+		if inDepartment(faculty, departmentID) {
+			faculties = append(faculties, faculty)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return faculties, nil
+}
+
+// GetFacultyByDesignation returns the faculty holding a designation, ordered by ID
+func (s *StudentRecordContract) GetFacultyByDesignation(ctx contractapi.TransactionContextInterface, designation string) ([]Faculty, error) {
+	if !contains(facultyDesignations, designation) {
+		return nil, fmt.Errorf("Invalid designation %s: must be one of %v", designation, facultyDesignations)
+	}
+
+	faculties := make([]Faculty, 0)
+	err := forEachEntity(ctx, facultyObjectType, func(value []byte) error {
+		var faculty Faculty
+		if err := json.Unmarshal(value, &faculty); err != nil {
+			return err
+		}
+		if contains(faculty.Designations, designation) {
+			faculties = append(faculties, faculty)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return faculties, nil
+}
+
+// setHoDDesignation gives or takes away the HoD designation of a faculty
+func (s *StudentRecordContract) setHoDDesignation(ctx contractapi.TransactionContextInterface, faculty Faculty, head bool) error {
+	designations := make([]string, 0, len(faculty.Designations)+1)
+	for _, designation := range faculty.Designations {
+		if designation != designationHoD {
+			designations = append(designations, designation)
+		}
+	}
+	if head {
+		designations = append(designations, designationHoD)
+	}
+
+	faculty.Designations = designations
+	faculty.Version++
+	return putCatalogState(ctx, facultyObjectType, faculty.FacultyID, faculty)
+}
+
+// ensureAppointmentsCoverDuties checks that a faculty is still appointed to the departments of the courses they teach and head
+func ensureAppointmentsCoverDuties(ctx contractapi.TransactionContextInterface, faculty Faculty) error {
+	err := forEachEntity(ctx, courseObjectType, func(value []byte) error {
+		var course Course
+		if err := json.Unmarshal(value, &course); err != nil {
+			return err
+		}
+		if teachesCourse(course, faculty.FacultyID) && !inDepartment(faculty, course.DepartmentID) {
+			return fmt.Errorf("Faculty %s teaches courses of department %s, reassign them before changing the appointments", faculty.FacultyID, course.DepartmentID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	departmentIDs, err := headedDepartments(ctx, faculty.FacultyID)
+	if err != nil {
+		return err
+	}
+	for _, departmentID := range departmentIDs {
+		if !inDepartment(faculty, departmentID) {
+			return fmt.Errorf("Faculty %s heads department %s, appoint another head before changing the appointments", faculty.FacultyID, departmentID)
+		}
+	}
+	return nil
+}
+
+// headedDepartments returns the departments a faculty heads on the ledger, ordered by ID
+func headedDepartments(ctx contractapi.TransactionContextInterface, facultyID string) ([]string, error) {
+	departmentIDs := make([]string, 0)
+	if facultyID == "" {
+		return departmentIDs, nil
+	}
+	err := forEachEntity(ctx, departmentObjectType, func(value []byte) error {
+		var department Department
+		if err := json.Unmarshal(value, &department); err != nil {
+			return err
+		}
+		if department.HeadID == facultyID {
+			departmentIDs = append(departmentIDs, department.DepartmentID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return departmentIDs, nil
+}
+
+// containsOtherThan reports whether the list has an item other than the given one
+func containsOtherThan(list []string, item string) bool {
+	for _, value := range list {
+		if value != item {
+			return true
+		}
+	}
+	return false
+}
+
+// inDepartment reports whether a faculty belongs to a department or is jointly appointed to it
+func inDepartment(faculty Faculty, departmentID string) bool {
+	return faculty.DepartmentID == departmentID || contains(faculty.JointDepartments, departmentID)
+}
+
+// teachesCourse reports whether a faculty is the instructor or a co-instructor of a course
+func teachesCourse(course Course, facultyID string) bool {
+	return course.FacultyID == facultyID || contains(course.CoInstructors, facultyID)
+}
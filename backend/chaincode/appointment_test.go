@@ -0,0 +1,113 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// faculty returns a faculty record
+func (c *testContract) faculty(facultyID string) Faculty {
+	c.t.Helper()
+	var faculty Faculty
+	c.query(&faculty, "GetFaculty", facultyID)
+	return faculty
+}
+
+func TestSetFacultyDesignations(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	c.mustFail("Invalid designation dean", "SetFacultyDesignations", "F1", `["dean"]`)
+	c.mustFail("given with SetDepartmentHead", "SetFacultyDesignations", "F1", `["HoD"]`)
+	c.mustFail("listed more than once", "SetFacultyDesignations", "F1", `["advisor","advisor"]`)
+	c.mustFail("does not exist", "SetFacultyDesignations", "F9", `["advisor"]`)
+	c.mustInvoke("SetFacultyDesignations", "F1", `["professor","advisor"]`)
+
+	// A department head keeps the HoD designation when the others are replaced
+	c.mustInvoke("SetDepartmentHead", "CSE", "F1")
+	c.mustInvoke("SetFacultyDesignations", "F1", `["professor"]`)
+	if designations := strings.Join(c.faculty("F1").Designations, ","); designations != "professor,HoD" {
+		t.Fatalf("designations of F1 are %s, want professor,HoD", designations)
+	}
+
+	var faculty []Faculty
+	c.query(&faculty, "GetFacultyByDesignation", designationHoD)
+	if len(faculty) != 1 || faculty[0].FacultyID != "F1" {
+		t.Fatalf("HoD faculty are %+v", faculty)
+	}
+	c.mustFail("Invalid designation dean", "GetFacultyByDesignation", "dean")
+}
+
+func TestSetJointAppointments(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddDepartment", "EE", "Electrical")
+	c.mustInvoke("AddFaculty", "F2", "Ravi Kumar", "EE")
+
+	// Faculty teach the courses of the departments they are appointed to
+	c.mustFail("not associated with department CSE", "AddCourse", "EE101", "Circuits", "4", "CSE", "F2", "", "2024", "1", "30")
+	c.mustFail("is the own department of faculty F2", "SetJointAppointments", "F2", `["EE"]`)
+	c.mustFail("Department ID ME is not valid", "SetJointAppointments", "F2", `["ME"]`)
+	c.mustInvoke("SetJointAppointments", "F2", `["CSE"]`)
+	c.mustInvoke("AddCourse", "EE101", "Circuits", "4", "CSE", "F2", "", "2024", "1", "30")
+	c.mustFail("teaches courses of department CSE, reassign them before changing the appointments", "SetJointAppointments", "F2", `[]`)
+
+	var faculty []Faculty
+	c.query(&faculty, "GetDepartmentFaculty", "CSE")
+	if len(faculty) != 2 || faculty[0].FacultyID != "F1" || faculty[1].FacultyID != "F2" {
+		t.Fatalf("faculty of CSE are %+v", faculty)
+	}
+	c.mustFail("does not exist", "GetDepartmentFaculty", "ME")
+}
+
+func TestSetDepartmentHead(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddDepartment", "EE", "Electrical")
+	c.mustInvoke("AddFaculty", "F2", "Ravi Kumar", "EE")
+
+	c.mustFail("not associated with department CSE", "SetDepartmentHead", "CSE", "F2")
+	c.mustFail("Faculty ID F9 is not valid", "SetDepartmentHead", "CSE", "F9")
+	c.mustInvoke("SetJointAppointments", "F1", `["EE"]`)
+	c.mustInvoke("SetJointAppointments", "F2", `["CSE"]`)
+	c.mustInvoke("SetDepartmentHead", "CSE", "F1")
+	c.mustFail("already heads department CSE", "SetDepartmentHead", "CSE", "F1")
+	c.mustInvoke("SetDepartmentHead", "EE", "F1")
+	c.mustFail("heads department EE, appoint another head before changing the appointments", "SetJointAppointments", "F1", `[]`)
+
+	// The previous head keeps the HoD designation while heading another department
+	c.mustInvoke("SetDepartmentHead", "CSE", "F2")
+	if !contains(c.faculty("F1").Designations, designationHoD) || !contains(c.faculty("F2").Designations, designationHoD) {
+		t.Fatalf("F1 is %+v and F2 is %+v, want both HoD", c.faculty("F1"), c.faculty("F2"))
+	}
+	c.mustInvoke("SetDepartmentHead", "EE", "F2")
+	if contains(c.faculty("F1").Designations, designationHoD) {
+		t.Fatalf("F1 is %+v, want no longer HoD", c.faculty("F1"))
+	}
+
+	var department Department
+	c.query(&department, "GetDepartment", "CSE")
+	if department.HeadID != "F2" || department.Version != 2 {
+		t.Fatalf("department is %+v", department)
+	}
+}
+
+func TestSetCourseCoInstructors(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddDepartment", "EE", "Electrical")
+	c.mustInvoke("AddFaculty", "F2", "Ravi Kumar", "EE")
+
+	c.mustFail("not associated with department CSE", "SetCourseCoInstructors", "CS101", `["F2"]`)
+	c.mustInvoke("SetJointAppointments", "F2", `["CSE"]`)
+	c.mustFail("Faculty F1 is the instructor of course CS101", "SetCourseCoInstructors", "CS101", `["F1"]`)
+	c.mustFail("listed more than once", "SetCourseCoInstructors", "CS101", `["F2","F2"]`)
+	c.mustInvoke("SetCourseCoInstructors", "CS101", `["F2"]`)
+
+	var courses []Course
+	c.query(&courses, "GetCoursesByFacultyID", "F2")
+	if len(courses) != 1 || courses[0].CourseID != "CS101" || strings.Join(courses[0].CoInstructors, ",") != "F2" {
+		t.Fatalf("courses of F2 are %+v", courses)
+	}
+	c.mustFail("teaches courses of department CSE", "SetJointAppointments", "F2", `[]`)
+}
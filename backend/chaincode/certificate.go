@@ -3,21 +3,51 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 )
 
 // Certificate represents a certificate associated with an extracurricular activity
 type Certificate struct {
-	ActivityID string `json:"activityID"` // ID of the extracurricular activity
-	Key        string `json:"key"`        // Key associated with the certificate
+	ActivityID      string `json:"activityID"`      // ID of the extracurricular activity
+	Key             string `json:"key"`             // Key associated with the certificate
+	SHA256          string `json:"sha256"`          // Hex encoded SHA-256 digest of the certificate file
+	Size            int    `json:"size"`            // Size of the certificate file in bytes
+	MimeType        string `json:"mimeType"`        // MIME type of the certificate file
+	IssuerFacultyID string `json:"issuerFacultyID"` // Faculty who issued the certificate
+	UploadedAt      string `json:"uploadedAt"`      // Time the certificate was recorded on the ledger
+	Status          string `json:"status"`          // submitted, approved or rejected
+	ReviewedBy      string `json:"reviewedBy"`      // Faculty who approved or rejected the certificate
+	ReviewComment   string `json:"reviewComment"`   // Comment given with the review
+	ReviewedAt      string `json:"reviewedAt"`      // Time the certificate was reviewed
 }
 
-// AddCertificateForStudent adds a certificate for a given student ID
-func (s *StudentRecordContract) AddCertificateForStudent(ctx contractapi.TransactionContextInterface, studentID string, activityID string, key string) error {
+// Certificate review statuses
+const (
+	certificateSubmitted = "submitted"
+	certificateApproved  = "approved"
+	certificateRejected  = "rejected"
+)
+
+// PendingCertificate is a submitted certificate awaiting review
+type PendingCertificate struct {
+	StudentID   string      `json:"studentID"`
+	StudentName string      `json:"studentName"`
+	Certificate Certificate `json:"certificate"`
+}
+
+// isApproved reports whether a certificate has been approved.
+// Certificates recorded before the review workflow are marked approved when their enrollment is upgraded, see upgradeEnrollmentV1
+func (certificate Certificate) isApproved() bool {
+	return certificate.Status == certificateApproved
+}
+
+// AddCertificateForStudent submits a certificate for a given student ID along with the digest of the certificate file
+// The certificate must be approved by the faculty in charge of the activity before it is shown or can be verified
+func (s *StudentRecordContract) AddCertificateForStudent(ctx contractapi.TransactionContextInterface, studentID string, activityID string, key string, sha256Hash string, size int, mimeType string, facultyID string) error {
 	// Check if the student exists
-	studentKey := fmt.Sprintf("STUDENT-%s", studentID)
-	studentJSON, err := ctx.GetStub().GetState(studentKey)
+	studentJSON, err := getEntityState(ctx, studentObjectType, studentID)
 	if err != nil {
 		return err
 	}
@@ -25,49 +55,180 @@ func (s *StudentRecordContract) AddCertificateForStudent(ctx contractapi.Transac
 		return fmt.Errorf("Student with ID %s does not exist", studentID)
 	}
 
+	// Check if the activity exists
+	activity, err := s.GetExtracurricularActivity(ctx, activityID)
+	if err != nil {
+		return err
+	}
+
+	// Only the faculty in charge of the activity can issue its certificates
+	if facultyID == "" {
+		facultyID = activity.FacultyID
+	}
+	if facultyID != activity.FacultyID {
+		return fmt.Errorf("Faculty %s is not in charge of extracurricular activity %s", facultyID, activityID)
+	}
+
+	// Validate the file details
+	fileHash, err := normalizeDocumentHash(sha256Hash)
+	if err != nil {
+		return err
+	}
+	if size <= 0 {
+		return fmt.Errorf("Certificate file size must be positive")
+	}
+	if mimeType == "" {
+		return fmt.Errorf("Certificate MIME type is required")
+	}
+
+	uploadedAt, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
 	// Fetch the student's existing enrollment
 	existingEnrollment, err := s.GetEnrollment(ctx, studentID)
 	if err != nil {
 		return err
 	}
 
-	// Find if the activityID exists in the student's extracurricular activities
-	var activityIndex int
-	activityFound := false
-	for i, activity := range existingEnrollment.Certificates {
-		if activity.ActivityID == activityID {
-			activityFound = true
-			activityIndex = i
-			break
-		}
+	newCertificate := Certificate{
+		ActivityID:      activityID,
+		Key:             key,
+		SHA256:          fileHash,
+		Size:            size,
+		MimeType:        mimeType,
+		IssuerFacultyID: facultyID,
+		UploadedAt:      uploadedAt,
+		Status:          certificateSubmitted,
 	}
 
-	// If activityID exists, update the key; otherwise, add a new certificate
-	if activityFound {
-		existingEnrollment.Certificates[activityIndex].Key = key
-	} else {
-		// Create a new certificate
-		newCertificate := Certificate{
-			ActivityID: activityID,
-			Key:        key,
-		}
+	// Check if the student has attended the activity
+	participation, registered := participationFor(existingEnrollment, activityID)
+	if !registered {
+		return fmt.Errorf("Student %s has not registered for extracurricular activity %s", studentID, activityID)
+	}
+	if participation.Status != participationAttended {
+		return fmt.Errorf("Student %s has not been marked as attended for extracurricular activity %s", studentID, activityID)
+	}
+
+	// Find if a certificate has already been submitted for the activity
+	activityIndex := certificateIndex(existingEnrollment.Certificates, activityID)
 
+	// An approved certificate cannot be replaced; a submitted or rejected one is replaced by the new submission
+	if activityIndex >= 0 {
+		if existingEnrollment.Certificates[activityIndex].isApproved() {
+			return fmt.Errorf("Certificate for student %s and activity %s has already been approved", studentID, activityID)
+		}
+		existingEnrollment.Certificates[activityIndex] = newCertificate
+	} else {
 		// Add the certificate to the student's enrollment
 		existingEnrollment.Certificates = append(existingEnrollment.Certificates, newCertificate)
 	}
 
-	// Update the enrollment mapping
-	enrollmentMapping[studentID] = existingEnrollment
+	// Update the enrollment in the ledger
+	enrollmentJSON, _ := json.Marshal(existingEnrollment)
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Submitted certificate %s (sha256 %s) for student %s", key, fileHash, studentID)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReviewCertificate approves or rejects a submitted certificate; decision is approved or rejected
+// Only the faculty in charge of the activity can review its certificates, and a rejection needs a comment. The reviewer
+// is the faculty named by the facultyID attribute of the caller's certificate
+func (s *StudentRecordContract) ReviewCertificate(ctx contractapi.TransactionContextInterface, studentID string, activityID string, decision string, comment string) error {
+	caller := ctx.GetClientIdentity()
+	if !s.isFaculty(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only faculty can review certificates")
+	}
+	facultyID, found := s.callerFacultyID(ctx, caller)
+	if !found {
+		return fmt.Errorf("Unauthorized: the caller's certificate has no facultyID attribute")
+	}
+
+	if decision != certificateApproved && decision != certificateRejected {
+		return fmt.Errorf("Invalid decision %s: must be %s or %s", decision, certificateApproved, certificateRejected)
+	}
+	if decision == certificateRejected && comment == "" {
+		return fmt.Errorf("A comment is required to reject a certificate")
+	}
+
+	// Check that the reviewer is in charge of the activity
+	activity, err := s.GetExtracurricularActivity(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	if facultyID != activity.FacultyID {
+		return fmt.Errorf("Faculty %s is not in charge of extracurricular activity %s", facultyID, activityID)
+	}
+
+	existingEnrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return err
+	}
+
+	index := certificateIndex(existingEnrollment.Certificates, activityID)
+	if index < 0 {
+		return fmt.Errorf("Certificate not found for student %s and activity %s", studentID, activityID)
+	}
+	certificate := existingEnrollment.Certificates[index]
+	if certificate.Status != certificateSubmitted {
+		return fmt.Errorf("Certificate for student %s and activity %s is not awaiting review", studentID, activityID)
+	}
+
+	reviewedAt, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	certificate.Status = decision
+	certificate.ReviewedBy = facultyID
+	certificate.ReviewComment = comment
+	certificate.ReviewedAt = reviewedAt
+	if decision == certificateApproved {
+		certificate.IssuerFacultyID = facultyID
+	}
+	existingEnrollment.Certificates[index] = certificate
 
 	// Update the enrollment in the ledger
 	enrollmentJSON, _ := json.Marshal(existingEnrollment)
-	err = ctx.GetStub().PutState(fmt.Sprintf("ENROLLMENT-%s", studentID), enrollmentJSON)
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
 	if err != nil {
 		return err
 	}
 
+	// Record an approved certificate for public verification
+	if decision == certificateApproved {
+		err = s.putVerificationRecord(ctx, VerificationRecord{
+			CredentialID:   fmt.Sprintf("CERTIFICATE-%s-%s", studentID, activityID),
+			CredentialType: credentialTypeCertificate,
+			StudentID:      studentID,
+			StudentName:    existingEnrollment.Name,
+			Details: map[string]string{
+				"activityID":      activityID,
+				"key":             certificate.Key,
+				"mimeType":        certificate.MimeType,
+				"issuerFacultyID": facultyID,
+			},
+			DocumentHashes: []string{certificate.SHA256},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	// Record the ledger update
-	entry := fmt.Sprintf("Added new certificate %s for student %s", key, studentID)
+	entry := fmt.Sprintf("Certificate %s for student %s %s by faculty %s", certificate.Key, studentID, decision, facultyID)
 	err = s.recordLedgerUpdate(ctx, entry)
 	if err != nil {
 		return err
@@ -76,7 +237,7 @@ func (s *StudentRecordContract) AddCertificateForStudent(ctx contractapi.Transac
 	return nil
 }
 
-// GetCertificateForActivityAndStudent retrieves the certificate for a specific activity and student
+// GetCertificateForActivityAndStudent retrieves the approved certificate for a specific activity and student
 func (s *StudentRecordContract) GetCertificateForActivityAndStudent(ctx contractapi.TransactionContextInterface, studentID string, activityID string) (*Certificate, error) {
 	// Check if the student's enrollment record exists
 	enrollment, err := s.GetEnrollment(ctx, studentID)
@@ -84,13 +245,100 @@ func (s *StudentRecordContract) GetCertificateForActivityAndStudent(ctx contract
 		return nil, err
 	}
 
-	// Iterate through certificates in the enrollment record
+	// Only approved certificates are shown
+	index := certificateIndex(enrollment.Certificates, activityID)
+	if index < 0 || !enrollment.Certificates[index].isApproved() {
+		return nil, fmt.Errorf("Certificate not found for student %s and activity %s", studentID, activityID)
+	}
+
+	return &enrollment.Certificates[index], nil
+}
+
+// GetCertificateSubmission retrieves the certificate submitted for a specific activity and student, whatever its status
+func (s *StudentRecordContract) GetCertificateSubmission(ctx contractapi.TransactionContextInterface, studentID string, activityID string) (*Certificate, error) {
+	enrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := certificateIndex(enrollment.Certificates, activityID)
+	if index < 0 {
+		return nil, fmt.Errorf("Certificate not found for student %s and activity %s", studentID, activityID)
+	}
+
+	return &enrollment.Certificates[index], nil
+}
+
+// GetApprovedCertificates returns the approved certificates of a student
+func (s *StudentRecordContract) GetApprovedCertificates(ctx contractapi.TransactionContextInterface, studentID string) ([]Certificate, error) {
+	enrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates := make([]Certificate, 0)
 	for _, certificate := range enrollment.Certificates {
-		if certificate.ActivityID == activityID {
-			return &certificate, nil
+		if certificate.isApproved() {
+			certificates = append(certificates, certificate)
+		}
+	}
+
+	return certificates, nil
+}
+
+// GetPendingCertificatesForFaculty returns the submitted certificates awaiting review by a faculty
+func (s *StudentRecordContract) GetPendingCertificatesForFaculty(ctx contractapi.TransactionContextInterface, facultyID string) ([]PendingCertificate, error) {
+	_, err := s.GetFaculty(ctx, facultyID)
+	if err != nil {
+		return nil, err
+	}
+
+	activities, err := s.GetExtracurricularActivitiesByFacultyID(ctx, facultyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only students marked as attended can submit certificates, so only they are checked
+	pending := make([]PendingCertificate, 0)
+	for _, activity := range activities {
+		roster, err := getActivityRoster(ctx, activity.ActivityID)
+		if err != nil {
+			return nil, err
+		}
+		for _, studentID := range rosterStudentIDs(roster, participationAttended) {
+			enrollment, err := s.GetEnrollment(ctx, studentID)
+			if err != nil {
+				return nil, err
+			}
+			index := certificateIndex(enrollment.Certificates, activity.ActivityID)
+			if index < 0 || enrollment.Certificates[index].Status != certificateSubmitted {
+				continue
+			}
+			pending = append(pending, PendingCertificate{
+				StudentID:   studentID,
+				StudentName: enrollment.Name,
+				Certificate: enrollment.Certificates[index],
+			})
 		}
 	}
 
-	// If no certificate is found for the specified activity ID, return an error
-	return nil, fmt.Errorf("Certificate not found for student %s and activity %s", studentID, activityID)
+	// Oldest submissions first
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].Certificate.UploadedAt != pending[j].Certificate.UploadedAt {
+			return pending[i].Certificate.UploadedAt < pending[j].Certificate.UploadedAt
+		}
+		return pending[i].StudentID < pending[j].StudentID
+	})
+
+	return pending, nil
+}
+
+// certificateIndex returns the index of the certificate for an activity, or -1 if there is none
+func certificateIndex(certificates []Certificate, activityID string) int {
+	for i, certificate := range certificates {
+		if certificate.ActivityID == activityID {
+			return i
+		}
+	}
+	return -1
 }
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const testCertificateHash = "ee0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcd"
+
+// attendActivity adds activity A1 in charge of faculty F1 on 1 January 2030 and marks student S1 as attended
+func (c *testContract) attendActivity() {
+	c.t.Helper()
+	c.mustInvoke("AddExtracurricularActivity", "A1", "Chess", "Inter-hostel chess", "Hall 1", "01012030", "10", "F1")
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S1", "A1")
+	c.at("02012030")
+	c.as(map[string]string{"facultyID": "F1"})
+	c.mustInvoke("MarkActivityAttendance", "A1", `[{"studentID":"S1","status":"attended","hours":2}]`)
+	c.as(nil)
+}
+
+func (c *testContract) certificateSubmission(studentID string, activityID string) Certificate {
+	c.t.Helper()
+	var certificate Certificate
+	c.query(&certificate, "GetCertificateSubmission", studentID, activityID)
+	return certificate
+}
+
+func TestAddCertificateForStudent(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.attendActivity()
+	c.mustInvoke("AddFaculty", "F2", "Second Faculty", "CSE")
+
+	c.mustFail("does not exist", "AddCertificateForStudent", "S9", "A1", "key1", testCertificateHash, "10", "application/pdf", "")
+	c.mustFail("not in charge of", "AddCertificateForStudent", "S1", "A1", "key1", testCertificateHash, "10", "application/pdf", "F2")
+	c.mustFail("not a hex encoded SHA-256 digest", "AddCertificateForStudent", "S1", "A1", "key1", "ee", "10", "application/pdf", "")
+	c.mustFail("size must be positive", "AddCertificateForStudent", "S1", "A1", "key1", testCertificateHash, "0", "application/pdf", "")
+	c.mustFail("MIME type is required", "AddCertificateForStudent", "S1", "A1", "key1", testCertificateHash, "10", "", "")
+
+	c.mustInvoke("AddCertificateForStudent", "S1", "A1", "key1", "EE"+testCertificateHash[2:], "10", "application/pdf", "")
+	c.restart()
+	certificate := c.certificateSubmission("S1", "A1")
+	want := Certificate{
+		ActivityID:      "A1",
+		Key:             "key1",
+		SHA256:          testCertificateHash,
+		Size:            10,
+		MimeType:        "application/pdf",
+		IssuerFacultyID: "F1",
+		UploadedAt:      certificate.UploadedAt,
+		Status:          certificateSubmitted,
+	}
+	if certificate != want {
+		t.Fatalf("certificate is %+v, want %+v", certificate, want)
+	}
+	if certificate.UploadedAt[:10] != "2030-01-02" {
+		t.Fatalf("certificate was uploaded at %s, want the transaction time on 2030-01-02", certificate.UploadedAt)
+	}
+
+	// A certificate awaiting review can be replaced with another file
+	c.mustInvoke("AddCertificateForStudent", "S1", "A1", "key2", testFileHash, "20", "image/png", "F1")
+	if certificate := c.certificateSubmission("S1", "A1"); certificate.Key != "key2" || certificate.SHA256 != testFileHash || certificate.Size != 20 {
+		t.Fatalf("replaced certificate is %+v", certificate)
+	}
+	if certificates := c.enrollment("S1").Certificates; len(certificates) != 1 {
+		t.Fatalf("enrollment has certificates %+v, want only the replacement", certificates)
+	}
+}
+
+// review reviews the certificate of student S1 for activity A1 as a faculty
+func (c *testContract) review(facultyID string, decision string, comment string) (string, error) {
+	c.t.Helper()
+	c.as(map[string]string{"facultyID": facultyID})
+	defer c.as(nil)
+	return c.invoke("ReviewCertificate", "S1", "A1", decision, comment)
+}
+
+func TestReviewCertificate(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.attendActivity()
+	c.mustInvoke("AddFaculty", "F2", "Second Faculty", "CSE")
+	c.mustInvoke("AddCertificateForStudent", "S1", "A1", "key1", testCertificateHash, "10", "application/pdf", "")
+
+	// A submitted certificate is neither shown nor verifiable until it is approved
+	c.mustFail("Certificate not found", "GetCertificateForActivityAndStudent", "S1", "A1")
+	if result := c.verify("CERTIFICATE-S1-A1", ""); result.Verified {
+		t.Fatalf("submitted certificate verifies: %+v", result)
+	}
+	var pending []PendingCertificate
+	c.query(&pending, "GetPendingCertificatesForFaculty", "F1")
+	if len(pending) != 1 || pending[0].StudentID != "S1" || pending[0].StudentName != "Asha" || pending[0].Certificate.Key != "key1" {
+		t.Fatalf("pending certificates of F1 are %+v", pending)
+	}
+
+	failures := []struct {
+		facultyID, decision, comment, want string
+	}{
+		{"", certificateApproved, "", "no facultyID attribute"},
+		{"F2", certificateApproved, "", "not in charge of"},
+		{"F1", "ok", "", "Invalid decision"},
+		{"F1", certificateRejected, "", "comment is required"},
+	}
+	for _, failure := range failures {
+		if _, err := c.review(failure.facultyID, failure.decision, failure.comment); err == nil || !strings.Contains(err.Error(), failure.want) {
+			t.Fatalf("review by %q with %s failed with %v, want %q", failure.facultyID, failure.decision, err, failure.want)
+		}
+	}
+
+	if _, err := c.review("F1", certificateRejected, "blurry scan"); err != nil {
+		t.Fatal(err)
+	}
+	if certificate := c.certificateSubmission("S1", "A1"); certificate.Status != certificateRejected || certificate.ReviewedBy != "F1" || certificate.ReviewComment != "blurry scan" {
+		t.Fatalf("rejected certificate is %+v", certificate)
+	}
+	if _, err := c.review("F1", certificateApproved, ""); err == nil || !strings.Contains(err.Error(), "not awaiting review") {
+		t.Fatalf("review of a rejected certificate failed with %v", err)
+	}
+	c.query(&pending, "GetPendingCertificatesForFaculty", "F1")
+	if len(pending) != 0 {
+		t.Fatalf("pending certificates of F1 after the review are %+v", pending)
+	}
+
+	// The student submits the certificate again, and once approved it cannot be replaced
+	c.mustInvoke("AddCertificateForStudent", "S1", "A1", "key2", testFileHash, "10", "application/pdf", "")
+	if _, err := c.review("F1", certificateApproved, "clear copy"); err != nil {
+		t.Fatal(err)
+	}
+	c.restart()
+	var certificate Certificate
+	c.query(&certificate, "GetCertificateForActivityAndStudent", "S1", "A1")
+	if certificate.Status != certificateApproved || certificate.Key != "key2" || certificate.ReviewedAt == "" {
+		t.Fatalf("approved certificate is %+v", certificate)
+	}
+	var approved []Certificate
+	c.query(&approved, "GetApprovedCertificates", "S1")
+	if len(approved) != 1 || approved[0].Key != "key2" {
+		t.Fatalf("approved certificates are %+v", approved)
+	}
+	if result := c.verify("CERTIFICATE-S1-A1", testFileHash); !result.Verified || result.Record.Details["issuerFacultyID"] != "F1" {
+		t.Fatalf("approved certificate does not verify: %+v", result)
+	}
+	c.mustFail("already been approved", "AddCertificateForStudent", "S1", "A1", "key3", testCertificateHash, "10", "application/pdf", "")
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Club represents a club or other body that organizes extracurricular activities
+type Club struct {
+	ClubID              string   `json:"clubID"`
+	Name                string   `json:"name"`
+	Description         string   `json:"description"`
+	FacultyAdvisors     []string `json:"facultyAdvisors"`     // IDs of the faculty advising the club
+	StudentCoordinators []string `json:"studentCoordinators"` // IDs of the students coordinating the club
+	SchemaVersion       int      `json:"schemaVersion"`
+}
+
+// activityCategories are the categories an extracurricular activity can belong to
+var activityCategories = []string{"sports", "cultural", "technical", "NSS"}
+
+// AddClub adds a new club with its faculty advisors and student coordinators given as JSON lists of IDs
+func (s *StudentRecordContract) AddClub(ctx contractapi.TransactionContextInterface, clubID string, name string, description string, facultyAdvisorsJSON string, studentCoordinatorsJSON string) error {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can add clubs")
+	}
+
+	// Check if the club already exists
+	clubJSON, err := getEntityState(ctx, clubObjectType, clubID)
+	if err != nil {
+		return err
+	}
+	if clubJSON != nil {
+		return fmt.Errorf("Club with ID %s already exists", clubID)
+	}
+	if name == "" {
+		return fmt.Errorf("Club name is required")
+	}
+
+	club := Club{
+		ClubID:        clubID,
+		Name:          name,
+		Description:   description,
+		SchemaVersion: schemaVersion(clubObjectType),
+	}
+	err = s.setClubMembers(ctx, &club, facultyAdvisorsJSON, studentCoordinatorsJSON)
+	if err != nil {
+		return err
+	}
+
+	err = s.putClub(ctx, club)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Added new club: %s", clubID)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateClubMembers replaces the faculty advisors and student coordinators of a club
+func (s *StudentRecordContract) UpdateClubMembers(ctx contractapi.TransactionContextInterface, clubID string, facultyAdvisorsJSON string, studentCoordinatorsJSON string) error {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can update clubs")
+	}
+
+	club, err := s.GetClub(ctx, clubID)
+	if err != nil {
+		return err
+	}
+
+	err = s.setClubMembers(ctx, club, facultyAdvisorsJSON, studentCoordinatorsJSON)
+	if err != nil {
+		return err
+	}
+
+	err = s.putClub(ctx, *club)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Updated advisors and coordinators of club %s", clubID)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetClub retrieves a club by its ID from the ledger
+func (s *StudentRecordContract) GetClub(ctx contractapi.TransactionContextInterface, clubID string) (*Club, error) {
+	clubJSON, err := getEntityState(ctx, clubObjectType, clubID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read club with ID %s: %v", clubID, err)
+	}
+	if clubJSON == nil {
+		return nil, fmt.Errorf("Club with ID %s does not exist", clubID)
+	}
+
+	var club Club
+	err = json.Unmarshal(clubJSON, &club)
+	if err != nil {
+		return nil, err
+	}
+
+	return &club, nil
+}
+
+// GetAllClubs returns all the clubs in the ledger, ordered by ID
+func (s *StudentRecordContract) GetAllClubs(ctx contractapi.TransactionContextInterface) ([]Club, error) {
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(clubObjectType, []string{})
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	clubs := make([]Club, 0)
+	for iterator.HasNext() {
+		result, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		value, err := upgradeDocument(clubObjectType, result.Value)
+		if err != nil {
+			return nil, err
+		}
+		var club Club
+		if err := json.Unmarshal(value, &club); err != nil {
+			return nil, err
+		}
+		clubs = append(clubs, club)
+	}
+
+	return clubs, nil
+}
+
+// SetActivityClassification sets the organizing club, category and term of an activity
+// clubID may be empty for an activity that no club organizes; category is one of sports, cultural, technical or NSS
+func (s *StudentRecordContract) SetActivityClassification(ctx contractapi.TransactionContextInterface, activityID string, clubID string, category string, term string) error {
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) && !s.isFaculty(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin or faculty can classify activities")
+	}
+
+	activity, err := s.GetExtracurricularActivity(ctx, activityID)
+	if err != nil {
+		return err
+	}
+
+	if clubID != "" {
+		if _, err := s.GetClub(ctx, clubID); err != nil {
+			return err
+		}
+	}
+	if !contains(activityCategories, category) {
+		return fmt.Errorf("Invalid activity category %s: must be one of %v", category, activityCategories)
+	}
+	if term == "" {
+		return fmt.Errorf("Activity term is required")
+	}
+
+	activity.ClubID = clubID
+	activity.Category = category
+	activity.Term = term
+	activity.Version++
+	err = s.putExtracurricularActivity(ctx, activity)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Classified extracurricular activity %s as %s in term %s", activityID, category, term)
+	if clubID != "" {
+		entry += fmt.Sprintf(" organized by club %s", clubID)
+	}
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FilterExtracurricularActivities returns the activities matching a club, category and term, ordered by ID
+// An empty argument matches any value
+func (s *StudentRecordContract) FilterExtracurricularActivities(ctx contractapi.TransactionContextInterface, clubID string, category string, term string) ([]ExtracurricularActivity, error) {
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(activityObjectType, []string{})
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	activities := make([]ExtracurricularActivity, 0)
+	for iterator.HasNext() {
+		result, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		value, err := upgradeDocument(activityObjectType, result.Value)
+		if err != nil {
+			return nil, err
+		}
+		var activity ExtracurricularActivity
+		if err := json.Unmarshal(value, &activity); err != nil {
+			return nil, err
+		}
+
+		if (clubID == "" || activity.ClubID == clubID) && (category == "" || activity.Category == category) && (term == "" || activity.Term == term) {
+			activities = append(activities, activity)
+		}
+	}
+
+	return activities, nil
+}
+
+// GetActivitiesByClub returns the activities organized by a club
+func (s *StudentRecordContract) GetActivitiesByClub(ctx contractapi.TransactionContextInterface, clubID string) ([]ExtracurricularActivity, error) {
+	if _, err := s.GetClub(ctx, clubID); err != nil {
+		return nil, err
+	}
+	return s.FilterExtracurricularActivities(ctx, clubID, "", "")
+}
+
+// GetActivitiesByCategory returns the activities of a category
+func (s *StudentRecordContract) GetActivitiesByCategory(ctx contractapi.TransactionContextInterface, category string) ([]ExtracurricularActivity, error) {
+	if !contains(activityCategories, category) {
+		return nil, fmt.Errorf("Invalid activity category %s: must be one of %v", category, activityCategories)
+	}
+	return s.FilterExtracurricularActivities(ctx, "", category, "")
+}
+
+// GetActivitiesByTerm returns the activities held in a term
+func (s *StudentRecordContract) GetActivitiesByTerm(ctx contractapi.TransactionContextInterface, term string) ([]ExtracurricularActivity, error) {
+	if term == "" {
+		return nil, fmt.Errorf("Activity term is required")
+	}
+	return s.FilterExtracurricularActivities(ctx, "", "", term)
+}
+
+// setClubMembers validates and sets the faculty advisors and student coordinators of a club
+func (s *StudentRecordContract) setClubMembers(ctx contractapi.TransactionContextInterface, club *Club, facultyAdvisorsJSON string, studentCoordinatorsJSON string) error {
+	var facultyAdvisors []string
+	if err := json.Unmarshal([]byte(facultyAdvisorsJSON), &facultyAdvisors); err != nil {
+		return fmt.Errorf("unmarhsal error")
+	}
+	var studentCoordinators []string
+	if err := json.Unmarshal([]byte(studentCoordinatorsJSON), &studentCoordinators); err != nil {
+		return fmt.Errorf("unmarhsal error")
+	}
+
+	if len(facultyAdvisors) == 0 {
+		return fmt.Errorf("A club needs at least one faculty advisor")
+	}
+	for _, facultyID := range facultyAdvisors {
+		if _, err := s.GetFaculty(ctx, facultyID); err != nil {
+			return err
+		}
+	}
+	for _, studentID := range studentCoordinators {
+		if _, err := s.GetStudent(ctx, studentID); err != nil {
+			return err
+		}
+	}
+
+	club.FacultyAdvisors = append([]string{}, facultyAdvisors...)
+	club.StudentCoordinators = append([]string{}, studentCoordinators...)
+	return nil
+}
+
+// putClub stores a club in the ledger
+func (s *StudentRecordContract) putClub(ctx contractapi.TransactionContextInterface, club Club) error {
+	clubJSON, err := json.Marshal(club)
+	if err != nil {
+		return err
+	}
+	return putEntityState(ctx, clubObjectType, club.ClubID, clubJSON)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// activityIDs returns the IDs of the activities returned by a query, in order
+func (c *testContract) activityIDs(function string, args ...string) string {
+	c.t.Helper()
+	var activities []ExtracurricularActivity
+	c.query(&activities, function, args...)
+	activityIDs := make([]string, 0, len(activities))
+	for _, activity := range activities {
+		activityIDs = append(activityIDs, activity.ActivityID)
+	}
+	return strings.Join(activityIDs, ",")
+}
+
+func TestAddClub(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	c.mustFail("does not exist", "AddClub", "C1", "Robotics", "Robotics club", `["F9"]`, `[]`)
+	c.mustFail("at least one faculty advisor", "AddClub", "C1", "Robotics", "Robotics club", `[]`, `[]`)
+	c.mustFail("does not exist", "AddClub", "C1", "Robotics", "Robotics club", `["F1"]`, `["S9"]`)
+	c.mustFail("name is required", "AddClub", "C1", "", "Robotics club", `["F1"]`, `[]`)
+	c.mustFail("unmarhsal error", "AddClub", "C1", "Robotics", "Robotics club", `F1`, `[]`)
+	c.mustInvoke("AddClub", "C1", "Robotics", "Robotics club", `["F1"]`, `["S1"]`)
+	c.mustFail("already exists", "AddClub", "C1", "Robotics", "Robotics club", `["F1"]`, `["S1"]`)
+
+	c.mustInvoke("UpdateClubMembers", "C1", `["F1"]`, `[]`)
+	c.mustFail("at least one faculty advisor", "UpdateClubMembers", "C1", `[]`, `["S1"]`)
+	c.restart()
+	var clubs []Club
+	c.query(&clubs, "GetAllClubs")
+	if len(clubs) != 1 || clubs[0].Name != "Robotics" || strings.Join(clubs[0].FacultyAdvisors, ",") != "F1" || len(clubs[0].StudentCoordinators) != 0 {
+		t.Fatalf("clubs are %+v", clubs)
+	}
+}
+
+func TestActivityClassification(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddClub", "C1", "Robotics", "Robotics club", `["F1"]`, `[]`)
+	c.mustInvoke("AddExtracurricularActivity", "A1", "Line follower", "Robot race", "Lab 1", "01012030", "10", "F1")
+	c.mustInvoke("AddExtracurricularActivity", "A2", "Football", "Inter-hostel football", "Ground", "01012030", "10", "F1")
+	c.mustInvoke("AddExtracurricularActivity", "A3", "Drone show", "Robotics exhibition", "Ground", "01012030", "10", "F1")
+
+	c.mustFail("does not exist", "SetActivityClassification", "A1", "C9", "technical", "2029-30")
+	c.mustFail("Invalid activity category music", "SetActivityClassification", "A1", "C1", "music", "2029-30")
+	c.mustFail("term is required", "SetActivityClassification", "A1", "C1", "technical", "")
+	c.mustInvoke("SetActivityClassification", "A1", "C1", "technical", "2029-30")
+	c.mustInvoke("SetActivityClassification", "A2", "", "sports", "2029-30")
+	c.mustInvoke("SetActivityClassification", "A3", "C1", "cultural", "2030-31")
+
+	tests := []struct {
+		function string
+		args     []string
+		want     string
+	}{
+		{"FilterExtracurricularActivities", []string{"", "", ""}, "A1,A2,A3"},
+		{"FilterExtracurricularActivities", []string{"C1", "", "2029-30"}, "A1"},
+		{"FilterExtracurricularActivities", []string{"C1", "sports", ""}, ""},
+		{"GetActivitiesByClub", []string{"C1"}, "A1,A3"},
+		{"GetActivitiesByCategory", []string{"sports"}, "A2"},
+		{"GetActivitiesByTerm", []string{"2029-30"}, "A1,A2"},
+	}
+	for _, test := range tests {
+		if got := c.activityIDs(test.function, test.args...); got != test.want {
+			t.Errorf("%s%q returned %q, want %q", test.function, test.args, got, test.want)
+		}
+	}
+	c.mustFail("Invalid activity category", "GetActivitiesByCategory", "music")
+	c.mustFail("term is required", "GetActivitiesByTerm", "")
+}
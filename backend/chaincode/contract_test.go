@@ -0,0 +1,266 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// testContract invokes the contract through a MockStub, each invocation in a transaction of its own
+// The MockStub writes state as the transaction runs, unlike a peer, so writes are visible to the rest of the transaction
+type testContract struct {
+	t    *testing.T
+	stub *shimtest.MockStub
+	cc   *contractapi.ContractChaincode
+	now  time.Time // Time of the transactions, the time they run at if zero
+	txs  int
+}
+
+// clockStub gives the transactions of a testContract its time and arguments
+// The MockStub sets neither when a ContractChaincode is invoked directly
+type clockStub struct {
+	*shimtest.MockStub
+	args [][]byte
+	now  time.Time
+}
+
+func (stub *clockStub) GetArgs() [][]byte {
+	return stub.args
+}
+
+func (stub *clockStub) GetStringArgs() []string {
+	args := make([]string, 0, len(stub.args))
+	for _, arg := range stub.args {
+		args = append(args, string(arg))
+	}
+	return args
+}
+
+func (stub *clockStub) GetFunctionAndParameters() (string, []string) {
+	args := stub.GetStringArgs()
+	if len(args) == 0 {
+		return "", nil
+	}
+	return args[0], args[1:]
+}
+
+func (stub *clockStub) GetTxTimestamp() (*timestamppb.Timestamp, error) {
+	if stub.now.IsZero() {
+		return timestamppb.Now(), nil
+	}
+	return timestamppb.New(stub.now), nil
+}
+
+// DelPrivateData deletes private data, which the MockStub does not implement
+func (stub *clockStub) DelPrivateData(collection string, key string) error {
+	delete(stub.PvtState[collection], key)
+	return nil
+}
+
+// newTestContract returns a contract on an empty ledger, initialized with InitLedger
+func newTestContract(t *testing.T) *testContract {
+	t.Helper()
+	stub := shimtest.NewMockStub("student-record", nil)
+	c := &testContract{t: t, stub: stub}
+	c.restart()
+	c.as(nil)
+	c.mustInvoke("InitLedger")
+	return c
+}
+
+// restart replaces the chaincode with a new instance on the same ledger, like a peer restarting or another peer endorsing
+func (c *testContract) restart() {
+	c.t.Helper()
+	cc, err := contractapi.NewChaincode(new(StudentRecordContract))
+	if err != nil {
+		c.t.Fatalf("failed to create the chaincode: %v", err)
+	}
+	c.cc = cc
+}
+
+// as makes the following transactions be submitted by an identity with the certificate attributes
+func (c *testContract) as(attributes map[string]string) {
+	c.t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "user"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	if attributes != nil {
+		attributesJSON, err := json.Marshal(map[string]interface{}{"attrs": attributes})
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		template.ExtraExtensions = []pkix.Extension{{Id: []int{1, 2, 3, 4, 5, 6, 7, 8, 1}, Value: attributesJSON}}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	creator, err := proto.Marshal(&msp.SerializedIdentity{
+		Mspid:   "Org1MSP",
+		IdBytes: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	})
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.stub.Creator = creator
+}
+
+// at makes the following transactions run at noon, Indian time, of a DDMMYYYY date
+func (c *testContract) at(date string) {
+	c.t.Helper()
+	day, err := time.ParseInLocation(activityDateLayout, date, indianTimeZone)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.now = day.Add(12 * time.Hour)
+}
+
+// transient sets the transient data of the following transactions
+func (c *testContract) transient(data map[string]string) {
+	c.stub.TransientMap = make(map[string][]byte)
+	for key, value := range data {
+		c.stub.TransientMap[key] = []byte(value)
+	}
+}
+
+// invoke runs a transaction and returns its payload, or the error it failed with
+func (c *testContract) invoke(function string, args ...string) (string, error) {
+	c.txs++
+	txID := fmt.Sprintf("tx%d", c.txs)
+	invocation := [][]byte{[]byte(function)}
+	for _, arg := range args {
+		invocation = append(invocation, []byte(arg))
+	}
+
+	c.stub.MockTransactionStart(txID)
+	response := c.cc.Invoke(&clockStub{MockStub: c.stub, args: invocation, now: c.now})
+	c.stub.MockTransactionEnd(txID)
+	if response.Status != 200 {
+		return "", fmt.Errorf("%s", response.Message)
+	}
+	return string(response.Payload), nil
+}
+
+// mustInvoke runs a transaction that must succeed
+func (c *testContract) mustInvoke(function string, args ...string) string {
+	c.t.Helper()
+	payload, err := c.invoke(function, args...)
+	if err != nil {
+		c.t.Fatalf("%s%q failed: %v", function, args, err)
+	}
+	return payload
+}
+
+// mustFail runs a transaction that must fail with an error containing want
+func (c *testContract) mustFail(want string, function string, args ...string) {
+	c.t.Helper()
+	payload, err := c.invoke(function, args...)
+	if err == nil {
+		c.t.Fatalf("%s%q succeeded with %s, want an error containing %q", function, args, payload, want)
+	}
+	if !strings.Contains(err.Error(), want) {
+		c.t.Fatalf("%s%q failed with %q, want an error containing %q", function, args, err, want)
+	}
+}
+
+// query runs a transaction that must succeed and decodes its payload into value
+func (c *testContract) query(value interface{}, function string, args ...string) {
+	c.t.Helper()
+	payload := c.mustInvoke(function, args...)
+	if err := json.Unmarshal([]byte(payload), value); err != nil {
+		c.t.Fatalf("%s%q returned %s: %v", function, args, payload, err)
+	}
+}
+
+// context returns a transaction context on the ledger, to call the helpers of the contract directly
+// Writes made with it are only kept between MockTransactionStart and MockTransactionEnd
+func (c *testContract) context() *contractapi.TransactionContext {
+	ctx := new(contractapi.TransactionContext)
+	ctx.SetStub(c.stub)
+	return ctx
+}
+
+// enrollment returns the enrollment of a student
+func (c *testContract) enrollment(studentID string) Enrollment {
+	c.t.Helper()
+	var enrollment Enrollment
+	c.query(&enrollment, "GetEnrollment", studentID)
+	return enrollment
+}
+
+// addCatalog adds the CSE department with faculty F1, five courses over three semesters and student S1 in BTECH
+func (c *testContract) addCatalog() {
+	c.t.Helper()
+	c.mustInvoke("AddDepartment", "CSE", "Computer Science")
+	c.mustInvoke("AddFaculty", "F1", "Mitesh Khapra", "CSE")
+	c.mustInvoke("AddCourse", "CS101", "Programming", "37", "CSE", "F1", "", "2024", "1", "30")
+	c.mustInvoke("AddCourse", "CS102", "Data Structures", "37", "CSE", "F1", "", "2024", "1", "30")
+	c.mustInvoke("AddCourse", "CS201", "Algorithms", "37", "CSE", "F1", "", "2024", "2", "30")
+	c.mustInvoke("AddCourse", "CS202", "Operating Systems", "37", "CSE", "F1", "", "2024", "2", "30")
+	c.mustInvoke("AddCourse", "CS301", "Project", "40", "CSE", "F1", "", "2024", "3", "30")
+	c.mustInvoke("InitialEnrollment", "S1", "Asha", "BTECH", "CSE")
+}
+
+func TestLedgerIsNotSharedBetweenContracts(t *testing.T) {
+	first := newTestContract(t)
+	first.addCatalog()
+
+	second := newTestContract(t)
+	var departments []Department
+	second.query(&departments, "GetAllDepartments")
+	if len(departments) != 0 {
+		t.Fatalf("GetAllDepartments on a new ledger returned %v", departments)
+	}
+	var students []Student
+	second.query(&students, "GetAllStudents")
+	if len(students) != 0 {
+		t.Fatalf("GetAllStudents on a new ledger returned %v", students)
+	}
+	second.mustFail("does not exist", "GetFaculty", "F1")
+	second.mustInvoke("AddDepartment", "CSE", "Computer Science")
+}
+
+func TestRecordsAreReadFromTheLedger(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.restart()
+
+	var faculty Faculty
+	c.query(&faculty, "GetFaculty", "F1")
+	if faculty.DepartmentID != "CSE" {
+		t.Fatalf("faculty F1 is in department %q, want CSE", faculty.DepartmentID)
+	}
+	var courses []Course
+	c.query(&courses, "GetAllCourses")
+	if len(courses) != 5 || courses[0].CourseID != "CS101" {
+		t.Fatalf("GetAllCourses returned %v, want the 5 courses ordered by ID", courses)
+	}
+	var enrollments []Enrollment
+	c.query(&enrollments, "GetAllEnrollments")
+	if len(enrollments) != 1 || enrollments[0].StudentID != "S1" {
+		t.Fatalf("GetAllEnrollments returned %v, want the enrollment of S1", enrollments)
+	}
+	c.mustFail("already exists", "AddFaculty", "F1", "Mitesh Khapra", "CSE")
+}
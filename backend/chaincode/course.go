@@ -10,21 +10,24 @@ import (
 
 // Course represent all the information related to a course
 type Course struct {
-	CourseID     string `json:"courseID"`
-	CourseName   string `json:"name"`
-	Credits      int    `json:"credits"`
-	DepartmentID string `json:"department"`
-	FacultyID    string `json:"facultyID"`
-	Description  string `json:"description"`
-	AcademicYear int    `json:"academicYear"`
-	Semester     int    `json:"semester"`
-	MaxSeats     int    `json:"maxSeats"`
-	SeatsFilled  int    `json:"seatsFilled"`
+	CourseID      string   `json:"courseID"`
+	CourseName    string   `json:"name"`
+	Credits       int      `json:"credits"`
+	DepartmentID  string   `json:"department"`
+	FacultyID     string   `json:"facultyID"`
+	Description   string   `json:"description"`
+	AcademicYear  int      `json:"academicYear"`
+	Semester      int      `json:"semester"`
+	MaxSeats      int      `json:"maxSeats"`
+	SeatsFilled   int      `json:"seatsFilled"`
+	Archived      bool     `json:"archived"`      // Archived courses cannot be taken by students
+	Version       int      `json:"version"`       // Number of edits made to the course, seat counts excluded
+	CoInstructors []string `json:"coInstructors"` // Faculty teaching the course along with FacultyID
+	SchemaVersion int      `json:"schemaVersion"`
 }
 
-var courseMapping = make(map[string]Course)
-
-// AddCoursesToCurrentSemester adds courses to the current semester's enrollment
+// AddCoursesToCurrentSemester adds courses to the current semester's enrollment directly
+// Students register through SubmitCoursePlan and their advisor's approval; this is kept for admin registrations
 func (s *StudentRecordContract) AddCoursesToCurrentSemester(ctx contractapi.TransactionContextInterface, studentID string, coursesToAddjson string) error {
 
 	var coursesToAdd []string
@@ -32,10 +35,10 @@ func (s *StudentRecordContract) AddCoursesToCurrentSemester(ctx contractapi.Tran
 		return fmt.Errorf("unmarhsal error")
 	}
 
-	// Check if the caller is authorized (admin and faculty)
+	// Check if the caller is authorized (admin)
 	caller := ctx.GetClientIdentity()
-	if !s.isAdmin(ctx, caller) && !s.isFaculty(ctx, caller) {
-		return fmt.Errorf("Unauthorized: courses can only be added upon approval of admin and faculty both")
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can add courses directly, students submit a course plan for their advisor to approve")
 	}
 
 	// Fetch the student's existing enrollment
@@ -44,6 +47,40 @@ func (s *StudentRecordContract) AddCoursesToCurrentSemester(ctx contractapi.Tran
 		return err
 	}
 
+	// Check if the student is active
+	if err := ensureEnrollmentActive(existingEnrollment); err != nil {
+		return err
+	}
+
+	_, err = s.checkCoursesForCurrentSemester(ctx, existingEnrollment, coursesToAdd)
+	if err != nil {
+		return err
+	}
+
+	err = s.takeCourses(ctx, &existingEnrollment, coursesToAdd)
+	if err != nil {
+		return err
+	}
+
+	// Update the enrollment in the ledger
+	enrollmentJSON, _ := json.Marshal(existingEnrollment)
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Added courses to current semester for student %s: %s", studentID, strings.Join(coursesToAdd, ", "))
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkCoursesForCurrentSemester checks that a student can add courses to the current semester and returns their total credits
+func (s *StudentRecordContract) checkCoursesForCurrentSemester(ctx contractapi.TransactionContextInterface, existingEnrollment Enrollment, coursesToAdd []string) (int, error) {
 	// Check if the current semester exists in the enrollment
 	currentSemester := existingEnrollment.CurrentSemester
 
@@ -52,7 +89,7 @@ func (s *StudentRecordContract) AddCoursesToCurrentSemester(ctx contractapi.Tran
 		if semester != currentSemester {
 			for _, course := range coursesToAdd {
 				if contains(coursesTaken, course) {
-					return fmt.Errorf("Course %s has already been taken in semester %s", course, semester)
+					return 0, fmt.Errorf("Course %s has already been taken in semester %s, courses cannot be retaken and a failed course is cleared by updating its grade after re-examination", course, semester)
 				}
 			}
 		}
@@ -62,34 +99,46 @@ func (s *StudentRecordContract) AddCoursesToCurrentSemester(ctx contractapi.Tran
 	existingCourses := existingEnrollment.CoursesTaken[currentSemester]
 	for _, course := range coursesToAdd {
 		if contains(existingCourses, course) {
-			return fmt.Errorf("Course %s is already in the current semester's course list", course)
+			return 0, fmt.Errorf("Course %s is already in the current semester's course list", course)
 		}
 	}
 
 	// Calculate the total credits for the courses to add
 	totalCreditsToAdd := 0
-	for _, courseID := range coursesToAdd {
+	for i, courseID := range coursesToAdd {
+		if contains(coursesToAdd[:i], courseID) {
+			return 0, fmt.Errorf("Course %s is listed more than once", courseID)
+		}
 		course, err := s.GetCourse(ctx, courseID)
 		if err != nil {
-			return err
+			return 0, err
+		}
+		if course.Archived {
+			return 0, fmt.Errorf("Course %s is archived", courseID)
 		}
 		totalCreditsToAdd += course.Credits
 		// Check if there are seats available for the course
 		if course.SeatsFilled >= course.MaxSeats {
-			return fmt.Errorf("No seats available for course %s", courseID)
+			return 0, fmt.Errorf("No seats available for course %s", courseID)
 		}
 	}
 
 	// Check if the total credits exceed the maximum allowed credits per semester
-	maxCreditsPerSemester, err := s.GetProgramMaxCreditsPerSemester(existingEnrollment.ProgramType)
+	maxCreditsPerSemester, err := s.GetProgramMaxCreditsPerSemester(ctx, existingEnrollment.ProgramType)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	if existingEnrollment.CreditsThisSemester+totalCreditsToAdd > maxCreditsPerSemester {
-		return fmt.Errorf("Total credits (%d) exceed the maximum allowed credits per semester (%d)", existingEnrollment.CreditsThisSemester+totalCreditsToAdd, maxCreditsPerSemester)
+		return 0, fmt.Errorf("Total credits (%d) exceed the maximum allowed credits per semester (%d)", existingEnrollment.CreditsThisSemester+totalCreditsToAdd, maxCreditsPerSemester)
 	}
-	// // Add the courses to the current semester's enrollment
-	// existingEnrollment.CoursesTaken[currentSemester] = append(existingEnrollment.CoursesTaken[currentSemester], coursesToAdd...)
+
+	return totalCreditsToAdd, nil
+}
+
+// takeCourses adds checked courses to the current semester of an enrollment and fills a seat in each
+// The caller stores the enrollment
+func (s *StudentRecordContract) takeCourses(ctx contractapi.TransactionContextInterface, existingEnrollment *Enrollment, coursesToAdd []string) error {
+	currentSemester := existingEnrollment.CurrentSemester
 
 	// Add the courses to the current semester's enrollment
 	for _, courseID := range coursesToAdd {
@@ -102,37 +151,22 @@ func (s *StudentRecordContract) AddCoursesToCurrentSemester(ctx contractapi.Tran
 		}
 
 		course.SeatsFilled += 1
-		// Update the course in the course mapping
-		courseMapping[course.CourseID] = *course
 
 		// Marshal and store the course in the ledger
 		newCourseJSON, _ := json.Marshal(course)
-		err = ctx.GetStub().PutState(course.CourseID, newCourseJSON)
+		err = putEntityState(ctx, courseObjectType, course.CourseID, newCourseJSON)
 		if err != nil {
 			return err
 		}
 
 	}
 
-	// Update the CreditsThisSemester with the totalCreditsToAdd
-	existingEnrollment.CreditsThisSemester += totalCreditsToAdd
-
-	// Update the enrollment mapping
-	enrollmentMapping[studentID] = existingEnrollment
-
-	// Update the enrollment in the ledger
-	enrollmentJSON, _ := json.Marshal(existingEnrollment)
-	err = ctx.GetStub().PutState(fmt.Sprintf("ENROLLMENT-%s", studentID), enrollmentJSON)
-	if err != nil {
-		return err
-	}
-
-	// Record the ledger update
-	entry := fmt.Sprintf("Added courses to current semester for student %s: %s", studentID, strings.Join(coursesToAdd, ", "))
-	err = s.recordLedgerUpdate(ctx, entry)
+	// Derive the credits of the semester from the courses taken
+	credits, err := s.semesterCredits(ctx, *existingEnrollment)
 	if err != nil {
 		return err
 	}
+	existingEnrollment.CreditsThisSemester = credits
 
 	return nil
 }
@@ -157,6 +191,11 @@ func (s *StudentRecordContract) DropCoursesFromCurrentSemester(ctx contractapi.T
 		return err
 	}
 
+	// Check if the student is active
+	if err := ensureEnrollmentActive(existingEnrollment); err != nil {
+		return err
+	}
+
 	// Check if the current semester exists in the enrollment
 	currentSemester := existingEnrollment.CurrentSemester
 
@@ -177,26 +216,13 @@ func (s *StudentRecordContract) DropCoursesFromCurrentSemester(ctx contractapi.T
 		// Decrement the seats filled for the dropped course
 		if course.SeatsFilled > 0 {
 			// course.SeatsFilled--
-			// Create a new course
-			newCourse := Course{
-				CourseID:     course.CourseID,
-				CourseName:   course.CourseName,
-				Credits:      course.Credits,
-				DepartmentID: course.DepartmentID,
-				FacultyID:    course.FacultyID,
-				Description:  course.Description,
-				AcademicYear: course.AcademicYear,
-				Semester:     course.Semester,
-				MaxSeats:     course.MaxSeats,
-				SeatsFilled:  course.SeatsFilled - 1,
-			}
-
-			// Update the course in the course mapping
-			courseMapping[course.CourseID] = newCourse
+			// Copy the course, keeping its instructors, archival and version
+			newCourse := *course
+			newCourse.SeatsFilled = course.SeatsFilled - 1
 
 			// Marshal and store the course in the ledger
 			newCourseJSON, _ := json.Marshal(newCourse)
-			err = ctx.GetStub().PutState(course.CourseID, newCourseJSON)
+			err = putEntityState(ctx, courseObjectType, course.CourseID, newCourseJSON)
 			if err != nil {
 				return err
 			}
@@ -204,18 +230,6 @@ func (s *StudentRecordContract) DropCoursesFromCurrentSemester(ctx contractapi.T
 		}
 	}
 
-	// Calculate the total credits for the courses to drop
-	totalCreditsToDrop := 0
-	for _, courseID := range coursesToDrop {
-		course, err := s.GetCourse(ctx, courseID)
-		if err != nil {
-			return err
-		}
-		totalCreditsToDrop += course.Credits
-	}
-	// Update the CreditsThisSemester with the totalCreditsToAdd
-	existingEnrollment.CreditsThisSemester -= totalCreditsToDrop
-
 	// Remove the dropped courses from the current semester's enrollment
 	existingCourses := existingEnrollment.CoursesTaken[currentSemester]
 	remainingCourses := []string{}
@@ -226,12 +240,15 @@ func (s *StudentRecordContract) DropCoursesFromCurrentSemester(ctx contractapi.T
 	}
 	existingEnrollment.CoursesTaken[currentSemester] = remainingCourses
 
-	// Update the enrollment mapping
-	enrollmentMapping[studentID] = existingEnrollment
+	// Derive the credits of the semester from the remaining courses
+	existingEnrollment.CreditsThisSemester, err = s.semesterCredits(ctx, existingEnrollment)
+	if err != nil {
+		return err
+	}
 
 	// Update the enrollment in the ledger
 	enrollmentJSON, _ := json.Marshal(existingEnrollment)
-	err = ctx.GetStub().PutState(fmt.Sprintf("ENROLLMENT-%s", studentID), enrollmentJSON)
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
 	if err != nil {
 		return err
 	}
@@ -259,74 +276,94 @@ func (s *StudentRecordContract) DropCoursesFromCurrentSemester(ctx contractapi.T
 
 // To use this function, you can invoke it using the peer CLI or through your application to add new courses to the list of available courses in your Hyperledger Fabric network.
 func (s *StudentRecordContract) AddCourse(ctx contractapi.TransactionContextInterface, courseID string, courseName string, credits int, departmentID string, facultyID string, description string, academicYear int, semester int, maxSeats int) error {
-	// Check if the course already exists
-	courseKey := fmt.Sprintf("COURSE-%s", courseID)
-	courseJSON, err := ctx.GetStub().GetState(courseKey)
+	err := s.createCourse(ctx, courseID, courseName, credits, departmentID, facultyID, description, academicYear, semester, maxSeats)
 	if err != nil {
 		return err
 	}
-	if courseJSON != nil {
-		return fmt.Errorf("Course with ID %s already exists", courseID)
-	}
-
-	// Check if the departmentID is valid
-	_, departmentExists := departmentMapping[departmentID]
-	if !departmentExists {
-		return fmt.Errorf("Department ID %s is not valid", departmentID)
-	}
 
-	// Check if the facultyID is valid
-	faculty, facultyExists := facultyMapping[facultyID]
-	if !facultyExists {
-		return fmt.Errorf("Faculty ID %s is not valid", facultyID)
+	// Record the ledger update
+	entry := fmt.Sprintf("Added new course: %s", courseID)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
 	}
 
-	// Check if the faculty's department matches the input departmentID
-	if faculty.DepartmentID != departmentID {
-		return fmt.Errorf("Faculty with ID %s is not associated with department %s", facultyID, departmentID)
-	}
+	return nil
+}
 
-	// Create a new course
-	newCourse := Course{
-		CourseID:     courseID,
-		CourseName:   courseName,
-		Credits:      credits,
-		DepartmentID: departmentID,
-		FacultyID:    facultyID,
-		Description:  description,
-		AcademicYear: academicYear,
-		Semester:     semester,
-		MaxSeats:     maxSeats,
-		SeatsFilled:  0,
+// createCourse stores a new course, without recording a ledger update
+func (s *StudentRecordContract) createCourse(ctx contractapi.TransactionContextInterface, courseID string, courseName string, credits int, departmentID string, facultyID string, description string, academicYear int, semester int, maxSeats int) error {
+	newCourse, err := s.buildCourse(ctx, nil, courseID, courseName, credits, departmentID, facultyID, description, academicYear, semester, maxSeats)
+	if err != nil {
+		return err
 	}
 
 	// Marshal and store the course in the ledger
-	courseJSON, err = json.Marshal(newCourse)
+	return putCatalogState(ctx, courseObjectType, courseID, newCourse)
+}
+
+// buildCourse checks and returns a new course, without storing it
+// The department and faculty are looked up in the staged records before the ledger
+func (s *StudentRecordContract) buildCourse(ctx contractapi.TransactionContextInterface, staged *stagedRecords, courseID string, courseName string, credits int, departmentID string, facultyID string, description string, academicYear int, semester int, maxSeats int) (Course, error) {
+	// Check if the course already exists
+	courseJSON, err := getEntityState(ctx, courseObjectType, courseID)
 	if err != nil {
-		return err
+		return Course{}, err
 	}
-	err = ctx.GetStub().PutState(courseKey, courseJSON)
-	if err != nil {
-		return err
+	if courseJSON != nil {
+		return Course{}, fmt.Errorf("Course with ID %s already exists", courseID)
 	}
 
-	courseMapping[newCourse.CourseID] = newCourse
+	// Check if the departmentID is valid
+	department, departmentExists, err := staged.lookupDepartment(ctx, departmentID)
+	if err != nil {
+		return Course{}, err
+	}
+	if !departmentExists {
+		return Course{}, fmt.Errorf("Department ID %s is not valid", departmentID)
+	}
+	if department.Archived {
+		return Course{}, fmt.Errorf("Department %s is archived", departmentID)
+	}
 
-	// Record the ledger update
-	entry := fmt.Sprintf("Added new course: %s", courseID)
-	err = s.recordLedgerUpdate(ctx, entry)
+	// Check if the facultyID is valid
+	faculty, facultyExists, err := staged.lookupFaculty(ctx, facultyID)
 	if err != nil {
-		return err
+		return Course{}, err
+	}
+	if !facultyExists {
+		return Course{}, fmt.Errorf("Faculty ID %s is not valid", facultyID)
+	}
+	if faculty.Archived {
+		return Course{}, fmt.Errorf("Faculty %s is archived", facultyID)
 	}
 
-	return nil
+	// Check if the faculty is appointed to the input departmentID
+	if !inDepartment(faculty, departmentID) {
+		return Course{}, fmt.Errorf("Faculty with ID %s is not associated with department %s", facultyID, departmentID)
+	}
+
+	// Create a new course
+	return Course{
+		CourseID:      courseID,
+		CourseName:    courseName,
+		Credits:       credits,
+		DepartmentID:  departmentID,
+		FacultyID:     facultyID,
+		Description:   description,
+		AcademicYear:  academicYear,
+		Semester:      semester,
+		MaxSeats:      maxSeats,
+		SeatsFilled:   0,
+		CoInstructors: []string{},
+		SchemaVersion: schemaVersion(courseObjectType),
+	}, nil
 }
 
 // RemoveCourse removes a course from the ledger
 func (s *StudentRecordContract) RemoveCourse(ctx contractapi.TransactionContextInterface, courseID string) error {
 	// Check if the course exists
-	courseKey := fmt.Sprintf("COURSE-%s", courseID)
-	courseJSON, err := ctx.GetStub().GetState(courseKey)
+	courseJSON, err := getEntityState(ctx, courseObjectType, courseID)
 	if err != nil {
 		return err
 	}
@@ -334,13 +371,17 @@ func (s *StudentRecordContract) RemoveCourse(ctx contractapi.TransactionContextI
 		return fmt.Errorf("Course with ID %s does not exist", courseID)
 	}
 
-	// Delete the course from the ledger
-	err = ctx.GetStub().DelState(courseKey)
+	// Refuse to remove a course that other records refer to
+	err = s.ensureNoDependents(ctx, entityCourse, courseID)
 	if err != nil {
 		return err
 	}
 
-	delete(courseMapping, courseID)
+	// Delete the course from the ledger
+	err = delEntityState(ctx, courseObjectType, courseID)
+	if err != nil {
+		return err
+	}
 
 	// Record the ledger update
 	entry := fmt.Sprintf("Removed course: %s", courseID)
@@ -354,25 +395,20 @@ func (s *StudentRecordContract) RemoveCourse(ctx contractapi.TransactionContextI
 
 // GetCourse retrieves a course by its ID from the ledger
 func (s *StudentRecordContract) GetCourse(ctx contractapi.TransactionContextInterface, courseID string) (*Course, error) {
-	// courseJSON, err := ctx.GetStub().GetState(fmt.Sprintf("COURSE-%s", courseID))
-	// if err != nil {
-	// 	return Course{}, fmt.Errorf("Failed to read course with ID %s: %v", courseID, err)
-	// }
-	// if courseJSON == nil {
-	// 	return Course{}, fmt.Errorf("Course with ID %s does not exist", courseID)
-	// }
-
-	// var course Course
-	// err = json.Unmarshal(courseJSON, &course)
-	// if err != nil {
-	// 	return Course{}, err
-	// }
-
-	// return course, nil
-	course, exists := courseMapping[courseID]
-	if !exists {
+	courseJSON, err := getEntityState(ctx, courseObjectType, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read course with ID %s: %v", courseID, err)
+	}
+	if courseJSON == nil {
 		return nil, fmt.Errorf("course with id %s does not exist", courseID)
 	}
+
+	var course Course
+	err = json.Unmarshal(courseJSON, &course)
+	if err != nil {
+		return nil, err
+	}
+
 	return &course, nil
 }
 
@@ -380,8 +416,16 @@ func (s *StudentRecordContract) GetCourse(ctx contractapi.TransactionContextInte
 func (s *StudentRecordContract) GetAllCourses(ctx contractapi.TransactionContextInterface) ([]Course, error) {
 	courses := make([]Course, 0)
 
-	for _, course := range courseMapping {
+	err := forEachEntity(ctx, courseObjectType, func(value []byte) error {
+		var course Course
+		if err := json.Unmarshal(value, &course); err != nil {
+			return err
+		}
 		courses = append(courses, course)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return courses, nil
@@ -391,10 +435,18 @@ func (s *StudentRecordContract) GetAllCourses(ctx contractapi.TransactionContext
 func (s *StudentRecordContract) GetCoursesByDepartment(ctx contractapi.TransactionContextInterface, departmentID string) ([]Course, error) {
 	courses := make([]Course, 0)
 
-	for _, course := range courseMapping {
+	err := forEachEntity(ctx, courseObjectType, func(value []byte) error {
+		var course Course
+		if err := json.Unmarshal(value, &course); err != nil {
+			return err
+		}
 		if course.DepartmentID == departmentID {
 			courses = append(courses, course)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return courses, nil
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// CoursePlan is a set of courses a student asks to register for in a semester, approved or rejected by the student's advisor
+// Seats are taken only when the plan is approved
+type CoursePlan struct {
+	PlanID      int      `json:"planID"` // Position of the plan in the student's plans, starting at 1
+	Semester    string   `json:"semester"`
+	Courses     []string `json:"courses"`
+	Credits     int      `json:"credits"`
+	Status      string   `json:"status"` // submitted, approved, rejected or withdrawn
+	SubmittedAt string   `json:"submittedAt"`
+	ReviewedBy  string   `json:"reviewedBy"` // Advisor who approved or rejected the plan
+	Remarks     string   `json:"remarks"`
+	ReviewedAt  string   `json:"reviewedAt"`
+	TxID        string   `json:"txID"` // ID of the transaction that submitted the plan
+}
+
+// AdviseeCoursePlan is a course plan awaiting the review of an advisor
+type AdviseeCoursePlan struct {
+	StudentID string     `json:"studentID"`
+	Name      string     `json:"name"`
+	Plan      CoursePlan `json:"plan"`
+}
+
+// Course plan statuses
+const (
+	planSubmitted = "submitted"
+	planApproved  = "approved"
+	planRejected  = "rejected"
+	planWithdrawn = "withdrawn" // Replaced by a later submission before it was reviewed
+)
+
+// AssignAdvisor makes a faculty with the advisor designation the advisor of a student of their department
+func (s *StudentRecordContract) AssignAdvisor(ctx contractapi.TransactionContextInterface, studentID string, facultyID string) error {
+	existingEnrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return err
+	}
+
+	// Check if the caller is authorized (admin or head of the student's department)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) && !s.isDepartmentHead(ctx, caller, existingEnrollment.DepartmentID) {
+		return fmt.Errorf("Unauthorized: only admin or the head of department %s can assign advisors to its students", existingEnrollment.DepartmentID)
+	}
+	if err := ensureEnrollmentModifiable(existingEnrollment); err != nil {
+		return err
+	}
+
+	var faculty Faculty
+	facultyExists, err := readEntity(ctx, facultyObjectType, facultyID, &faculty)
+	if err != nil {
+		return err
+	}
+	if !facultyExists {
+		return fmt.Errorf("Faculty ID %s is not valid", facultyID)
+	}
+	if faculty.Archived {
+		return fmt.Errorf("Faculty %s is archived", facultyID)
+	}
+	if !contains(faculty.Designations, designationAdvisor) {
+		return fmt.Errorf("Faculty %s does not have the %s designation", facultyID, designationAdvisor)
+	}
+	if !inDepartment(faculty, existingEnrollment.DepartmentID) {
+		return fmt.Errorf("Faculty with ID %s is not associated with department %s", facultyID, existingEnrollment.DepartmentID)
+	}
+	if existingEnrollment.AdvisorID == facultyID {
+		return fmt.Errorf("Faculty %s is already the advisor of student %s", facultyID, studentID)
+	}
+
+	// Plans awaiting review move to the new advisor
+	previousAdvisorID := existingEnrollment.AdvisorID
+	existingEnrollment.AdvisorID = facultyID
+
+	// Update the enrollment in the ledger
+	enrollmentJSON, err := json.Marshal(existingEnrollment)
+	if err != nil {
+		return err
+	}
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Assigned advisor %s to student %s", facultyID, studentID)
+	if previousAdvisorID != "" {
+		entry += fmt.Sprintf(" (previously %s)", previousAdvisorID)
+	}
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAdvisees returns the IDs of the students a faculty advises, ordered by ID
+func (s *StudentRecordContract) GetAdvisees(ctx contractapi.TransactionContextInterface, facultyID string) ([]string, error) {
+	if _, err := s.GetFaculty(ctx, facultyID); err != nil {
+		return nil, err
+	}
+
+	studentIDs := make([]string, 0)
+	err := forEachEntity(ctx, enrollmentObjectType, func(value []byte) error {
+		var enrollment Enrollment
+		if err := json.Unmarshal(value, &enrollment); err != nil {
+			return err
+		}
+		if enrollment.AdvisorID == facultyID {
+			studentIDs = append(studentIDs, enrollment.StudentID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return studentIDs, nil
+}
+
+// SubmitCoursePlan submits the courses, given as a JSON list, a student asks to register for in the current semester
+// A plan awaiting review is replaced by the new one
+func (s *StudentRecordContract) SubmitCoursePlan(ctx contractapi.TransactionContextInterface, studentID string, coursesJSON string) error {
+	var courses []string
+	if err := json.Unmarshal([]byte(coursesJSON), &courses); err != nil {
+		return fmt.Errorf("unmarhsal error")
+	}
+
+	// Check if the caller is authorized (admin or the student)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) && !s.isStudentSelf(ctx, caller, studentID) {
+		return fmt.Errorf("Unauthorized: only the student or admin can submit a course plan")
+	}
+
+	existingEnrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	if err := ensureEnrollmentActive(existingEnrollment); err != nil {
+		return err
+	}
+	if existingEnrollment.AdvisorID == "" {
+		return fmt.Errorf("Student %s has no advisor to approve a course plan", studentID)
+	}
+	if len(courses) == 0 {
+		return fmt.Errorf("A course plan needs at least one course")
+	}
+
+	credits, err := s.checkCoursesForCurrentSemester(ctx, existingEnrollment, courses)
+	if err != nil {
+		return err
+	}
+
+	submittedAt, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	if index := pendingCoursePlan(existingEnrollment); index >= 0 {
+		existingEnrollment.CoursePlans[index].Status = planWithdrawn
+	}
+	plan := CoursePlan{
+		PlanID:      len(existingEnrollment.CoursePlans) + 1,
+		Semester:    existingEnrollment.CurrentSemester,
+		Courses:     courses,
+		Credits:     credits,
+		Status:      planSubmitted,
+		SubmittedAt: submittedAt,
+		TxID:        ctx.GetStub().GetTxID(),
+	}
+	existingEnrollment.CoursePlans = append(existingEnrollment.CoursePlans, plan)
+
+	// Update the enrollment in the ledger
+	enrollmentJSON, err := json.Marshal(existingEnrollment)
+	if err != nil {
+		return err
+	}
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Submitted course plan %d for student %s: %s", plan.PlanID, studentID, strings.Join(courses, ", "))
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReviewCoursePlan approves or rejects the course plan of a student awaiting review; decision is approved or rejected
+// Only the student's advisor can review it, and a rejection needs remarks. Approval registers the courses and takes their seats.
+// The reviewer is the faculty named by the facultyID attribute of the caller's certificate
+func (s *StudentRecordContract) ReviewCoursePlan(ctx contractapi.TransactionContextInterface, studentID string, decision string, remarks string) error {
+	caller := ctx.GetClientIdentity()
+	if !s.isFaculty(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only faculty can review course plans")
+	}
+	facultyID, found := s.callerFacultyID(ctx, caller)
+	if !found {
+		return fmt.Errorf("Unauthorized: the caller's certificate has no facultyID attribute")
+	}
+
+	if decision != planApproved && decision != planRejected {
+		return fmt.Errorf("Invalid decision %s: must be %s or %s", decision, planApproved, planRejected)
+	}
+	if decision == planRejected && remarks == "" {
+		return fmt.Errorf("Remarks are required to reject a course plan")
+	}
+
+	existingEnrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	if facultyID != existingEnrollment.AdvisorID {
+		return fmt.Errorf("Faculty %s is not the advisor of student %s", facultyID, studentID)
+	}
+
+	index := pendingCoursePlan(existingEnrollment)
+	if index < 0 {
+		return fmt.Errorf("Student %s has no course plan awaiting review", studentID)
+	}
+	plan := existingEnrollment.CoursePlans[index]
+	if plan.Semester != existingEnrollment.CurrentSemester {
+		return fmt.Errorf("Course plan %d of student %s is for semester %s, which has ended", plan.PlanID, studentID, plan.Semester)
+	}
+
+	if decision == planApproved {
+		if err := ensureEnrollmentActive(existingEnrollment); err != nil {
+			return err
+		}
+		// Courses may have filled up since the plan was submitted
+		_, err := s.checkCoursesForCurrentSemester(ctx, existingEnrollment, plan.Courses)
+		if err != nil {
+			return err
+		}
+		err = s.takeCourses(ctx, &existingEnrollment, plan.Courses)
+		if err != nil {
+			return err
+		}
+	}
+
+	reviewedAt, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+	plan.Status = decision
+	plan.ReviewedBy = facultyID
+	plan.Remarks = remarks
+	plan.ReviewedAt = reviewedAt
+	existingEnrollment.CoursePlans[index] = plan
+
+	// Update the enrollment in the ledger
+	enrollmentJSON, err := json.Marshal(existingEnrollment)
+	if err != nil {
+		return err
+	}
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Course plan %d of student %s %s by advisor %s", plan.PlanID, studentID, decision, facultyID)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetCoursePlans returns the course plans of a student, oldest first
+func (s *StudentRecordContract) GetCoursePlans(ctx contractapi.TransactionContextInterface, studentID string) ([]CoursePlan, error) {
+	enrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	return enrollment.CoursePlans, nil
+}
+
+// GetPendingCoursePlans returns the course plans awaiting the review of an advisor, ordered by student ID
+func (s *StudentRecordContract) GetPendingCoursePlans(ctx contractapi.TransactionContextInterface, facultyID string) ([]AdviseeCoursePlan, error) {
+	if _, err := s.GetFaculty(ctx, facultyID); err != nil {
+		return nil, err
+	}
+
+	plans := make([]AdviseeCoursePlan, 0)
+	err := forEachEntity(ctx, enrollmentObjectType, func(value []byte) error {
+		var enrollment Enrollment
+		if err := json.Unmarshal(value, &enrollment); err != nil {
+			return err
+		}
+		if enrollment.AdvisorID != facultyID {
+			return nil
+		}
+		if index := pendingCoursePlan(enrollment); index >= 0 {
+			plans = append(plans, AdviseeCoursePlan{
+				StudentID: enrollment.StudentID,
+				Name:      enrollment.Name,
+				Plan:      enrollment.CoursePlans[index],
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return plans, nil
+}
+
+// pendingCoursePlan returns the index of the plan awaiting review, or -1 if there is none
+func pendingCoursePlan(enrollment Enrollment) int {
+	for i, plan := range enrollment.CoursePlans {
+		if plan.Status == planSubmitted {
+			return i
+		}
+	}
+	return -1
+}
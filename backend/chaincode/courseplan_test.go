@@ -0,0 +1,131 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// advise makes F2, of CSE, the advisor of S1
+func (c *testContract) advise() {
+	c.t.Helper()
+	c.mustInvoke("AddFaculty", "F2", "Ravi Kumar", "CSE")
+	c.mustInvoke("SetFacultyDesignations", "F2", `["advisor"]`)
+	c.mustInvoke("AssignAdvisor", "S1", "F2")
+}
+
+func TestAssignAdvisor(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddDepartment", "EE", "Electrical")
+	c.mustInvoke("AddFaculty", "F3", "Meera Iyer", "EE")
+	c.mustInvoke("SetFacultyDesignations", "F3", `["advisor"]`)
+
+	c.mustFail("does not have the advisor designation", "AssignAdvisor", "S1", "F1")
+	c.mustFail("not associated with department CSE", "AssignAdvisor", "S1", "F3")
+	c.mustFail("Faculty ID F9 is not valid", "AssignAdvisor", "S1", "F9")
+	c.advise()
+	c.mustFail("already the advisor of student S1", "AssignAdvisor", "S1", "F2")
+
+	var advisees []string
+	c.query(&advisees, "GetAdvisees", "F2")
+	if strings.Join(advisees, ",") != "S1" {
+		t.Fatalf("advisees of F2 are %v", advisees)
+	}
+	var report DependencyReport
+	c.query(&report, "GetDependents", entityFaculty, "F2")
+	if len(report.Dependents) != 1 || report.Dependents[0].Relation != "advised by the faculty" {
+		t.Fatalf("dependents of the advisor are %+v", report.Dependents)
+	}
+}
+
+func TestSubmitCoursePlan(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	c.mustFail("has no advisor to approve a course plan", "SubmitCoursePlan", "S1", `["CS101"]`)
+	c.advise()
+	c.mustFail("listed more than once", "SubmitCoursePlan", "S1", `["CS101","CS101"]`)
+	c.mustFail("needs at least one course", "SubmitCoursePlan", "S1", `[]`)
+	c.mustInvoke("SubmitCoursePlan", "S1", `["CS101"]`)
+
+	// A new plan replaces the one awaiting review
+	c.mustInvoke("SubmitCoursePlan", "S1", `["CS101","CS102"]`)
+	var plans []CoursePlan
+	c.query(&plans, "GetCoursePlans", "S1")
+	if len(plans) != 2 || plans[0].Status != planWithdrawn || plans[1].Status != planSubmitted || plans[1].Credits != 74 || plans[1].PlanID != 2 {
+		t.Fatalf("course plans are %+v", plans)
+	}
+	var pending []AdviseeCoursePlan
+	c.query(&pending, "GetPendingCoursePlans", "F2")
+	if len(pending) != 1 || pending[0].StudentID != "S1" || pending[0].Name != "Asha" || pending[0].Plan.PlanID != 2 {
+		t.Fatalf("pending course plans are %+v", pending)
+	}
+
+	// Seats are taken only when the plan is approved
+	var course Course
+	c.query(&course, "GetCourse", "CS101")
+	if course.SeatsFilled != 0 {
+		t.Fatalf("CS101 has %d seats filled before the approval", course.SeatsFilled)
+	}
+}
+
+func TestReviewCoursePlan(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.advise()
+	c.as(map[string]string{"facultyID": "F2"})
+	c.mustFail("has no course plan awaiting review", "ReviewCoursePlan", "S1", planApproved, "")
+	c.as(nil)
+	c.mustInvoke("SubmitCoursePlan", "S1", `["CS101","CS102"]`)
+
+	// The reviewer is the faculty of the caller's certificate, not one named in the arguments
+	c.mustFail("the caller's certificate has no facultyID attribute", "ReviewCoursePlan", "S1", planApproved, "")
+	c.as(map[string]string{"facultyID": "F1"})
+	c.mustFail("Faculty F1 is not the advisor of student S1", "ReviewCoursePlan", "S1", planApproved, "")
+	c.as(map[string]string{"facultyID": "F2"})
+	c.mustFail("Remarks are required", "ReviewCoursePlan", "S1", planRejected, "")
+	c.mustFail("Invalid decision pending", "ReviewCoursePlan", "S1", "pending", "")
+	c.mustInvoke("ReviewCoursePlan", "S1", planApproved, "")
+	c.mustFail("has no course plan awaiting review", "ReviewCoursePlan", "S1", planApproved, "")
+	c.as(nil)
+
+	enrollment := c.enrollment("S1")
+	if strings.Join(enrollment.CoursesTaken["Semester1"], ",") != "CS101,CS102" || enrollment.CreditsThisSemester != 74 {
+		t.Fatalf("enrollment after the approval is %+v", enrollment)
+	}
+	plan := enrollment.CoursePlans[0]
+	if plan.Status != planApproved || plan.ReviewedBy != "F2" || plan.ReviewedAt == "" {
+		t.Fatalf("approved plan is %+v", plan)
+	}
+	var course Course
+	c.query(&course, "GetCourse", "CS101")
+	if course.SeatsFilled != 1 {
+		t.Fatalf("CS101 has %d seats filled after the approval", course.SeatsFilled)
+	}
+
+	// A rejection keeps the remarks and registers nothing
+	c.mustInvoke("DropCoursesFromCurrentSemester", "S1", `["CS102"]`)
+	c.mustInvoke("SubmitCoursePlan", "S1", `["CS201"]`)
+	c.as(map[string]string{"facultyID": "F2"})
+	c.mustInvoke("ReviewCoursePlan", "S1", planRejected, "Take CS102 first")
+	c.as(nil)
+	enrollment = c.enrollment("S1")
+	if plan := enrollment.CoursePlans[1]; plan.Status != planRejected || plan.Remarks != "Take CS102 first" || strings.Join(enrollment.CoursesTaken["Semester1"], ",") != "CS101" {
+		t.Fatalf("enrollment after the rejection is %+v", enrollment)
+	}
+}
+
+func TestReviewCoursePlanRechecksSeats(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("InitialEnrollment", "S2", "Ravi", "BTECH", "CSE")
+	c.mustInvoke("AddCourse", "CS103", "Discrete Mathematics", "4", "CSE", "F1", "", "2024", "1", "1")
+	c.advise()
+
+	// The last seat is taken while the plan awaits review
+	c.mustInvoke("SubmitCoursePlan", "S1", `["CS103"]`)
+	c.mustInvoke("AddCoursesToCurrentSemester", "S2", `["CS103"]`)
+	c.as(map[string]string{"facultyID": "F2"})
+	c.mustFail("No seats available for course CS103", "ReviewCoursePlan", "S1", planApproved, "")
+	c.mustInvoke("ReviewCoursePlan", "S1", planRejected, "Course is full")
+}
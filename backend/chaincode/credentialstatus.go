@@ -0,0 +1,285 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// CredentialStatusList tracks the revocation of verifiable credentials by their index in the list
+type CredentialStatusList struct {
+	ListID        string `json:"listID"`
+	NextIndex     int    `json:"nextIndex"` // Index assigned to the next verifiable credential
+	Revoked       []int  `json:"revoked"`   // Sorted indexes of the revoked verifiable credentials
+	SchemaVersion int    `json:"schemaVersion"`
+}
+
+// credentialStatusLink links a verifiable credential to the degree or certificate it was issued for
+// Stored under the composite key VCSTATUS~sourceCredentialID~credentialID, so that revoking the degree or certificate
+// finds the verifiable credentials issued for it and marks them in the status list
+type credentialStatusLink struct {
+	CredentialID    string `json:"credentialID"`
+	StatusListIndex int    `json:"statusListIndex"`
+	SchemaVersion   int    `json:"schemaVersion"`
+}
+
+// revocationStatusListID is the ID of the status list used for revocation
+const revocationStatusListID = "revocation"
+
+// IssueVerifiableCredential records a signed verifiable credential issued for a degree or certificate
+// The credential is signed with the next free index of the status list, read with GetCredentialStatusList, and the
+// index is only taken once the credential is recorded. If another credential took the index first, the transaction
+// fails and the credential must be signed again with the new index
+func (s *StudentRecordContract) IssueVerifiableCredential(ctx contractapi.TransactionContextInterface, credentialID string, sourceCredentialID string, statusListIndex int, credentialHash string) error {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can issue verifiable credentials")
+	}
+
+	// Check that the credential ID is not in use
+	existingRecord, err := s.getVerificationRecord(ctx, credentialID)
+	if err != nil {
+		return err
+	}
+	if existingRecord != nil {
+		return fmt.Errorf("Credential %s already exists", credentialID)
+	}
+
+	// Verifiable credentials are only issued for valid degrees and certificates
+	source, err := s.getVerificationRecord(ctx, sourceCredentialID)
+	if err != nil {
+		return err
+	}
+	if source == nil {
+		return fmt.Errorf("No credential with ID %s has been issued", sourceCredentialID)
+	}
+	if source.CredentialType != credentialTypeDegree && source.CredentialType != credentialTypeCertificate {
+		return fmt.Errorf("Verifiable credentials can only be issued for degrees and certificates")
+	}
+	if source.Revoked {
+		return fmt.Errorf("Credential %s has been revoked", sourceCredentialID)
+	}
+
+	// The credential must be signed with the next free index of the status list
+	statusList, err := s.GetCredentialStatusList(ctx)
+	if err != nil {
+		return err
+	}
+	if statusListIndex != statusList.NextIndex {
+		return fmt.Errorf("Status index %d is not the next free index %d of the status list", statusListIndex, statusList.NextIndex)
+	}
+
+	// The verifiable credential carries the public details of the credential it was issued for
+	details := map[string]string{}
+	for name, value := range source.Details {
+		details[name] = value
+	}
+	details["sourceCredentialID"] = sourceCredentialID
+	details["statusListID"] = revocationStatusListID
+	details["statusListIndex"] = fmt.Sprintf("%d", statusListIndex)
+
+	err = s.putVerificationRecord(ctx, VerificationRecord{
+		CredentialID:   credentialID,
+		CredentialType: credentialTypeVC,
+		StudentID:      source.StudentID,
+		StudentName:    source.StudentName,
+		Details:        details,
+		DocumentHashes: []string{credentialHash},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Take the index and link the verifiable credential to its source
+	statusList.NextIndex++
+	err = s.putCredentialStatusList(ctx, statusList)
+	if err != nil {
+		return err
+	}
+	linkKey, err := entityKey(ctx, credentialStatusObjectType, sourceCredentialID, credentialID)
+	if err != nil {
+		return err
+	}
+	linkJSON, _ := json.Marshal(credentialStatusLink{
+		CredentialID:    credentialID,
+		StatusListIndex: statusListIndex,
+		SchemaVersion:   schemaVersion(credentialStatusObjectType),
+	})
+	err = ctx.GetStub().PutState(linkKey, linkJSON)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Issued verifiable credential %s for %s with status index %d", credentialID, sourceCredentialID, statusListIndex)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RevokeCredential revokes a credential; a revoked verifiable credential is also marked in the revocation status list
+// Revoking a degree or certificate also revokes the verifiable credentials issued for it
+func (s *StudentRecordContract) RevokeCredential(ctx contractapi.TransactionContextInterface, credentialID string, reason string) error {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can revoke a credential")
+	}
+
+	if reason == "" {
+		return fmt.Errorf("A reason is required to revoke a credential")
+	}
+
+	record, err := s.getVerificationRecord(ctx, credentialID)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return fmt.Errorf("No credential with ID %s has been issued", credentialID)
+	}
+	if record.Revoked {
+		return fmt.Errorf("Credential %s has already been revoked", credentialID)
+	}
+
+	revokedAt, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+	record.Revoked = true
+	record.RevokedAt = revokedAt
+	record.RevocationReason = reason
+
+	err = s.storeVerificationRecord(ctx, *record)
+	if err != nil {
+		return err
+	}
+
+	// Collect the status indexes to mark, of the verifiable credential or of those issued for the degree or certificate
+	statusListIndexes := []int{}
+	if record.CredentialType == credentialTypeVC {
+		var statusListIndex int
+		_, err = fmt.Sscanf(record.Details["statusListIndex"], "%d", &statusListIndex)
+		if err != nil {
+			return fmt.Errorf("Credential %s has no valid status list index", credentialID)
+		}
+		statusListIndexes = append(statusListIndexes, statusListIndex)
+	}
+	derivedCredentials, err := s.revokeDerivedCredentials(ctx, credentialID, revokedAt, reason)
+	if err != nil {
+		return err
+	}
+	for _, link := range derivedCredentials {
+		statusListIndexes = append(statusListIndexes, link.StatusListIndex)
+	}
+
+	// Mark the verifiable credentials in the status list
+	if len(statusListIndexes) > 0 {
+		statusList, err := s.GetCredentialStatusList(ctx)
+		if err != nil {
+			return err
+		}
+		for _, statusListIndex := range statusListIndexes {
+			if !containsInt(statusList.Revoked, statusListIndex) {
+				statusList.Revoked = append(statusList.Revoked, statusListIndex)
+			}
+		}
+		sort.Ints(statusList.Revoked)
+
+		err = s.putCredentialStatusList(ctx, statusList)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Revoked credential %s: %s", credentialID, reason)
+	if len(derivedCredentials) > 0 {
+		entry += fmt.Sprintf(", with %d verifiable credentials issued for it", len(derivedCredentials))
+	}
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// revokeDerivedCredentials revokes the verifiable credentials issued for a degree or certificate that are not revoked
+// yet, and returns their links
+func (s *StudentRecordContract) revokeDerivedCredentials(ctx contractapi.TransactionContextInterface, sourceCredentialID string, revokedAt string, reason string) ([]credentialStatusLink, error) {
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(credentialStatusObjectType, []string{sourceCredentialID})
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	links := []credentialStatusLink{}
+	for iterator.HasNext() {
+		result, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		linkJSON, err := upgradeDocument(credentialStatusObjectType, result.Value)
+		if err != nil {
+			return nil, err
+		}
+		var link credentialStatusLink
+		if err := json.Unmarshal(linkJSON, &link); err != nil {
+			return nil, err
+		}
+
+		record, err := s.getVerificationRecord(ctx, link.CredentialID)
+		if err != nil {
+			return nil, err
+		}
+		if record == nil || record.Revoked {
+			continue
+		}
+		record.Revoked = true
+		record.RevokedAt = revokedAt
+		record.RevocationReason = fmt.Sprintf("%s was revoked: %s", sourceCredentialID, reason)
+		err = s.storeVerificationRecord(ctx, *record)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// GetCredentialStatusList retrieves the revocation status list of verifiable credentials
+func (s *StudentRecordContract) GetCredentialStatusList(ctx contractapi.TransactionContextInterface) (*CredentialStatusList, error) {
+	statusListJSON, err := getEntityState(ctx, statusListObjectType, revocationStatusListID)
+	if err != nil {
+		return nil, err
+	}
+
+	statusList := CredentialStatusList{ListID: revocationStatusListID, Revoked: []int{}, SchemaVersion: schemaVersion(statusListObjectType)}
+	if statusListJSON == nil {
+		return &statusList, nil
+	}
+
+	err = json.Unmarshal(statusListJSON, &statusList)
+	if err != nil {
+		return nil, err
+	}
+
+	return &statusList, nil
+}
+
+// putCredentialStatusList writes the status list to the ledger
+func (s *StudentRecordContract) putCredentialStatusList(ctx contractapi.TransactionContextInterface, statusList *CredentialStatusList) error {
+	statusListJSON, err := json.Marshal(statusList)
+	if err != nil {
+		return err
+	}
+
+	return putEntityState(ctx, statusListObjectType, statusList.ListID, statusListJSON)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func (c *testContract) statusList() CredentialStatusList {
+	c.t.Helper()
+	var statusList CredentialStatusList
+	c.query(&statusList, "GetCredentialStatusList")
+	return statusList
+}
+
+func TestIssueVerifiableCredential(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	c.mustFail("No credential with ID DEGREE-S1", "IssueVerifiableCredential", "urn:uuid:1", "DEGREE-S1", "0", testTranscriptHash)
+	c.mustInvoke("RecordTranscriptIssuance", "TRANSCRIPT-S1-1", "S1", testTranscriptHash, "")
+	c.mustFail("only be issued for degrees and certificates", "IssueVerifiableCredential", "urn:uuid:1", "TRANSCRIPT-S1-1", "0", testFileHash)
+
+	c.graduate()
+	if statusList := c.statusList(); statusList.NextIndex != 0 || len(statusList.Revoked) != 0 {
+		t.Fatalf("status list before any issue is %+v", statusList)
+	}
+	c.mustFail("not the next free index 0", "IssueVerifiableCredential", "urn:uuid:1", "DEGREE-S1", "1", testFileHash)
+	c.mustInvoke("IssueVerifiableCredential", "urn:uuid:1", "DEGREE-S1", "0", testFileHash)
+	c.mustFail("already exists", "IssueVerifiableCredential", "urn:uuid:1", "DEGREE-S1", "1", testFileHash)
+	c.mustFail("not the next free index 1", "IssueVerifiableCredential", "urn:uuid:2", "DEGREE-S1", "0", testFileHash)
+
+	c.restart()
+	if statusList := c.statusList(); statusList.NextIndex != 1 {
+		t.Fatalf("status list after one issue is %+v", statusList)
+	}
+	result := c.verify("urn:uuid:1", testFileHash)
+	if !result.Verified || result.Record.CredentialType != credentialTypeVC || result.Record.Details["sourceCredentialID"] != "DEGREE-S1" {
+		t.Fatalf("verifiable credential does not verify: %+v", result)
+	}
+}
+
+func TestRevokeCredential(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.graduate()
+	c.mustInvoke("IssueVerifiableCredential", "urn:uuid:1", "DEGREE-S1", "0", testTranscriptHash)
+	c.mustInvoke("IssueVerifiableCredential", "urn:uuid:2", "DEGREE-S1", "1", testFileHash)
+
+	c.mustFail("reason is required", "RevokeCredential", "urn:uuid:2", "")
+	c.mustFail("No credential with ID urn:uuid:3", "RevokeCredential", "urn:uuid:3", "issued in error")
+	c.mustInvoke("RevokeCredential", "urn:uuid:2", "issued in error")
+	c.mustFail("already been revoked", "RevokeCredential", "urn:uuid:2", "again")
+	if revoked := c.statusList().Revoked; !reflect.DeepEqual(revoked, []int{1}) {
+		t.Fatalf("revoked indexes are %v, want [1]", revoked)
+	}
+	if result := c.verify("urn:uuid:1", ""); !result.Verified {
+		t.Fatalf("credential urn:uuid:1 is not valid after revoking urn:uuid:2: %+v", result)
+	}
+
+	// Revoking the degree revokes the verifiable credentials issued for it
+	c.mustInvoke("RevokeCredential", "DEGREE-S1", "degree withdrawn")
+	if revoked := c.statusList().Revoked; !reflect.DeepEqual(revoked, []int{0, 1}) {
+		t.Fatalf("revoked indexes are %v, want [0 1]", revoked)
+	}
+	for _, credentialID := range []string{"DEGREE-S1", "urn:uuid:1"} {
+		if result := c.verify(credentialID, ""); result.Verified || !result.Record.Revoked {
+			t.Fatalf("revoked credential %s verifies: %+v", credentialID, result)
+		}
+	}
+	c.mustFail("has been revoked", "IssueVerifiableCredential", "urn:uuid:3", "DEGREE-S1", "2", "ee"+testFileHash[2:])
+}
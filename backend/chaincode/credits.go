@@ -0,0 +1,252 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// CreditSummary is the credit standing of a student, derived from the courses taken and their results
+type CreditSummary struct {
+	StudentID       string   `json:"studentID"`
+	Attempted       int      `json:"attempted"`       // Credits of every graded course, passed or failed
+	Earned          int      `json:"earned"`          // Credits of the passed courses that count towards the program
+	InProgress      int      `json:"inProgress"`      // Credits of the courses taken whose results are awaited
+	ThisSemester    int      `json:"thisSemester"`    // Credits of the courses taken in the current semester
+	ExcludedCourses []string `json:"excludedCourses"` // Passed courses left out by the last transfer
+}
+
+// GetCreditSummary returns the attempted, earned and in-progress credits of a student
+func (s *StudentRecordContract) GetCreditSummary(ctx contractapi.TransactionContextInterface, studentID string) (*CreditSummary, error) {
+	enrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the results, including private grades
+	allSemesterResults, err := s.getSemesterResults(ctx, enrollment)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.creditSummary(ctx, enrollment, allSemesterResults, transferExclusions(enrollment))
+	if err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+// RecomputeEnrollment rebuilds the derived fields of a student's enrollment from the source data
+// The credits are derived from the courses taken and their results, the status and the semesters of leave and
+// suspension from the status history, and the student record is brought in line with the enrollment
+func (s *StudentRecordContract) RecomputeEnrollment(ctx contractapi.TransactionContextInterface, studentID string) error {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can recompute enrollments")
+	}
+
+	student, err := s.GetStudent(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	existingEnrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return err
+	}
+
+	// Read the results, including private grades
+	allSemesterResults, err := s.getSemesterResults(ctx, existingEnrollment)
+	if err != nil {
+		return err
+	}
+
+	changes := []string{}
+
+	// Rebuild the credits
+	summary, err := s.creditSummary(ctx, existingEnrollment, allSemesterResults, transferExclusions(existingEnrollment))
+	if err != nil {
+		return err
+	}
+	publicCredits := publicCreditsCompleted(existingEnrollment, summary.Earned)
+	changeInt(&changes, "creditsCompleted", &publicCredits, &existingEnrollment.CreditsCompleted)
+	changeInt(&changes, "creditsThisSemester", &summary.ThisSemester, &existingEnrollment.CreditsThisSemester)
+
+	// Rebuild the status and the semesters of leave and suspension
+	if len(existingEnrollment.StatusHistory) > 0 {
+		status := existingEnrollment.StatusHistory[len(existingEnrollment.StatusHistory)-1].Status
+		leaveSemesters, suspendedSemesters := 0, 0
+		for _, change := range existingEnrollment.StatusHistory {
+			switch change.Status {
+			case studentOnLeave:
+				leaveSemesters += change.Semesters
+			case studentSuspended:
+				suspendedSemesters += change.Semesters
+			}
+		}
+		changeString(&changes, "status", &status, &existingEnrollment.Status)
+		changeInt(&changes, "leaveSemesters", &leaveSemesters, &existingEnrollment.LeaveSemesters)
+		changeInt(&changes, "suspendedSemesters", &suspendedSemesters, &existingEnrollment.SuspendedSemesters)
+	}
+
+	// Bring the student record in line with the enrollment
+	changeString(&changes, "student programType", &existingEnrollment.ProgramType, &student.ProgramType)
+	changeString(&changes, "student department", &existingEnrollment.DepartmentID, &student.DepartmentID)
+	program, programExists, err := readProgram(ctx, existingEnrollment.ProgramType)
+	if err != nil {
+		return err
+	}
+	if programExists {
+		changeInt(&changes, "student maxSemesters", &program.MaxSemesters, &student.MaxSemesters)
+	}
+
+	// Update the student and enrollment in the ledger
+	studentJSON, err := json.Marshal(student)
+	if err != nil {
+		return err
+	}
+	err = putEntityState(ctx, studentObjectType, studentID, studentJSON)
+	if err != nil {
+		return err
+	}
+	enrollmentJSON, err := json.Marshal(existingEnrollment)
+	if err != nil {
+		return err
+	}
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Recomputed enrollment of student %s: no changes", studentID)
+	if len(changes) > 0 {
+		entry = fmt.Sprintf("Recomputed enrollment of student %s: %s", studentID, strings.Join(changes, ", "))
+	}
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// creditSummary derives the credits of a student from the courses taken and the graded results of every semester
+// Passed courses in excludedCourses do not count towards the earned credits
+func (s *StudentRecordContract) creditSummary(ctx contractapi.TransactionContextInterface, enrollment Enrollment, allSemesterResults map[string][]Result, excludedCourses []string) (CreditSummary, error) {
+	summary := CreditSummary{
+		StudentID:       enrollment.StudentID,
+		ExcludedCourses: append([]string{}, excludedCourses...),
+	}
+
+	gradedCourses := make(map[string]bool)
+	for _, semesterResults := range allSemesterResults {
+		for _, result := range semesterResults {
+			course, err := s.GetCourse(ctx, result.CourseID)
+			if err != nil {
+				return CreditSummary{}, err
+			}
+			gradedCourses[result.CourseID] = true
+			summary.Attempted += course.Credits
+			if !isFailingGrade(result.Grade) && !contains(excludedCourses, result.CourseID) {
+				summary.Earned += course.Credits
+			}
+		}
+	}
+
+	// Courses taken without a result are still in progress
+	for _, courseIDs := range enrollment.CoursesTaken {
+		for _, courseID := range courseIDs {
+			if gradedCourses[courseID] {
+				continue
+			}
+			course, err := s.GetCourse(ctx, courseID)
+			if err != nil {
+				return CreditSummary{}, err
+			}
+			summary.InProgress += course.Credits
+		}
+	}
+
+	thisSemester, err := s.semesterCredits(ctx, enrollment)
+	if err != nil {
+		return CreditSummary{}, err
+	}
+	summary.ThisSemester = thisSemester
+
+	return summary, nil
+}
+
+// updateCredits sets the completed credits and the credits of the current semester of an enrollment to the ones
+// derived from its courses and results
+// The caller stores the enrollment
+func (s *StudentRecordContract) updateCredits(ctx contractapi.TransactionContextInterface, enrollment *Enrollment, allSemesterResults map[string][]Result) error {
+	summary, err := s.creditSummary(ctx, *enrollment, allSemesterResults, transferExclusions(*enrollment))
+	if err != nil {
+		return err
+	}
+	enrollment.CreditsCompleted = publicCreditsCompleted(*enrollment, summary.Earned)
+	enrollment.CreditsThisSemester = summary.ThisSemester
+	return nil
+}
+
+// publicCreditsCompleted returns the completed credits kept on the public enrollment
+// They are left out for students whose results are private, as a change in them tells which courses were passed
+func publicCreditsCompleted(enrollment Enrollment, earned int) int {
+	if enrollment.PrivateResults {
+		return 0
+	}
+	return earned
+}
+
+// completedCredits returns the completed credits of a student
+// For students whose results are private they are derived from the private grades, so only peers of orgs that are
+// members of the grades collection can read them
+func (s *StudentRecordContract) completedCredits(ctx contractapi.TransactionContextInterface, enrollment Enrollment) (int, error) {
+	if !enrollment.PrivateResults {
+		return enrollment.CreditsCompleted, nil
+	}
+
+	allSemesterResults, err := s.getSemesterResults(ctx, enrollment)
+	if err != nil {
+		return 0, err
+	}
+	summary, err := s.creditSummary(ctx, enrollment, allSemesterResults, transferExclusions(enrollment))
+	if err != nil {
+		return 0, err
+	}
+	return summary.Earned, nil
+}
+
+// semesterCredits returns the credits of the courses taken in the current semester
+// It needs no grades, so it can be used by peers that cannot read private results
+func (s *StudentRecordContract) semesterCredits(ctx contractapi.TransactionContextInterface, enrollment Enrollment) (int, error) {
+	credits := 0
+	for _, courseID := range enrollment.CoursesTaken[enrollment.CurrentSemester] {
+		course, err := s.GetCourse(ctx, courseID)
+		if err != nil {
+			return 0, err
+		}
+		credits += course.Credits
+	}
+	return credits, nil
+}
+
+// transferExclusions returns the passed courses that the last transfer of a student left out, sorted
+func transferExclusions(enrollment Enrollment) []string {
+	if len(enrollment.Transfers) == 0 {
+		return nil
+	}
+	excludedCourses := append([]string{}, enrollment.Transfers[len(enrollment.Transfers)-1].ExcludedCourses...)
+	sort.Strings(excludedCourses)
+	return excludedCourses
+}
+
+// isFailingGrade reports whether a grade fails the course, so that its credits are not earned
+func isFailingGrade(grade string) bool {
+	return strings.ToUpper(grade) == "F"
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// creditSummary returns the credit summary of a student
+func (c *testContract) creditSummary(studentID string) CreditSummary {
+	c.t.Helper()
+	var summary CreditSummary
+	c.query(&summary, "GetCreditSummary", studentID)
+	return summary
+}
+
+func TestGetCreditSummary(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	c.mustInvoke("AddCoursesToCurrentSemester", "S1", `["CS101","CS102"]`)
+	c.mustInvoke("DropCoursesFromCurrentSemester", "S1", `["CS102"]`)
+	if summary := c.creditSummary("S1"); summary.InProgress != 37 || summary.ThisSemester != 37 || summary.Attempted != 0 {
+		t.Fatalf("summary after dropping a course is %+v", summary)
+	}
+
+	// Failed courses are attempted but not earned, and a grade change moves their credits
+	c.mustInvoke("AddCoursesToCurrentSemester", "S1", `["CS102"]`)
+	c.mustInvoke("AddResultForCurrentSemester", "S1", `[{"courseID":"CS101","grade":"A"},{"courseID":"CS102","grade":"F"}]`)
+	tests := []struct {
+		courseID string
+		grade    string
+		earned   int
+	}{
+		{"", "", 37},
+		{"CS102", "B", 74},
+		{"CS101", "F", 37},
+	}
+	for _, test := range tests {
+		if test.courseID != "" {
+			c.mustInvoke("UpdateGradeForCourse", "S1", test.courseID, test.grade)
+		}
+		summary := c.creditSummary("S1")
+		if summary.Attempted != 74 || summary.Earned != test.earned || summary.InProgress != 0 || summary.ThisSemester != 74 {
+			t.Fatalf("summary after grading %s %s is %+v, want %d earned", test.courseID, test.grade, summary, test.earned)
+		}
+		if completed := c.enrollment("S1").CreditsCompleted; completed != test.earned {
+			t.Fatalf("credits completed after grading %s %s are %d, want %d", test.courseID, test.grade, completed, test.earned)
+		}
+	}
+}
+
+func TestGetCreditSummaryOfPrivateResults(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.privateResults()
+	c.transient(map[string]string{"results": `[{"courseID":"CS102","grade":"F"}]`, "salt": testResultSalt})
+	c.mustInvoke("AddPrivateResultForCurrentSemester", "S1")
+	c.transient(nil)
+
+	// The summary is read from the private grades, the public enrollment keeps no credits
+	if summary := c.creditSummary("S1"); summary.Attempted != 74 || summary.Earned != 37 {
+		t.Fatalf("summary of private results is %+v", summary)
+	}
+	c.transient(map[string]string{"grade": "C", "salt": testResultSalt})
+	c.mustInvoke("UpdatePrivateGradeForCourse", "S1", "CS102")
+	c.transient(nil)
+	if summary := c.creditSummary("S1"); summary.Earned != 74 {
+		t.Fatalf("summary after passing CS102 is %+v", summary)
+	}
+	if completed := c.enrollment("S1").CreditsCompleted; completed != 0 {
+		t.Fatalf("public credits completed are %d, want 0", completed)
+	}
+}
+
+func TestRecomputeEnrollment(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.passCourses(`["CS101","CS102"]`, `[{"courseID":"CS101","grade":"A"},{"courseID":"CS102","grade":"F"}]`)
+	c.mustInvoke("ChangeStudentStatus", "S1", studentOnLeave, "2", "Medical")
+
+	// Corrupt the derived fields of the enrollment
+	enrollment := c.enrollment("S1")
+	enrollment.CreditsCompleted = 999
+	enrollment.CreditsThisSemester = 3
+	enrollment.Status = studentSuspended
+	enrollment.LeaveSemesters = 0
+	enrollmentJSON, err := json.Marshal(enrollment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.stub.MockTransactionStart("corrupt")
+	if err := putEntityState(c.context(), enrollmentObjectType, "S1", enrollmentJSON); err != nil {
+		t.Fatal(err)
+	}
+	c.stub.MockTransactionEnd("corrupt")
+
+	c.mustInvoke("RecomputeEnrollment", "S1")
+	want := `Recomputed enrollment of student S1: creditsCompleted 999 -> 37, creditsThisSemester 3 -> 74, status "suspended" -> "on_leave", leaveSemesters 0 -> 2`
+	if entry := c.lastLedgerUpdate(); entry != want {
+		t.Fatalf("ledger update is %q, want %q", entry, want)
+	}
+	enrollment = c.enrollment("S1")
+	if enrollment.CreditsCompleted != 37 || enrollment.CreditsThisSemester != 74 || enrollment.Status != studentOnLeave || enrollment.LeaveSemesters != 2 {
+		t.Fatalf("recomputed enrollment is %+v", enrollment)
+	}
+
+	// Recomputing again changes nothing
+	c.mustInvoke("RecomputeEnrollment", "S1")
+	if entry := c.lastLedgerUpdate(); entry != "Recomputed enrollment of student S1: no changes" {
+		t.Fatalf("ledger update is %q", entry)
+	}
+	c.mustFail("does not exist", "RecomputeEnrollment", "S9")
+}
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// ElectiveGroup is a bucket of courses from which a student must earn a minimum number of credits
+type ElectiveGroup struct {
+	GroupID    string   `json:"groupID"`
+	GroupName  string   `json:"groupName"`
+	Courses    []string `json:"courses"`
+	MinCredits int      `json:"minCredits"`
+}
+
+// Curriculum defines the courses that satisfy the degree of a program offered by a department
+type Curriculum struct {
+	ProgramType    string              `json:"programType"`
+	DepartmentID   string              `json:"department"`
+	CoreCourses    []string            `json:"coreCourses"`    // Courses every student must pass
+	ElectiveGroups []ElectiveGroup     `json:"electiveGroups"` // Elective buckets with minimum credits
+	SemesterPlan   map[string][]string `json:"semesterPlan"`   // Map of semester to list of recommended course IDs
+	SchemaVersion  int                 `json:"schemaVersion"`
+}
+
+// BucketProgress represents a student's progress against one bucket of the curriculum
+type BucketProgress struct {
+	BucketID          string   `json:"bucketID"`
+	BucketName        string   `json:"bucketName"`
+	BucketType        string   `json:"bucketType"` // core, elective or free
+	CompletedCourses  []string `json:"completedCourses"`
+	InProgressCourses []string `json:"inProgressCourses"`
+	FailedCourses     []string `json:"failedCourses"`
+	RemainingCourses  []string `json:"remainingCourses"` // Core courses not yet taken, empty for other buckets
+	CreditsEarned     int      `json:"creditsEarned"`
+	CreditsRequired   int      `json:"creditsRequired"`
+	Satisfied         bool     `json:"satisfied"`
+}
+
+// PlannedCourse represents the status of a course recommended in the semester plan
+type PlannedCourse struct {
+	CourseID string `json:"courseID"`
+	Status   string `json:"status"` // completed, in progress, failed or pending
+}
+
+// PlannedSemester represents the status of the recommended courses of a semester
+type PlannedSemester struct {
+	Semester string          `json:"semester"`
+	Courses  []PlannedCourse `json:"courses"`
+}
+
+// CurriculumProgress represents a student's progress against the curriculum of their program and department
+type CurriculumProgress struct {
+	StudentID       string            `json:"studentID"`
+	ProgramType     string            `json:"programType"`
+	DepartmentID    string            `json:"department"`
+	CurrentSemester string            `json:"currentSemester"`
+	Buckets         []BucketProgress  `json:"buckets"`
+	SemesterPlan    []PlannedSemester `json:"semesterPlan"`
+	Complete        bool              `json:"complete"` // True when every core and elective bucket is satisfied
+}
+
+// Statuses of a course with respect to a student's results
+const (
+	courseStatusCompleted  = "completed"
+	courseStatusInProgress = "in progress"
+	courseStatusFailed     = "failed"
+	courseStatusPending    = "pending"
+)
+
+// curriculumKey returns the composite key the curriculum of a program offered by a department is stored under
+func curriculumKey(ctx contractapi.TransactionContextInterface, programType string, departmentID string) (string, error) {
+	return entityKey(ctx, curriculumObjectType, programType, departmentID)
+}
+
+// SetCurriculum adds or replaces the curriculum of a program offered by a department
+func (s *StudentRecordContract) SetCurriculum(ctx contractapi.TransactionContextInterface, programType string, departmentID string, curriculumJSON string) error {
+	var curriculum Curriculum
+	if err := json.Unmarshal([]byte(curriculumJSON), &curriculum); err != nil {
+		return fmt.Errorf("unmarhsal error")
+	}
+
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can set a curriculum")
+	}
+
+	// Check if the program type is valid
+	program, programExists, err := readProgram(ctx, programType)
+	if err != nil {
+		return err
+	}
+	if !programExists {
+		return fmt.Errorf("Invalid program type: %s", programType)
+	}
+
+	// Check if the departmentID is valid
+	departmentJSON, err := getEntityState(ctx, departmentObjectType, departmentID)
+	if err != nil {
+		return err
+	}
+	if departmentJSON == nil {
+		return fmt.Errorf("Department ID %s is not valid", departmentID)
+	}
+
+	curriculum.ProgramType = programType
+	curriculum.DepartmentID = departmentID
+	curriculum.SchemaVersion = schemaVersion(curriculumObjectType)
+	if curriculum.CoreCourses == nil {
+		curriculum.CoreCourses = []string{}
+	}
+	if curriculum.ElectiveGroups == nil {
+		curriculum.ElectiveGroups = []ElectiveGroup{}
+	}
+	if curriculum.SemesterPlan == nil {
+		curriculum.SemesterPlan = make(map[string][]string)
+	}
+
+	// Validate the core courses, a course can belong to only one bucket
+	bucketOfCourse := make(map[string]string)
+	for _, courseID := range curriculum.CoreCourses {
+		if _, err := s.GetCourse(ctx, courseID); err != nil {
+			return err
+		}
+		if _, exists := bucketOfCourse[courseID]; exists {
+			return fmt.Errorf("Course %s is listed more than once in the curriculum", courseID)
+		}
+		bucketOfCourse[courseID] = "core"
+	}
+
+	// Validate the elective groups
+	groupIDs := make(map[string]bool)
+	for index, group := range curriculum.ElectiveGroups {
+		if group.GroupID == "" {
+			return fmt.Errorf("Elective group at position %d has no ID", index+1)
+		}
+		if groupIDs[group.GroupID] {
+			return fmt.Errorf("Elective group %s is defined more than once", group.GroupID)
+		}
+		groupIDs[group.GroupID] = true
+
+		if group.MinCredits < 0 {
+			return fmt.Errorf("Minimum credits of elective group %s cannot be negative", group.GroupID)
+		}
+
+		groupCredits := 0
+		for _, courseID := range group.Courses {
+			course, err := s.GetCourse(ctx, courseID)
+			if err != nil {
+				return err
+			}
+			if bucket, exists := bucketOfCourse[courseID]; exists {
+				return fmt.Errorf("Course %s of elective group %s is already part of %s", courseID, group.GroupID, bucket)
+			}
+			bucketOfCourse[courseID] = fmt.Sprintf("elective group %s", group.GroupID)
+			groupCredits += course.Credits
+		}
+		if group.MinCredits > groupCredits {
+			return fmt.Errorf("Elective group %s requires %d credits but its courses offer only %d", group.GroupID, group.MinCredits, groupCredits)
+		}
+		if group.Courses == nil {
+			curriculum.ElectiveGroups[index].Courses = []string{}
+		}
+	}
+
+	// Validate the semester-wise recommended plan
+	for semester, courseIDs := range curriculum.SemesterPlan {
+		semesterNumber := extractSemesterNumber(semester)
+		if semesterNumber < 1 || semesterNumber > program.MaxSemesters {
+			return fmt.Errorf("Semester %s in the plan is not valid for program %s", semester, programType)
+		}
+		for _, courseID := range courseIDs {
+			if _, err := s.GetCourse(ctx, courseID); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Marshal and store the curriculum in the ledger
+	curriculumBytes, err := json.Marshal(curriculum)
+	if err != nil {
+		return err
+	}
+	key, err := curriculumKey(ctx, programType, departmentID)
+	if err != nil {
+		return err
+	}
+	err = ctx.GetStub().PutState(key, curriculumBytes)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Set curriculum for program %s in department %s", programType, departmentID)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetCurriculum retrieves the curriculum of a program offered by a department from the ledger
+func (s *StudentRecordContract) GetCurriculum(ctx contractapi.TransactionContextInterface, programType string, departmentID string) (*Curriculum, error) {
+	curriculum, err := s.findCurriculum(ctx, programType, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	if curriculum == nil {
+		return nil, fmt.Errorf("Curriculum for program %s in department %s does not exist", programType, departmentID)
+	}
+
+	return curriculum, nil
+}
+
+// findCurriculum reads the curriculum of a program offered by a department, returning nil if none is defined
+func (s *StudentRecordContract) findCurriculum(ctx contractapi.TransactionContextInterface, programType string, departmentID string) (*Curriculum, error) {
+	key, err := curriculumKey(ctx, programType, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	curriculumBytes, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read curriculum for program %s in department %s: %v", programType, departmentID, err)
+	}
+	curriculumBytes, err = upgradeDocument(curriculumObjectType, curriculumBytes)
+	if err != nil {
+		return nil, err
+	}
+	if curriculumBytes == nil {
+		return nil, nil
+	}
+
+	var curriculum Curriculum
+	err = json.Unmarshal(curriculumBytes, &curriculum)
+	if err != nil {
+		return nil, err
+	}
+
+	return &curriculum, nil
+}
+
+// GetCurriculumProgress shows a student's progress against each bucket of the curriculum of their program and department
+func (s *StudentRecordContract) GetCurriculumProgress(ctx contractapi.TransactionContextInterface, studentID string) (*CurriculumProgress, error) {
+	// Fetch the student's existing enrollment
+	enrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	curriculum, err := s.GetCurriculum(ctx, enrollment.ProgramType, enrollment.DepartmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildCurriculumProgress(ctx, enrollment, curriculum)
+}
+
+// courseStatuses returns the status of every course taken by the student, based on their results
+func courseStatuses(enrollment Enrollment, allSemesterResults map[string][]Result) map[string]string {
+	statuses := make(map[string]string)
+
+	for _, courseIDs := range enrollment.CoursesTaken {
+		for _, courseID := range courseIDs {
+			statuses[courseID] = courseStatusInProgress
+		}
+	}
+
+	for _, semesterResults := range allSemesterResults {
+		for _, result := range semesterResults {
+			if isFailingGrade(result.Grade) {
+				statuses[result.CourseID] = courseStatusFailed
+			} else {
+				statuses[result.CourseID] = courseStatusCompleted
+			}
+		}
+	}
+
+	return statuses
+}
+
+// buildCurriculumProgress evaluates the enrollment against the core, elective and free buckets and the semester plan
+func (s *StudentRecordContract) buildCurriculumProgress(ctx contractapi.TransactionContextInterface, enrollment Enrollment, curriculum *Curriculum) (*CurriculumProgress, error) {
+	// Read the results, including private grades
+	allSemesterResults, err := s.getSemesterResults(ctx, enrollment)
+	if err != nil {
+		return nil, err
+	}
+	statuses := courseStatuses(enrollment, allSemesterResults)
+	inCurriculum := make(map[string]bool)
+
+	// fillBucket sorts the given courses into the bucket by their status
+	fillBucket := func(bucket *BucketProgress, courseIDs []string) error {
+		for _, courseID := range courseIDs {
+			inCurriculum[courseID] = true
+			switch statuses[courseID] {
+			case courseStatusCompleted:
+				course, err := s.GetCourse(ctx, courseID)
+				if err != nil {
+					return err
+				}
+				bucket.CompletedCourses = append(bucket.CompletedCourses, courseID)
+				bucket.CreditsEarned += course.Credits
+			case courseStatusInProgress:
+				bucket.InProgressCourses = append(bucket.InProgressCourses, courseID)
+			case courseStatusFailed:
+				bucket.FailedCourses = append(bucket.FailedCourses, courseID)
+			}
+		}
+		return nil
+	}
+
+	progress := CurriculumProgress{
+		StudentID:       enrollment.StudentID,
+		ProgramType:     enrollment.ProgramType,
+		DepartmentID:    enrollment.DepartmentID,
+		CurrentSemester: enrollment.CurrentSemester,
+		Buckets:         []BucketProgress{},
+		SemesterPlan:    []PlannedSemester{},
+		Complete:        true,
+	}
+
+	// Core bucket: every core course has to be passed
+	core := newBucketProgress("CORE", "Core courses", "core")
+	if err := fillBucket(&core, curriculum.CoreCourses); err != nil {
+		return nil, err
+	}
+	for _, courseID := range curriculum.CoreCourses {
+		if _, taken := statuses[courseID]; !taken {
+			core.RemainingCourses = append(core.RemainingCourses, courseID)
+		}
+		course, err := s.GetCourse(ctx, courseID)
+		if err != nil {
+			return nil, err
+		}
+		core.CreditsRequired += course.Credits
+	}
+	core.Satisfied = len(core.CompletedCourses) == len(curriculum.CoreCourses)
+	progress.Buckets = append(progress.Buckets, core)
+	progress.Complete = progress.Complete && core.Satisfied
+
+	// Elective buckets: the minimum credits of each group have to be earned
+	for _, group := range curriculum.ElectiveGroups {
+		elective := newBucketProgress(group.GroupID, group.GroupName, "elective")
+		if err := fillBucket(&elective, group.Courses); err != nil {
+			return nil, err
+		}
+		elective.CreditsRequired = group.MinCredits
+		elective.Satisfied = elective.CreditsEarned >= group.MinCredits
+		progress.Buckets = append(progress.Buckets, elective)
+		progress.Complete = progress.Complete && elective.Satisfied
+	}
+
+	// Free bucket: courses taken outside the curriculum
+	var freeCourses []string
+	for courseID := range statuses {
+		if !inCurriculum[courseID] {
+			freeCourses = append(freeCourses, courseID)
+		}
+	}
+	sort.Strings(freeCourses)
+	free := newBucketProgress("FREE", "Courses outside the curriculum", "free")
+	if err := fillBucket(&free, freeCourses); err != nil {
+		return nil, err
+	}
+	free.Satisfied = true
+	progress.Buckets = append(progress.Buckets, free)
+
+	// Semester-wise recommended plan, in semester order
+	semesters := make([]string, 0, len(curriculum.SemesterPlan))
+	for semester := range curriculum.SemesterPlan {
+		semesters = append(semesters, semester)
+	}
+	sortSemesters(semesters)
+	for _, semester := range semesters {
+		planned := PlannedSemester{Semester: semester, Courses: []PlannedCourse{}}
+		for _, courseID := range curriculum.SemesterPlan[semester] {
+			status, taken := statuses[courseID]
+			if !taken {
+				status = courseStatusPending
+			}
+			planned.Courses = append(planned.Courses, PlannedCourse{CourseID: courseID, Status: status})
+		}
+		progress.SemesterPlan = append(progress.SemesterPlan, planned)
+	}
+
+	return &progress, nil
+}
+
+func newBucketProgress(bucketID string, bucketName string, bucketType string) BucketProgress {
+	return BucketProgress{
+		BucketID:          bucketID,
+		BucketName:        bucketName,
+		BucketType:        bucketType,
+		CompletedCourses:  []string{},
+		InProgressCourses: []string{},
+		FailedCourses:     []string{},
+		RemainingCourses:  []string{},
+	}
+}
+
+// sortSemesters sorts semester names such as Semester2 and Semester10 by their semester number
+func sortSemesters(semesters []string) {
+	sort.Slice(semesters, func(i, j int) bool {
+		return extractSemesterNumber(semesters[i]) < extractSemesterNumber(semesters[j])
+	})
+}
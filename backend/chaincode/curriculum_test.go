@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const testCurriculum = `{"coreCourses":["CS101","CS201"],` +
+	`"electiveGroups":[{"groupID":"E1","groupName":"Systems","courses":["CS202","CS301"],"minCredits":37}],` +
+	`"semesterPlan":{"Semester2":["CS201","CS202"],"Semester1":["CS101","CS102"]}}`
+
+func TestSetCurriculum(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	c.mustFail("Invalid program type", "SetCurriculum", "PHD", "CSE", testCurriculum)
+	c.mustFail("is not valid", "SetCurriculum", "BTECH", "EE", testCurriculum)
+	c.mustFail("more than once", "SetCurriculum", "BTECH", "CSE", `{"coreCourses":["CS101","CS101"]}`)
+	c.mustFail("offer only 37", "SetCurriculum", "BTECH", "CSE",
+		`{"coreCourses":["CS101"],"electiveGroups":[{"groupID":"E1","courses":["CS102"],"minCredits":100}]}`)
+	c.mustFail("already part of", "SetCurriculum", "BTECH", "CSE",
+		`{"coreCourses":["CS101"],"electiveGroups":[{"groupID":"E1","courses":["CS101"],"minCredits":0}]}`)
+	c.mustFail("not valid for program", "SetCurriculum", "BTECH", "CSE", `{"coreCourses":["CS101"],"semesterPlan":{"Semester9":["CS101"]}}`)
+	c.mustFail("does not exist", "GetCurriculum", "BTECH", "CSE")
+
+	c.mustInvoke("SetCurriculum", "BTECH", "CSE", testCurriculum)
+	c.restart()
+	var curriculum Curriculum
+	c.query(&curriculum, "GetCurriculum", "BTECH", "CSE")
+	if strings.Join(curriculum.CoreCourses, ",") != "CS101,CS201" || len(curriculum.ElectiveGroups) != 1 {
+		t.Fatalf("curriculum is %+v", curriculum)
+	}
+}
+
+func TestGetCurriculumProgress(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("SetCurriculum", "BTECH", "CSE", testCurriculum)
+
+	c.passCourses(`["CS101","CS102"]`, `[{"courseID":"CS101","grade":"A"},{"courseID":"CS102","grade":"F"}]`)
+	c.mustInvoke("EnrollStudentIntoNextSemester", "S1")
+	c.mustInvoke("AddCoursesToCurrentSemester", "S1", `["CS201"]`)
+
+	var progress CurriculumProgress
+	c.query(&progress, "GetCurriculumProgress", "S1")
+	if progress.Complete || len(progress.Buckets) != 3 {
+		t.Fatalf("progress is %+v", progress)
+	}
+
+	core := progress.Buckets[0]
+	if core.BucketType != "core" || strings.Join(core.CompletedCourses, ",") != "CS101" ||
+		strings.Join(core.InProgressCourses, ",") != "CS201" || core.CreditsEarned != 37 || core.CreditsRequired != 74 || core.Satisfied {
+		t.Fatalf("core bucket is %+v", core)
+	}
+	elective := progress.Buckets[1]
+	if elective.BucketID != "E1" || elective.CreditsEarned != 0 || elective.CreditsRequired != 37 || elective.Satisfied {
+		t.Fatalf("elective bucket is %+v", elective)
+	}
+	free := progress.Buckets[2]
+	if free.BucketType != "free" || strings.Join(free.FailedCourses, ",") != "CS102" {
+		t.Fatalf("free bucket is %+v", free)
+	}
+
+	if len(progress.SemesterPlan) != 2 || progress.SemesterPlan[0].Semester != "Semester1" {
+		t.Fatalf("semester plan is %+v, want Semester1 and Semester2 in order", progress.SemesterPlan)
+	}
+	want := []PlannedCourse{{"CS201", courseStatusInProgress}, {"CS202", courseStatusPending}}
+	for i, course := range progress.SemesterPlan[1].Courses {
+		if course != want[i] {
+			t.Fatalf("Semester2 of the plan is %+v, want %+v", progress.SemesterPlan[1].Courses, want)
+		}
+	}
+
+	// The unsatisfied elective group keeps the student from graduating
+	audit := c.degreeAudit("S1")
+	if strings.Join(audit.UnsatisfiedGroups, ",") != "E1" || strings.Join(audit.MissingCoreCourses, ",") != "CS201" {
+		t.Fatalf("audit is %+v, want group E1 unsatisfied and core course CS201 missing", audit)
+	}
+}
+
+func TestSortSemesters(t *testing.T) {
+	semesters := []string{"Semester10", "Semester2", "Semester1"}
+	sortSemesters(semesters)
+	if strings.Join(semesters, ",") != "Semester1,Semester2,Semester10" {
+		t.Fatalf("sortSemesters returned %v", semesters)
+	}
+}
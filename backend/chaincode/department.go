@@ -10,55 +10,63 @@ import (
 type Department struct {
 	DepartmentID   string `json:"departmentID"`
 	DepartmentName string `json:"departmentName"`
+	Archived       bool   `json:"archived"` // Archived departments cannot take new faculty, courses or students
+	Version        int    `json:"version"`  // Number of edits made to the department
+	HeadID         string `json:"headID"`   // Faculty heading the department, who approves its requests
+	SchemaVersion  int    `json:"schemaVersion"`
 }
 
-var departmentMapping = make(map[string]Department)
-
 // AddDepartment adds a new department to the ledger
 func (s *StudentRecordContract) AddDepartment(ctx contractapi.TransactionContextInterface, departmentID string, departmentName string) error {
-	// Check if the department already exists
-	departmentKey := fmt.Sprintf("DEPARTMENT-%s", departmentID)
-	departmentJSON, err := ctx.GetStub().GetState(departmentKey)
+	err := s.createDepartment(ctx, departmentID, departmentName)
 	if err != nil {
 		return err
 	}
-	if departmentJSON != nil {
-		return fmt.Errorf("Department with ID %s already exists", departmentID)
-	}
-
-	// Create a new department
-	newDepartment := Department{
-		DepartmentID:   departmentID,
-		DepartmentName: departmentName,
-	}
 
-	// Marshal and store the department in the ledger
-	departmentJSON, err = json.Marshal(newDepartment)
+	// Record the ledger update
+	entry := fmt.Sprintf("Added new department: %s", departmentID)
+	err = s.recordLedgerUpdate(ctx, entry)
 	if err != nil {
 		return err
 	}
-	err = ctx.GetStub().PutState(departmentKey, departmentJSON)
+
+	return nil
+}
+
+// createDepartment stores a new department, without recording a ledger update
+func (s *StudentRecordContract) createDepartment(ctx contractapi.TransactionContextInterface, departmentID string, departmentName string) error {
+	newDepartment, err := s.buildDepartment(ctx, departmentID, departmentName)
 	if err != nil {
 		return err
 	}
 
-	departmentMapping[newDepartment.DepartmentID] = newDepartment
+	// Marshal and store the department in the ledger
+	return putCatalogState(ctx, departmentObjectType, departmentID, newDepartment)
+}
 
-	// Record the ledger update
-	entry := fmt.Sprintf("Added new department: %s", departmentID)
-	err = s.recordLedgerUpdate(ctx, entry)
+// buildDepartment checks and returns a new department, without storing it
+func (s *StudentRecordContract) buildDepartment(ctx contractapi.TransactionContextInterface, departmentID string, departmentName string) (Department, error) {
+	// Check if the department already exists
+	departmentJSON, err := getEntityState(ctx, departmentObjectType, departmentID)
 	if err != nil {
-		return err
+		return Department{}, err
+	}
+	if departmentJSON != nil {
+		return Department{}, fmt.Errorf("Department with ID %s already exists", departmentID)
 	}
 
-	return nil
+	// Create a new department
+	return Department{
+		DepartmentID:   departmentID,
+		DepartmentName: departmentName,
+		SchemaVersion:  schemaVersion(departmentObjectType),
+	}, nil
 }
 
 // RemoveDepartment removes a department from the ledger
 func (s *StudentRecordContract) RemoveDepartment(ctx contractapi.TransactionContextInterface, departmentID string) error {
 	// Check if the department exists
-	departmentKey := fmt.Sprintf("DEPARTMENT-%s", departmentID)
-	departmentJSON, err := ctx.GetStub().GetState(departmentKey)
+	departmentJSON, err := getEntityState(ctx, departmentObjectType, departmentID)
 	if err != nil {
 		return err
 	}
@@ -66,13 +74,17 @@ func (s *StudentRecordContract) RemoveDepartment(ctx contractapi.TransactionCont
 		return fmt.Errorf("Department with ID %s does not exist", departmentID)
 	}
 
-	// Delete the department from the ledger
-	err = ctx.GetStub().DelState(departmentKey)
+	// Refuse to remove a department that other records refer to
+	err = s.ensureNoDependents(ctx, entityDepartment, departmentID)
 	if err != nil {
 		return err
 	}
 
-	delete(departmentMapping, departmentID)
+	// Delete the department from the ledger
+	err = delEntityState(ctx, departmentObjectType, departmentID)
+	if err != nil {
+		return err
+	}
 
 	// Record the ledger update
 	entry := fmt.Sprintf("Removed department: %s", departmentID)
@@ -86,7 +98,7 @@ func (s *StudentRecordContract) RemoveDepartment(ctx contractapi.TransactionCont
 
 // GetDepartment retrieves a department by its ID from the ledger
 func (s *StudentRecordContract) GetDepartment(ctx contractapi.TransactionContextInterface, departmentID string) (Department, error) {
-	departmentJSON, err := ctx.GetStub().GetState(fmt.Sprintf("DEPARTMENT-%s", departmentID))
+	departmentJSON, err := getEntityState(ctx, departmentObjectType, departmentID)
 	if err != nil {
 		return Department{}, fmt.Errorf("Failed to read department with ID %s: %v", departmentID, err)
 	}
@@ -107,8 +119,16 @@ func (s *StudentRecordContract) GetDepartment(ctx contractapi.TransactionContext
 func (s *StudentRecordContract) GetAllDepartments(ctx contractapi.TransactionContextInterface) ([]Department, error) {
 	departments := make([]Department, 0)
 
-	for _, department := range departmentMapping {
+	err := forEachEntity(ctx, departmentObjectType, func(value []byte) error {
+		var department Department
+		if err := json.Unmarshal(value, &department); err != nil {
+			return err
+		}
 		departments = append(departments, department)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return departments, nil
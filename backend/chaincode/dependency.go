@@ -0,0 +1,428 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Dependent is a record that refers to a catalog entity
+type Dependent struct {
+	EntityType string `json:"entityType"` // faculty, course, student, curriculum, program, activity or club
+	EntityID   string `json:"entityID"`
+	Relation   string `json:"relation"` // How the dependent refers to the entity
+}
+
+// DependencyReport lists the records that refer to a catalog entity
+// An entity with dependents cannot be removed, only archived
+type DependencyReport struct {
+	EntityType string      `json:"entityType"`
+	EntityID   string      `json:"entityID"`
+	Archived   bool        `json:"archived"`
+	Dependents []Dependent `json:"dependents"`
+	Removable  bool        `json:"removable"`
+}
+
+// Catalog entity types that can be archived
+const (
+	entityDepartment = "department"
+	entityFaculty    = "faculty"
+	entityCourse     = "course"
+	entityProgram    = "program"
+	entityActivity   = "activity"
+)
+
+// entityLabels are the names of the entity types used in messages
+var entityLabels = map[string]string{
+	entityDepartment: "Department",
+	entityFaculty:    "Faculty",
+	entityCourse:     "Course",
+	entityProgram:    "Program",
+	entityActivity:   "Extracurricular activity",
+}
+
+// GetDependents reports the records that refer to a department, faculty, course, program or activity
+func (s *StudentRecordContract) GetDependents(ctx contractapi.TransactionContextInterface, entityType string, entityID string) (*DependencyReport, error) {
+	archived, err := s.isEntityArchived(ctx, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	dependents, err := s.findDependents(ctx, entityType, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DependencyReport{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Archived:   archived,
+		Dependents: dependents,
+		Removable:  len(dependents) == 0,
+	}, nil
+}
+
+// ArchiveEntity archives a department, faculty, course, program or activity
+// Archived entities stay readable for the records that refer to them, but cannot be referred to by new records
+func (s *StudentRecordContract) ArchiveEntity(ctx contractapi.TransactionContextInterface, entityType string, entityID string) error {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can archive %s records", entityType)
+	}
+
+	archived, err := s.isEntityArchived(ctx, entityType, entityID)
+	if err != nil {
+		return err
+	}
+	if archived {
+		return fmt.Errorf("%s %s is already archived", entityLabels[entityType], entityID)
+	}
+
+	// The entity exists, isEntityArchived returned an error otherwise
+	switch entityType {
+	case entityDepartment:
+		var department Department
+		_, err = readEntity(ctx, departmentObjectType, entityID, &department)
+		if err != nil {
+			return err
+		}
+		department.Archived = true
+		department.Version++
+		err = putCatalogState(ctx, departmentObjectType, entityID, department)
+	case entityFaculty:
+		var faculty Faculty
+		_, err = readEntity(ctx, facultyObjectType, entityID, &faculty)
+		if err != nil {
+			return err
+		}
+		faculty.Archived = true
+		faculty.Version++
+		err = putCatalogState(ctx, facultyObjectType, entityID, faculty)
+	case entityCourse:
+		var course Course
+		_, err = readEntity(ctx, courseObjectType, entityID, &course)
+		if err != nil {
+			return err
+		}
+		course.Archived = true
+		course.Version++
+		err = putCatalogState(ctx, courseObjectType, entityID, course)
+	case entityProgram:
+		var program Program
+		program, _, err = readProgram(ctx, entityID)
+		if err != nil {
+			return err
+		}
+		program.Archived = true
+		program.Version++
+		err = putCatalogState(ctx, programObjectType, entityID, program)
+	case entityActivity:
+		var activity ExtracurricularActivity
+		activity, err = s.GetExtracurricularActivity(ctx, entityID)
+		if err != nil {
+			return err
+		}
+		activity.Archived = true
+		activity.Version++
+		err = s.putExtracurricularActivity(ctx, activity)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Archived %s: %s", entityType, entityID)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ensureNoDependents returns an error listing the dependents of an entity that is about to be removed
+func (s *StudentRecordContract) ensureNoDependents(ctx contractapi.TransactionContextInterface, entityType string, entityID string) error {
+	dependents, err := s.findDependents(ctx, entityType, entityID)
+	if err != nil {
+		return err
+	}
+	if len(dependents) == 0 {
+		return nil
+	}
+
+	listed := make([]string, 0, 5)
+	for _, dependent := range dependents {
+		if len(listed) == cap(listed) {
+			listed = append(listed, "...")
+			break
+		}
+		listed = append(listed, fmt.Sprintf("%s %s", dependent.EntityType, dependent.EntityID))
+	}
+	return fmt.Errorf("%s %s cannot be removed, it is referred to by %d record(s) (%s); archive it with ArchiveEntity instead", entityLabels[entityType], entityID, len(dependents), strings.Join(listed, ", "))
+}
+
+// findDependents returns the records that refer to an entity, read from the ledger in key order
+func (s *StudentRecordContract) findDependents(ctx contractapi.TransactionContextInterface, entityType string, entityID string) ([]Dependent, error) {
+	dependents := make([]Dependent, 0)
+	add := func(dependentType string, dependentID string, relation string) {
+		dependents = append(dependents, Dependent{EntityType: dependentType, EntityID: dependentID, Relation: relation})
+	}
+
+	switch entityType {
+	case entityDepartment:
+		err := forEachEntity(ctx, facultyObjectType, func(value []byte) error {
+			var faculty Faculty
+			if err := json.Unmarshal(value, &faculty); err != nil {
+				return err
+			}
+			if faculty.DepartmentID == entityID {
+				add(entityFaculty, faculty.FacultyID, "member of the department")
+			} else if contains(faculty.JointDepartments, entityID) {
+				add(entityFaculty, faculty.FacultyID, "jointly appointed to the department")
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = forEachEntity(ctx, courseObjectType, func(value []byte) error {
+			var course Course
+			if err := json.Unmarshal(value, &course); err != nil {
+				return err
+			}
+			if course.DepartmentID == entityID {
+				add(entityCourse, course.CourseID, "offered by the department")
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	case entityFaculty:
+		departmentIDs, err := headedDepartments(ctx, entityID)
+		if err != nil {
+			return nil, err
+		}
+		for _, departmentID := range departmentIDs {
+			add(entityDepartment, departmentID, "headed by the faculty")
+		}
+		err = forEachEntity(ctx, courseObjectType, func(value []byte) error {
+			var course Course
+			if err := json.Unmarshal(value, &course); err != nil {
+				return err
+			}
+			if course.FacultyID == entityID {
+				add(entityCourse, course.CourseID, "taught by the faculty")
+			} else if contains(course.CoInstructors, entityID) {
+				add(entityCourse, course.CourseID, "co-taught by the faculty")
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = forEachEntity(ctx, activityObjectType, func(value []byte) error {
+			var activity ExtracurricularActivity
+			if err := json.Unmarshal(value, &activity); err != nil {
+				return err
+			}
+			if activity.FacultyID == entityID {
+				add(entityActivity, activity.ActivityID, "faculty in charge")
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		err = forEachEntity(ctx, clubObjectType, func(value []byte) error {
+			var club Club
+			if err := json.Unmarshal(value, &club); err != nil {
+				return err
+			}
+			if contains(club.FacultyAdvisors, entityID) {
+				add("club", club.ClubID, "faculty advisor")
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	case entityCourse:
+		err := forEachEntity(ctx, programObjectType, func(value []byte) error {
+			var program Program
+			if err := json.Unmarshal(value, &program); err != nil {
+				return err
+			}
+			if contains(program.CoreCourses, entityID) {
+				add(entityProgram, program.Name, "core course of the program")
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	case entityProgram, entityActivity:
+	default:
+		return nil, fmt.Errorf("Invalid entity type %s: must be one of department, faculty, course, program or activity", entityType)
+	}
+
+	// Curricula refer to their department, program and courses
+	if entityType == entityDepartment || entityType == entityProgram || entityType == entityCourse {
+		err := forEachEntity(ctx, curriculumObjectType, func(value []byte) error {
+			var curriculum Curriculum
+			if err := json.Unmarshal(value, &curriculum); err != nil {
+				return err
+			}
+			curriculumID := fmt.Sprintf("%s-%s", curriculum.ProgramType, curriculum.DepartmentID)
+			switch {
+			case entityType == entityDepartment && curriculum.DepartmentID == entityID:
+				add("curriculum", curriculumID, "curriculum of the department")
+			case entityType == entityProgram && curriculum.ProgramType == entityID:
+				add("curriculum", curriculumID, "curriculum of the program")
+			case entityType == entityCourse && curriculumCourses(curriculum)[entityID]:
+				add("curriculum", curriculumID, "course in the curriculum")
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Students refer to their department, program and advisor, the courses they took and the activities they registered for
+	err := forEachEntity(ctx, enrollmentObjectType, func(value []byte) error {
+		var enrollment Enrollment
+		if err := json.Unmarshal(value, &enrollment); err != nil {
+			return err
+		}
+		switch entityType {
+		case entityDepartment:
+			if enrollment.DepartmentID == entityID {
+				add("student", enrollment.StudentID, "enrolled in the department")
+			}
+		case entityProgram:
+			if enrollment.ProgramType == entityID {
+				add("student", enrollment.StudentID, "enrolled in the program")
+			}
+		case entityCourse:
+			for _, courseIDs := range enrollment.CoursesTaken {
+				if contains(courseIDs, entityID) {
+					add("student", enrollment.StudentID, "took the course")
+					break
+				}
+			}
+		case entityFaculty:
+			if enrollment.AdvisorID == entityID {
+				add("student", enrollment.StudentID, "advised by the faculty")
+			}
+		case entityActivity:
+			if contains(enrollment.Extracurricular, entityID) {
+				add("student", enrollment.StudentID, "registered for the activity")
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dependents, nil
+}
+
+// hasStudentDependents reports whether any student record refers to an entity
+func (s *StudentRecordContract) hasStudentDependents(ctx contractapi.TransactionContextInterface, entityType string, entityID string) (bool, error) {
+	dependents, err := s.findDependents(ctx, entityType, entityID)
+	if err != nil {
+		return false, err
+	}
+	for _, dependent := range dependents {
+		if dependent.EntityType == "student" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isEntityArchived reports whether an entity is archived, and returns an error if it does not exist
+func (s *StudentRecordContract) isEntityArchived(ctx contractapi.TransactionContextInterface, entityType string, entityID string) (bool, error) {
+	switch entityType {
+	case entityDepartment:
+		var department Department
+		exists, err := readEntity(ctx, departmentObjectType, entityID, &department)
+		if err != nil {
+			return false, err
+		}
+		if !exists {
+			return false, fmt.Errorf("Department with ID %s does not exist", entityID)
+		}
+		return department.Archived, nil
+	case entityFaculty:
+		var faculty Faculty
+		exists, err := readEntity(ctx, facultyObjectType, entityID, &faculty)
+		if err != nil {
+			return false, err
+		}
+		if !exists {
+			return false, fmt.Errorf("Faculty with ID %s does not exist", entityID)
+		}
+		return faculty.Archived, nil
+	case entityCourse:
+		var course Course
+		exists, err := readEntity(ctx, courseObjectType, entityID, &course)
+		if err != nil {
+			return false, err
+		}
+		if !exists {
+			return false, fmt.Errorf("Course with ID %s does not exist", entityID)
+		}
+		return course.Archived, nil
+	case entityProgram:
+		program, exists, err := readProgram(ctx, entityID)
+		if err != nil {
+			return false, err
+		}
+		if !exists {
+			return false, fmt.Errorf("Program %s does not exist", entityID)
+		}
+		return program.Archived, nil
+	case entityActivity:
+		activity, err := s.GetExtracurricularActivity(ctx, entityID)
+		if err != nil {
+			return false, err
+		}
+		return activity.Archived, nil
+	default:
+		return false, fmt.Errorf("Invalid entity type %s: must be one of department, faculty, course, program or activity", entityType)
+	}
+}
+
+// curriculumCourses returns the set of courses a curriculum names
+func curriculumCourses(curriculum Curriculum) map[string]bool {
+	courses := make(map[string]bool)
+	for _, courseID := range curriculum.CoreCourses {
+		courses[courseID] = true
+	}
+	for _, group := range curriculum.ElectiveGroups {
+		for _, courseID := range group.Courses {
+			courses[courseID] = true
+		}
+	}
+	for _, courseIDs := range curriculum.SemesterPlan {
+		for _, courseID := range courseIDs {
+			courses[courseID] = true
+		}
+	}
+	return courses
+}
+
+// putCatalogState stores a department, faculty or course in the ledger
+func putCatalogState(ctx contractapi.TransactionContextInterface, objectType string, id string, entity interface{}) error {
+	entityJSON, err := json.Marshal(entity)
+	if err != nil {
+		return err
+	}
+	return putEntityState(ctx, objectType, id, entityJSON)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetDependents(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("SetCurriculum", "BTECH", "CSE", `{"coreCourses":["CS201"]}`)
+	c.mustInvoke("AddCoursesToCurrentSemester", "S1", `["CS101"]`)
+
+	tests := []struct {
+		entityType string
+		entityID   string
+		want       string
+	}{
+		{entityDepartment, "CSE", "faculty F1,course CS101,course CS102,course CS201,course CS202,course CS301,curriculum BTECH-CSE,student S1"},
+		{entityCourse, "CS101", "student S1"},
+		{entityCourse, "CS201", "curriculum BTECH-CSE"},
+		{entityCourse, "CS202", ""},
+		{entityProgram, "BTECH", "curriculum BTECH-CSE,student S1"},
+	}
+	for _, test := range tests {
+		var report DependencyReport
+		c.query(&report, "GetDependents", test.entityType, test.entityID)
+		dependents := make([]string, 0, len(report.Dependents))
+		for _, dependent := range report.Dependents {
+			dependents = append(dependents, dependent.EntityType+" "+dependent.EntityID)
+		}
+		if got := strings.Join(dependents, ","); got != test.want || report.Removable != (test.want == "") {
+			t.Errorf("dependents of %s %s are %q, removable %t, want %q", test.entityType, test.entityID, got, report.Removable, test.want)
+		}
+	}
+	c.mustFail("Invalid entity type widget", "GetDependents", "widget", "W1")
+	c.mustFail("does not exist", "GetDependents", entityCourse, "CS999")
+}
+
+func TestRemoveEntities(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddCoursesToCurrentSemester", "S1", `["CS101"]`)
+
+	c.mustFail("Course CS101 cannot be removed, it is referred to by 1 record(s) (student S1); archive it with ArchiveEntity instead", "RemoveCourse", "CS101")
+	c.mustFail("Program BTECH cannot be removed", "RemoveProgram", "BTECH")
+	c.mustFail("Faculty F1 cannot be removed, it is referred to by 5 record(s)", "RemoveFaculty", "F1")
+
+	// At most five dependents are listed
+	c.mustFail("referred to by 7 record(s) (faculty F1, course CS101, course CS102, course CS201, course CS202, ...)", "RemoveDepartment", "CSE")
+
+	c.mustInvoke("RemoveCourse", "CS301")
+	c.mustFail("does not exist", "GetCourse", "CS301")
+	c.mustInvoke("AddDepartment", "EE", "Electrical")
+	c.mustInvoke("RemoveDepartment", "EE")
+	c.mustFail("does not exist", "GetDepartment", "EE")
+}
+
+func TestArchiveEntity(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddExtracurricularActivity", "A1", "Chess", "Inter-hostel chess", "Hall 1", "01012030", "10", "F1")
+
+	archived := []struct {
+		entityType string
+		entityID   string
+	}{
+		{entityCourse, "CS102"},
+		{entityActivity, "A1"},
+		{entityProgram, "BTECH"},
+		{entityFaculty, "F1"},
+		{entityDepartment, "CSE"},
+	}
+	for _, entity := range archived {
+		c.mustInvoke("ArchiveEntity", entity.entityType, entity.entityID)
+		c.mustFail("is already archived", "ArchiveEntity", entity.entityType, entity.entityID)
+	}
+	c.mustFail("does not exist", "ArchiveEntity", entityCourse, "CS999")
+
+	// Archived entities stay readable for the records that refer to them, but new records cannot refer to them
+	var course Course
+	c.query(&course, "GetCourse", "CS102")
+	if !course.Archived || course.Version != 1 {
+		t.Fatalf("archived course is %+v", course)
+	}
+	c.mustFail("Course CS102 is archived", "AddCoursesToCurrentSemester", "S1", `["CS102"]`)
+	c.mustFail("Extracurricular activity A1 is archived", "AddExtracurricularActivityForStudent", "S1", "A1")
+	c.mustFail("Department CSE is archived", "InitialEnrollment", "S2", "Ravi", "BTECH", "CSE")
+	c.mustFail("Department CSE is archived", "AddFaculty", "F2", "Ravi Kumar", "CSE")
+	c.mustFail("Faculty F1 is archived", "AddExtracurricularActivity", "A2", "Debate", "Debating society", "Hall 2", "01012030", "10", "F1")
+
+	var report DependencyReport
+	c.query(&report, "GetDependents", entityFaculty, "F1")
+	if !report.Archived || report.Removable || len(report.Dependents) != 6 {
+		t.Fatalf("dependency report of an archived faculty is %+v, want its 5 courses and activity", report)
+	}
+}
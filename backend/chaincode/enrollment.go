@@ -9,57 +9,111 @@ import (
 
 // Enrollment represents details required during initial enrollment
 type Enrollment struct {
-	StudentID           string              `json:"studentID"`
-	Name                string              `json:"name"`
-	ProgramType         string              `json:"programType"`
-	DepartmentID        string              `json:"department"`
-	CreditsCompleted    int                 `json:"creditsCompleted"`
-	CreditsThisSemester int                 `json:"creditsThisSemester"`
-	CurrentSemester     string              `json:"currentSemester"` // Current semester for the student
-	SemesterResults     map[string][]Result `json:"semesterResults"` // Map of sem to list of Result
-	CoursesTaken        map[string][]string `json:"coursesTaken"`    // Map of semester to list of course IDs
-	Extracurricular     []string            `json:"extracurricular"` // List of extracurricular activity IDs
-	Certificates        []Certificate       `json:"certificates"`    // List of certificates associated with the enrollment
+	StudentID           string                   `json:"studentID"`
+	Name                string                   `json:"name"`
+	ProgramType         string                   `json:"programType"`
+	DepartmentID        string                   `json:"department"`
+	CreditsCompleted    int                      `json:"creditsCompleted"`
+	CreditsThisSemester int                      `json:"creditsThisSemester"`
+	CurrentSemester     string                   `json:"currentSemester"`    // Current semester for the student
+	SemesterResults     map[string][]Result      `json:"semesterResults"`    // Map of sem to list of Result
+	CoursesTaken        map[string][]string      `json:"coursesTaken"`       // Map of semester to list of course IDs
+	Extracurricular     []string                 `json:"extracurricular"`    // List of extracurricular activity IDs
+	Certificates        []Certificate            `json:"certificates"`       // List of certificates associated with the enrollment
+	Participation       map[string]Participation `json:"participation"`      // Map of activity ID to the student's participation
+	Frozen              bool                     `json:"frozen"`             // Set once the degree is conferred, no further changes are allowed
+	Graduation          Graduation               `json:"graduation"`         // Degree conferral details, empty until graduation
+	PrivateResults      bool                     `json:"privateResults"`     // Grades are kept in the grades collection, the public results only list the courses
+	Status              string                   `json:"status"`             // Lifecycle status: active, on_leave, suspended, withdrawn, graduated or transferred
+	StatusHistory       []StatusChange           `json:"statusHistory"`      // Every change of the status, starting with the initial enrollment
+	LeaveSemesters      int                      `json:"leaveSemesters"`     // Semesters on leave, which do not count against the program maximum
+	SuspendedSemesters  int                      `json:"suspendedSemesters"` // Semesters of suspension, which count against the program maximum
+	Transfers           []TransferRecord         `json:"transfers"`          // Transfers to other programs or departments, oldest first
+	AdvisorID           string                   `json:"advisorID"`          // Faculty advisor who approves the student's course plans
+	CoursePlans         []CoursePlan             `json:"coursePlans"`        // Course plans submitted for approval, oldest first
+	SchemaVersion       int                      `json:"schemaVersion"`
 }
 
-var enrollmentMapping = make(map[string]Enrollment)
-
 // InitialEnrollment enrolls a new student into the first semester with basic details
 func (s *StudentRecordContract) InitialEnrollment(ctx contractapi.TransactionContextInterface, studentID string, name string, programType string, departmentID string) error {
+	// Check if the program type is valid
+	program, programExists, err := readProgram(ctx, programType)
+	if err != nil {
+		return err
+	}
+	if !programExists {
+		return fmt.Errorf("Invalid program type: %s", programType)
+	}
+
+	initialEnrollment, err := s.createEnrollment(ctx, studentID, name, program, departmentID)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Enrolled student %s into %s", studentID, initialEnrollment.CurrentSemester)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// createEnrollment stores a new student and their first semester enrollment in the program, without recording a ledger update
+func (s *StudentRecordContract) createEnrollment(ctx contractapi.TransactionContextInterface, studentID string, name string, program Program, departmentID string) (Enrollment, error) {
+	student, initialEnrollment, err := s.buildEnrollment(ctx, nil, studentID, name, program, departmentID)
+	if err != nil {
+		return Enrollment{}, err
+	}
+
+	err = storeEnrollment(ctx, student, initialEnrollment)
+	if err != nil {
+		return Enrollment{}, err
+	}
+
+	return initialEnrollment, nil
+}
+
+// buildEnrollment checks and returns a new student and their enrollment into the first semester, without storing them
+// The department is looked up in the staged records before the ledger
+func (s *StudentRecordContract) buildEnrollment(ctx contractapi.TransactionContextInterface, staged *stagedRecords, studentID string, name string, program Program, departmentID string) (Student, Enrollment, error) {
+	programType := program.Name
+
 	// Check if the student already exists
 	_, err := s.GetStudent(ctx, studentID)
 	if err == nil {
-		return fmt.Errorf("Student with ID %s already exists", studentID)
+		return Student{}, Enrollment{}, fmt.Errorf("Student with ID %s already exists", studentID)
 	}
 
 	// Check if the departmentID is valid
-	_, departmentExists := departmentMapping[departmentID]
+	department, departmentExists, err := staged.lookupDepartment(ctx, departmentID)
+	if err != nil {
+		return Student{}, Enrollment{}, err
+	}
 	if !departmentExists {
-		return fmt.Errorf("Department ID %s is not valid", departmentID)
+		return Student{}, Enrollment{}, fmt.Errorf("Department ID %s is not valid", departmentID)
+	}
+	if department.Archived {
+		return Student{}, Enrollment{}, fmt.Errorf("Department %s is archived", departmentID)
 	}
 
-	// Check if the program type is valid
-	program, programExists := programMapping[programType]
-	if !programExists {
-		return fmt.Errorf("Invalid program type: %s", programType)
+	// Check if the program is archived
+	if program.Archived {
+		return Student{}, Enrollment{}, fmt.Errorf("Program %s is archived", programType)
 	}
 	maxSemesters := program.MaxSemesters
 
 	// Create a new student record with basic details
 	student := Student{
-		StudentID:    studentID,
-		StudentName:  name,
-		ProgramType:  programType,
-		DepartmentID: departmentID,
-		MaxSemesters: maxSemesters,
+		StudentID:     studentID,
+		StudentName:   name,
+		ProgramType:   programType,
+		DepartmentID:  departmentID,
+		MaxSemesters:  maxSemesters,
+		SchemaVersion: schemaVersion(studentObjectType),
 	}
 
-	// Store the student record in the ledger
-	studentJSON, _ := json.Marshal(student)
-	err = ctx.GetStub().PutState(fmt.Sprintf("STUDENT-%s", studentID), studentJSON)
-	if err != nil {
-		return err
-	}
 	// Enroll the student into the first semester with empty courses and results
 	initialSemester := "Semester1"
 	initialEnrollment := Enrollment{
@@ -74,29 +128,31 @@ func (s *StudentRecordContract) InitialEnrollment(ctx contractapi.TransactionCon
 		CoursesTaken:        make(map[string][]string),
 		Extracurricular:     []string{}, // Initialize extracurricular activities as an empty list
 		Certificates:        []Certificate{},
+		Participation:       make(map[string]Participation),
+		StatusHistory:       []StatusChange{},
+		Transfers:           []TransferRecord{},
+		SchemaVersion:       schemaVersion(enrollmentObjectType),
 	}
-
-	// Update the student mapping
-	studentMapping[studentID] = student
-
-	// Update the enrollment mapping
-	enrollmentMapping[studentID] = initialEnrollment
-
-	// Store the initial enrollment in the ledger
-	initialEnrollmentJSON, _ := json.Marshal(initialEnrollment)
-	err = ctx.GetStub().PutState(fmt.Sprintf("ENROLLMENT-%s", studentID), initialEnrollmentJSON)
+	err = recordStatusChange(ctx, &initialEnrollment, studentActive, 0, "Initial enrollment")
 	if err != nil {
-		return err
+		return Student{}, Enrollment{}, err
 	}
 
-	// Record the ledger update
-	entry := fmt.Sprintf("Enrolled student %s into %s", studentID, initialSemester)
-	err = s.recordLedgerUpdate(ctx, entry)
+	return student, initialEnrollment, nil
+}
+
+// storeEnrollment stores a new student record and their initial enrollment in the ledger
+func storeEnrollment(ctx contractapi.TransactionContextInterface, student Student, initialEnrollment Enrollment) error {
+	// Store the student record in the ledger
+	studentJSON, _ := json.Marshal(student)
+	err := putEntityState(ctx, studentObjectType, student.StudentID, studentJSON)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	// Store the initial enrollment in the ledger
+	initialEnrollmentJSON, _ := json.Marshal(initialEnrollment)
+	return putEntityState(ctx, enrollmentObjectType, student.StudentID, initialEnrollmentJSON)
 }
 
 // EnrollStudentIntoNextSemester enrolls a student into the next semester
@@ -113,6 +169,11 @@ func (s *StudentRecordContract) EnrollStudentIntoNextSemester(ctx contractapi.Tr
 		return err
 	}
 
+	// Check if the student is active
+	if err := ensureEnrollmentActive(existingEnrollment); err != nil {
+		return err
+	}
+
 	// Validate if results are present for all courses in the current semester
 	currentSemester := existingEnrollment.CurrentSemester
 	if currentSemester == "" {
@@ -120,7 +181,7 @@ func (s *StudentRecordContract) EnrollStudentIntoNextSemester(ctx contractapi.Tr
 	}
 
 	// Check if creditsThis semester is at least equal to min credit required per semester
-	minCreditsRequired, _ := s.GetProgramMinCreditsPerSemester(existingEnrollment.ProgramType)
+	minCreditsRequired, _ := s.GetProgramMinCreditsPerSemester(ctx, existingEnrollment.ProgramType)
 	if existingEnrollment.CreditsThisSemester < minCreditsRequired {
 		return fmt.Errorf("Credits for this semester are less than the minimum required, Can't enroll in next semester.")
 	}
@@ -138,9 +199,12 @@ func (s *StudentRecordContract) EnrollStudentIntoNextSemester(ctx contractapi.Tr
 		return fmt.Errorf("current semester courses list is empty for student %s", studentID)
 	}
 
-	program, _ := programMapping[existingEnrollment.ProgramType]
-	// Check if the student has reached the maximum allowed semesters
-	if existingEnrollment.CurrentSemester == fmt.Sprintf("Semester%d", program.MaxSemesters) {
+	program, _, err := readProgram(ctx, existingEnrollment.ProgramType)
+	if err != nil {
+		return err
+	}
+	// Check if the student has reached the maximum allowed semesters, counting semesters of suspension
+	if semestersUsed(existingEnrollment) >= program.MaxSemesters {
 		return fmt.Errorf("Student %s has reached the maximum allowed semesters", studentID)
 	}
 
@@ -149,27 +213,15 @@ func (s *StudentRecordContract) EnrollStudentIntoNextSemester(ctx contractapi.Tr
 	nextSemesterNumber := currentSemesterNumber + 1
 	nextSemester := fmt.Sprintf("Semester%d", nextSemesterNumber)
 
-	// Create an enrollment for the next semester with empty courses and results
-	nextEnrollment := Enrollment{
-		StudentID:           studentID,
-		Name:                existingEnrollment.Name,
-		ProgramType:         existingEnrollment.ProgramType,
-		DepartmentID:        existingEnrollment.DepartmentID,
-		CreditsCompleted:    existingEnrollment.CreditsCompleted,
-		CreditsThisSemester: 0,
-		CurrentSemester:     nextSemester,
-		SemesterResults:     existingEnrollment.SemesterResults,
-		CoursesTaken:        existingEnrollment.CoursesTaken,
-		Extracurricular:     existingEnrollment.Extracurricular, // Retain extracurricular activities from existing enrollment
-		Certificates:        existingEnrollment.Certificates,
-	}
-
-	// Update the enrollment mapping
-	enrollmentMapping[studentID] = nextEnrollment
+	// Move the enrollment to the next semester with empty courses and results,
+	// retaining everything else (results, extracurricular activities, certificates) from the existing enrollment
+	nextEnrollment := existingEnrollment
+	nextEnrollment.CreditsThisSemester = 0
+	nextEnrollment.CurrentSemester = nextSemester
 
 	// Store the updated enrollment in the ledger
 	nextEnrollmentJSON, _ := json.Marshal(nextEnrollment)
-	err = ctx.GetStub().PutState(fmt.Sprintf("ENROLLMENT-%s", studentID), nextEnrollmentJSON)
+	err = putEntityState(ctx, enrollmentObjectType, studentID, nextEnrollmentJSON)
 	if err != nil {
 		return err
 	}
@@ -184,6 +236,14 @@ func (s *StudentRecordContract) EnrollStudentIntoNextSemester(ctx contractapi.Tr
 	return nil
 }
 
+// ensureEnrollmentModifiable returns an error if the enrollment has been frozen by degree conferral
+func ensureEnrollmentModifiable(enrollment Enrollment) error {
+	if enrollment.Frozen {
+		return fmt.Errorf("Enrollment for student %s is frozen after degree conferral", enrollment.StudentID)
+	}
+	return nil
+}
+
 // Helper function to extract the semester number from the semester name
 func extractSemesterNumber(semester string) int {
 	var semesterNumber int
@@ -196,7 +256,7 @@ func extractSemesterNumber(semester string) int {
 
 // GetEnrollment retrieves a student's enrollment by their ID from the ledger
 func (s *StudentRecordContract) GetEnrollment(ctx contractapi.TransactionContextInterface, studentID string) (Enrollment, error) {
-	enrollmentJSON, err := ctx.GetStub().GetState(fmt.Sprintf("ENROLLMENT-%s", studentID))
+	enrollmentJSON, err := getEntityState(ctx, enrollmentObjectType, studentID)
 	if err != nil {
 		return Enrollment{}, fmt.Errorf("Failed to read enrollment for student with ID %s: %v", studentID, err)
 	}
@@ -204,12 +264,29 @@ func (s *StudentRecordContract) GetEnrollment(ctx contractapi.TransactionContext
 		return Enrollment{}, fmt.Errorf("Enrollment for student with ID %s does not exist", studentID)
 	}
 
+	return decodeEnrollment(enrollmentJSON)
+}
+
+// decodeEnrollment decodes a stored enrollment, filling in the fields recorded without a value
+func decodeEnrollment(enrollmentJSON []byte) (Enrollment, error) {
 	var enrollment Enrollment
-	err = json.Unmarshal(enrollmentJSON, &enrollment)
+	err := json.Unmarshal(enrollmentJSON, &enrollment)
 	if err != nil {
 		return Enrollment{}, err
 	}
 
+	// Enrollments recorded before participation was tracked have no participation map
+	if enrollment.Participation == nil {
+		enrollment.Participation = make(map[string]Participation)
+	}
+	normalizeEnrollmentStatus(&enrollment)
+	if enrollment.Transfers == nil {
+		enrollment.Transfers = []TransferRecord{}
+	}
+	if enrollment.CoursePlans == nil {
+		enrollment.CoursePlans = []CoursePlan{}
+	}
+
 	return enrollment, nil
 }
 
@@ -217,8 +294,16 @@ func (s *StudentRecordContract) GetEnrollment(ctx contractapi.TransactionContext
 func (s *StudentRecordContract) GetAllEnrollments(ctx contractapi.TransactionContextInterface) ([]Enrollment, error) {
 	enrollments := make([]Enrollment, 0)
 
-	for _, enrollment := range enrollmentMapping {
+	err := forEachEntity(ctx, enrollmentObjectType, func(value []byte) error {
+		enrollment, err := decodeEnrollment(value)
+		if err != nil {
+			return err
+		}
 		enrollments = append(enrollments, enrollment)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return enrollments, nil
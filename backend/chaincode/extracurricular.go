@@ -3,28 +3,57 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 )
 
 // ExtracurricularActivity represents information related to an extracurricular activity
 type ExtracurricularActivity struct {
-	ActivityID   string `json:"activityID"`
-	ActivityName string `json:"name"`
-	Description  string `json:"description"`
-	Location     string `json:"location"`
-	Date         string `json:"date"`
-	MaxCount     int    `json:"maxCount"` // Maximum count of participants for the activity
-	FacultyID    string `json:"facultyID"`
+	ActivityID           string `json:"activityID"`
+	ActivityName         string `json:"name"`
+	Description          string `json:"description"`
+	Location             string `json:"location"`
+	Date                 string `json:"date"`
+	MaxCount             int    `json:"maxCount"` // Maximum count of participants for the activity
+	FacultyID            string `json:"facultyID"`
+	StartDate            string `json:"startDate"`            // First day of the activity in DDMMYYYY format
+	EndDate              string `json:"endDate"`              // Last day of the activity in DDMMYYYY format
+	RegistrationDeadline string `json:"registrationDeadline"` // Last day to register in DDMMYYYY format
+	Status               string `json:"status"`               // open, closed, cancelled or completed
+	CancellationReason   string `json:"cancellationReason"`
+	ParticipantCount     int    `json:"participantCount"` // Number of registered students who have not withdrawn
+	ClubID               string `json:"clubID"`           // Club organizing the activity, empty if none
+	Category             string `json:"category"`         // sports, cultural, technical or NSS
+	Term                 string `json:"term"`             // Academic term in which the activity is held
+	Archived             bool   `json:"archived"`         // Archived activities cannot take new registrations
+	Version              int    `json:"version"`          // Number of edits made to the activity, participant counts excluded
+	SchemaVersion        int    `json:"schemaVersion"`
 }
 
-var activityMapping = make(map[string]ExtracurricularActivity)
+// Activity statuses
+const (
+	activityOpen      = "open"
+	activityClosed    = "closed"
+	activityCancelled = "cancelled"
+	activityCompleted = "completed"
+)
+
+// activityDateLayout is the format of activity dates, DDMMYYYY as entered in the app
+const activityDateLayout = "02012006"
+
+// ActivityCancellation is the payload of the ActivityCancelled event sent to the registered students
+type ActivityCancellation struct {
+	ActivityID string   `json:"activityID"`
+	Name       string   `json:"name"`
+	Reason     string   `json:"reason"`
+	StudentIDs []string `json:"studentIDs"`
+}
 
 // AddExtracurricularActivityForStudent adds an extracurricular activity for a given student ID
 func (s *StudentRecordContract) AddExtracurricularActivityForStudent(ctx contractapi.TransactionContextInterface, studentID string, activityID string) error {
 	// Check if the student exists
-	studentKey := fmt.Sprintf("STUDENT-%s", studentID)
-	studentJSON, err := ctx.GetStub().GetState(studentKey)
+	studentJSON, err := getEntityState(ctx, studentObjectType, studentID)
 	if err != nil {
 		return err
 	}
@@ -38,42 +67,65 @@ func (s *StudentRecordContract) AddExtracurricularActivityForStudent(ctx contrac
 		return err
 	}
 
+	// Check if the student is active
+	if err := ensureEnrollmentActive(existingEnrollment); err != nil {
+		return err
+	}
+
 	// Check if the activityID already exists in the student's extracurricular activities
-	for _, activity := range existingEnrollment.Extracurricular {
-		if activity == activityID {
-			return fmt.Errorf("Extracurricular activity with ID %s already exists for student %s", activityID, studentID)
-		}
+	// A student who withdrew can register again
+	participation, registered := participationFor(existingEnrollment, activityID)
+	if registered && participation.Status != participationWithdrawn {
+		return fmt.Errorf("Extracurricular activity with ID %s already exists for student %s", activityID, studentID)
 	}
 
-	// Check if the maximum count for the activity has been reached
-	activity, activityExists := activityMapping[activityID]
-	if !activityExists {
-		return fmt.Errorf("Extracurricular activity with ID %s does not exist", activityID)
+	activity, err := s.GetExtracurricularActivity(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	if activity.Archived {
+		return fmt.Errorf("Extracurricular activity %s is archived", activityID)
 	}
 
-	// Count the number of students already registered for this activity
-	registeredStudentsCount := 0
-	for _, enrollment := range enrollmentMapping {
-		for _, act := range enrollment.Extracurricular {
-			if act == activityID {
-				registeredStudentsCount++
-			}
-		}
+	// Check if the activity is open for registration
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	if err := ensureRegistrationOpen(activity, now); err != nil {
+		return err
 	}
 
-	if registeredStudentsCount >= activity.MaxCount {
+	// Check if the maximum count for the activity has been reached
+	if activity.ParticipantCount >= activity.MaxCount {
 		return fmt.Errorf("Maximum count reached for extracurricular activity %s", activityID)
 	}
 
 	// Add the activityID to the student's extracurricular activities
-	existingEnrollment.Extracurricular = append(existingEnrollment.Extracurricular, activityID)
+	if !registered {
+		existingEnrollment.Extracurricular = append(existingEnrollment.Extracurricular, activityID)
+	}
+	participation = Participation{
+		ActivityID:   activityID,
+		Status:       participationRegistered,
+		RegisteredAt: now.Format(time.RFC3339),
+	}
+	setParticipation(&existingEnrollment, participation)
 
-	// Update the enrollment mapping
-	enrollmentMapping[studentID] = existingEnrollment
+	// Add the student to the roster of the activity and update its participant count
+	change, err := putRosterEntry(ctx, existingEnrollment, participation)
+	if err != nil {
+		return err
+	}
+	activity.ParticipantCount += change
+	err = s.putExtracurricularActivity(ctx, activity)
+	if err != nil {
+		return err
+	}
 
 	// Update the enrollment in the ledger
 	enrollmentJSON, _ := json.Marshal(existingEnrollment)
-	err = ctx.GetStub().PutState(fmt.Sprintf("ENROLLMENT-%s", studentID), enrollmentJSON)
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
 	if err != nil {
 		return err
 	}
@@ -91,8 +143,7 @@ func (s *StudentRecordContract) AddExtracurricularActivityForStudent(ctx contrac
 // AddExtracurricularActivity adds a new extracurricular activity to the ledger
 func (s *StudentRecordContract) AddExtracurricularActivity(ctx contractapi.TransactionContextInterface, activityID string, activityName string, description string, location string, date string, maxCount int, facultyID string) error {
 	// Check if the activity already exists
-	activityKey := fmt.Sprintf("EXTRACURRICULAR-%s", activityID)
-	activityJSON, err := ctx.GetStub().GetState(activityKey)
+	activityJSON, err := getEntityState(ctx, activityObjectType, activityID)
 	if err != nil {
 		return err
 	}
@@ -101,20 +152,37 @@ func (s *StudentRecordContract) AddExtracurricularActivity(ctx contractapi.Trans
 	}
 
 	// Check if the facultyID is valid
-	_, facultyExists := facultyMapping[facultyID]
+	var faculty Faculty
+	facultyExists, err := readEntity(ctx, facultyObjectType, facultyID, &faculty)
+	if err != nil {
+		return err
+	}
 	if !facultyExists {
 		return fmt.Errorf("Faculty ID %s is not valid", facultyID)
 	}
+	if faculty.Archived {
+		return fmt.Errorf("Faculty %s is archived", facultyID)
+	}
+
+	// The activity runs on the given date and registration is open until then
+	if _, err := time.Parse(activityDateLayout, date); err != nil {
+		return fmt.Errorf("Invalid date %s: expected DDMMYYYY", date)
+	}
 
 	// Create a new extracurricular activity
 	newActivity := ExtracurricularActivity{
-		ActivityID:   activityID,
-		ActivityName: activityName,
-		Description:  description,
-		Location:     location,
-		Date:         date,
-		MaxCount:     maxCount,
-		FacultyID:    facultyID,
+		ActivityID:           activityID,
+		ActivityName:         activityName,
+		Description:          description,
+		Location:             location,
+		Date:                 date,
+		MaxCount:             maxCount,
+		FacultyID:            facultyID,
+		StartDate:            date,
+		EndDate:              date,
+		RegistrationDeadline: date,
+		Status:               activityOpen,
+		SchemaVersion:        schemaVersion(activityObjectType),
 	}
 
 	// Marshal and store the extracurricular activity in the ledger
@@ -122,13 +190,11 @@ func (s *StudentRecordContract) AddExtracurricularActivity(ctx contractapi.Trans
 	if err != nil {
 		return err
 	}
-	err = ctx.GetStub().PutState(activityKey, activityJSON)
+	err = putEntityState(ctx, activityObjectType, activityID, activityJSON)
 	if err != nil {
 		return err
 	}
 
-	activityMapping[newActivity.ActivityID] = newActivity
-
 	// Record the ledger update
 	entry := fmt.Sprintf("Added new extracurricular activity: %s", activityID)
 	err = s.recordLedgerUpdate(ctx, entry)
@@ -142,8 +208,7 @@ func (s *StudentRecordContract) AddExtracurricularActivity(ctx contractapi.Trans
 // RemoveExtracurricularActivity removes an extracurricular activity from the ledger
 func (s *StudentRecordContract) RemoveExtracurricularActivity(ctx contractapi.TransactionContextInterface, activityID string) error {
 	// Check if the activity exists
-	activityKey := fmt.Sprintf("EXTRACURRICULAR-%s", activityID)
-	activityJSON, err := ctx.GetStub().GetState(activityKey)
+	activityJSON, err := getEntityState(ctx, activityObjectType, activityID)
 	if err != nil {
 		return err
 	}
@@ -151,13 +216,23 @@ func (s *StudentRecordContract) RemoveExtracurricularActivity(ctx contractapi.Tr
 		return fmt.Errorf("Extracurricular activity with ID %s does not exist", activityID)
 	}
 
+	// Refuse to remove an activity that students registered for
+	err = s.ensureNoDependents(ctx, entityActivity, activityID)
+	if err != nil {
+		return err
+	}
+
 	// Delete the activity from the ledger
-	err = ctx.GetStub().DelState(activityKey)
+	err = delEntityState(ctx, activityObjectType, activityID)
 	if err != nil {
 		return err
 	}
 
-	delete(activityMapping, activityID)
+	// Delete the roster of the activity
+	err = deleteActivityRoster(ctx, activityID)
+	if err != nil {
+		return err
+	}
 
 	// Record the ledger update
 	entry := fmt.Sprintf("Removed extracurricular activity: %s", activityID)
@@ -171,7 +246,7 @@ func (s *StudentRecordContract) RemoveExtracurricularActivity(ctx contractapi.Tr
 
 // GetExtracurricularActivity retrieves an extracurricular activity by its ID from the ledger
 func (s *StudentRecordContract) GetExtracurricularActivity(ctx contractapi.TransactionContextInterface, activityID string) (ExtracurricularActivity, error) {
-	activityJSON, err := ctx.GetStub().GetState(fmt.Sprintf("EXTRACURRICULAR-%s", activityID))
+	activityJSON, err := getEntityState(ctx, activityObjectType, activityID)
 	if err != nil {
 		return ExtracurricularActivity{}, fmt.Errorf("Failed to read extracurricular activity with ID %s: %v", activityID, err)
 	}
@@ -192,9 +267,273 @@ func (s *StudentRecordContract) GetExtracurricularActivity(ctx contractapi.Trans
 func (s *StudentRecordContract) GetAllExtracurricularActivities(ctx contractapi.TransactionContextInterface) ([]ExtracurricularActivity, error) {
 	activities := make([]ExtracurricularActivity, 0)
 
-	for _, activity := range activityMapping {
+	err := forEachEntity(ctx, activityObjectType, func(value []byte) error {
+		var activity ExtracurricularActivity
+		if err := json.Unmarshal(value, &activity); err != nil {
+			return err
+		}
 		activities = append(activities, activity)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return activities, nil
 }
+
+// SetActivitySchedule sets the start and end dates and the registration deadline of an activity, all in DDMMYYYY format
+func (s *StudentRecordContract) SetActivitySchedule(ctx contractapi.TransactionContextInterface, activityID string, startDate string, endDate string, registrationDeadline string) error {
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) && !s.isFaculty(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin or faculty can schedule activities")
+	}
+
+	activity, err := s.GetExtracurricularActivity(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	if activity.Status == activityCancelled || activity.Status == activityCompleted {
+		return fmt.Errorf("Extracurricular activity %s is %s", activityID, activity.Status)
+	}
+
+	// Validate the dates
+	start, err := time.Parse(activityDateLayout, startDate)
+	if err != nil {
+		return fmt.Errorf("Invalid start date %s: expected DDMMYYYY", startDate)
+	}
+	end, err := time.Parse(activityDateLayout, endDate)
+	if err != nil {
+		return fmt.Errorf("Invalid end date %s: expected DDMMYYYY", endDate)
+	}
+	deadline, err := time.Parse(activityDateLayout, registrationDeadline)
+	if err != nil {
+		return fmt.Errorf("Invalid registration deadline %s: expected DDMMYYYY", registrationDeadline)
+	}
+	if end.Before(start) {
+		return fmt.Errorf("End date %s is before start date %s", endDate, startDate)
+	}
+	if deadline.After(start) {
+		return fmt.Errorf("Registration deadline %s is after start date %s", registrationDeadline, startDate)
+	}
+
+	activity.Date = startDate
+	activity.StartDate = startDate
+	activity.EndDate = endDate
+	activity.RegistrationDeadline = registrationDeadline
+	activity.Version++
+
+	err = s.putExtracurricularActivity(ctx, activity)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Scheduled extracurricular activity %s from %s to %s with registration until %s", activityID, startDate, endDate, registrationDeadline)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetActivityStatus opens or closes registration for an activity, or marks it completed
+// Use CancelExtracurricularActivity to cancel an activity
+func (s *StudentRecordContract) SetActivityStatus(ctx contractapi.TransactionContextInterface, activityID string, status string) error {
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) && !s.isFaculty(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin or faculty can change the status of activities")
+	}
+
+	if status != activityOpen && status != activityClosed && status != activityCompleted {
+		return fmt.Errorf("Invalid activity status %s: must be %s, %s or %s", status, activityOpen, activityClosed, activityCompleted)
+	}
+
+	activity, err := s.GetExtracurricularActivity(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	if activity.Status == activityCancelled || activity.Status == activityCompleted {
+		return fmt.Errorf("Extracurricular activity %s is %s", activityID, activity.Status)
+	}
+
+	activity.Status = status
+	err = s.putExtracurricularActivity(ctx, activity)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Extracurricular activity %s is %s", activityID, status)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CancelExtracurricularActivity cancels an activity and notifies its registered students with an ActivityCancelled event
+func (s *StudentRecordContract) CancelExtracurricularActivity(ctx contractapi.TransactionContextInterface, activityID string, reason string) error {
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) && !s.isFaculty(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin or faculty can cancel activities")
+	}
+
+	if reason == "" {
+		return fmt.Errorf("A reason is required to cancel an activity")
+	}
+
+	activity, err := s.GetExtracurricularActivity(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	if activity.Status == activityCancelled || activity.Status == activityCompleted {
+		return fmt.Errorf("Extracurricular activity %s is %s", activityID, activity.Status)
+	}
+
+	activity.Status = activityCancelled
+	activity.CancellationReason = reason
+	err = s.putExtracurricularActivity(ctx, activity)
+	if err != nil {
+		return err
+	}
+
+	// Notify the students who are still registered
+	cancellation := ActivityCancellation{
+		ActivityID: activityID,
+		Name:       activity.ActivityName,
+		Reason:     reason,
+		StudentIDs: []string{},
+	}
+	roster, err := getActivityRoster(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	cancellation.StudentIDs = rosterStudentIDs(roster, participationRegistered)
+
+	cancellationJSON, err := json.Marshal(cancellation)
+	if err != nil {
+		return err
+	}
+	err = ctx.GetStub().SetEvent("ActivityCancelled", cancellationJSON)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Cancelled extracurricular activity %s: %s", activityID, reason)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WithdrawFromExtracurricularActivity withdraws a student from an activity they registered for
+// Students cannot withdraw once their attendance has been marked or the activity has completed
+func (s *StudentRecordContract) WithdrawFromExtracurricularActivity(ctx contractapi.TransactionContextInterface, studentID string, activityID string) error {
+	// Check if the caller is authorized (admin or the student)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) && !s.isStudentSelf(ctx, caller, studentID) {
+		return fmt.Errorf("Unauthorized: only the student or admin can withdraw from an extracurricular activity")
+	}
+
+	existingEnrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	if err := ensureEnrollmentModifiable(existingEnrollment); err != nil {
+		return err
+	}
+
+	activity, err := s.GetExtracurricularActivity(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	if activity.Status == activityCompleted {
+		return fmt.Errorf("Extracurricular activity %s has completed", activityID)
+	}
+
+	participation, registered := participationFor(existingEnrollment, activityID)
+	if !registered {
+		return fmt.Errorf("Student %s has not registered for extracurricular activity %s", studentID, activityID)
+	}
+	if participation.Status != participationRegistered {
+		return fmt.Errorf("Student %s cannot withdraw from extracurricular activity %s after being marked %s", studentID, activityID, participation.Status)
+	}
+
+	withdrawnAt, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+	participation.Status = participationWithdrawn
+	participation.MarkedBy = studentID
+	participation.MarkedAt = withdrawnAt
+	setParticipation(&existingEnrollment, participation)
+
+	// Update the enrollment in the ledger
+	enrollmentJSON, _ := json.Marshal(existingEnrollment)
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
+	if err != nil {
+		return err
+	}
+
+	// Update the roster and free the place of the student
+	change, err := putRosterEntry(ctx, existingEnrollment, participation)
+	if err != nil {
+		return err
+	}
+	activity.ParticipantCount += change
+	err = s.putExtracurricularActivity(ctx, activity)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Student %s withdrew from extracurricular activity %s", studentID, activityID)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// putExtracurricularActivity stores an updated activity in the ledger
+func (s *StudentRecordContract) putExtracurricularActivity(ctx contractapi.TransactionContextInterface, activity ExtracurricularActivity) error {
+	activityJSON, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+	err = putEntityState(ctx, activityObjectType, activity.ActivityID, activityJSON)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ensureRegistrationOpen checks that an activity is open and its registration deadline has not passed
+// Activities recorded before the lifecycle was tracked have no status or deadline and stay open
+func ensureRegistrationOpen(activity ExtracurricularActivity, now time.Time) error {
+	if activity.Status != "" && activity.Status != activityOpen {
+		return fmt.Errorf("Extracurricular activity %s is %s for registration", activity.ActivityID, activity.Status)
+	}
+	if activity.RegistrationDeadline == "" {
+		return nil
+	}
+
+	deadline, err := time.ParseInLocation(activityDateLayout, activity.RegistrationDeadline, indianTimeZone)
+	if err != nil {
+		return fmt.Errorf("Invalid registration deadline %s for extracurricular activity %s", activity.RegistrationDeadline, activity.ActivityID)
+	}
+	// The deadline day is included
+	if !now.Before(deadline.AddDate(0, 0, 1)) {
+		return fmt.Errorf("Registration for extracurricular activity %s closed on %s", activity.ActivityID, activity.RegistrationDeadline)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func (c *testContract) activity(activityID string) ExtracurricularActivity {
+	c.t.Helper()
+	var activity ExtracurricularActivity
+	c.query(&activity, "GetExtracurricularActivity", activityID)
+	return activity
+}
+
+func TestAddExtracurricularActivity(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	c.mustFail("expected DDMMYYYY", "AddExtracurricularActivity", "A1", "Chess", "Inter-hostel chess", "Hall 1", "2030-01-01", "10", "F1")
+	c.mustFail("not valid", "AddExtracurricularActivity", "A1", "Chess", "Inter-hostel chess", "Hall 1", "01012030", "10", "F9")
+	c.mustInvoke("AddExtracurricularActivity", "A1", "Chess", "Inter-hostel chess", "Hall 1", "01012030", "10", "F1")
+	c.mustFail("already exists", "AddExtracurricularActivity", "A1", "Chess", "Inter-hostel chess", "Hall 1", "01012030", "10", "F1")
+
+	activity := c.activity("A1")
+	if activity.Status != activityOpen || activity.StartDate != "01012030" || activity.EndDate != "01012030" || activity.RegistrationDeadline != "01012030" {
+		t.Fatalf("new activity is %+v, want open on 01012030 with registration until then", activity)
+	}
+}
+
+func TestSetActivitySchedule(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddExtracurricularActivity", "A1", "Chess", "Inter-hostel chess", "Hall 1", "01012030", "10", "F1")
+
+	c.mustFail("Invalid start date", "SetActivitySchedule", "A1", "2030-01-01", "02012030", "01012030")
+	c.mustFail("is before start date", "SetActivitySchedule", "A1", "01012030", "31122029", "01012030")
+	c.mustFail("is after start date", "SetActivitySchedule", "A1", "01012030", "02012030", "05012030")
+	c.mustInvoke("SetActivitySchedule", "A1", "01012030", "03012030", "20122029")
+	if activity := c.activity("A1"); activity.EndDate != "03012030" || activity.RegistrationDeadline != "20122029" {
+		t.Fatalf("scheduled activity is %+v", activity)
+	}
+
+	// The deadline day is included
+	c.at("20122029")
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S1", "A1")
+	c.mustInvoke("InitialEnrollment", "S2", "Ravi", "BTECH", "CSE")
+	c.at("21122029")
+	c.mustFail("closed on 20122029", "AddExtracurricularActivityForStudent", "S2", "A1")
+}
+
+func TestWithdrawFromExtracurricularActivity(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddExtracurricularActivity", "A1", "Chess", "Inter-hostel chess", "Hall 1", "01012030", "10", "F1")
+
+	c.mustFail("has not registered", "WithdrawFromExtracurricularActivity", "S1", "A1")
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S1", "A1")
+	c.mustFail("already exists for student", "AddExtracurricularActivityForStudent", "S1", "A1")
+
+	// The student withdraws with their own identity
+	c.as(map[string]string{"studentID": "S1"})
+	c.mustInvoke("WithdrawFromExtracurricularActivity", "S1", "A1")
+	c.as(nil)
+	c.mustFail("after being marked withdrawn", "WithdrawFromExtracurricularActivity", "S1", "A1")
+	if activity := c.activity("A1"); activity.ParticipantCount != 0 {
+		t.Fatalf("participant count after the withdrawal is %d", activity.ParticipantCount)
+	}
+
+	// A student who withdrew can register again, without listing the activity twice
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S1", "A1")
+	if enrollment := c.enrollment("S1"); len(enrollment.Extracurricular) != 1 || enrollment.Participation["A1"].Status != participationRegistered {
+		t.Fatalf("enrollment after registering again is %+v", enrollment)
+	}
+
+	c.mustInvoke("SetActivityStatus", "A1", activityCompleted)
+	c.mustFail("has completed", "WithdrawFromExtracurricularActivity", "S1", "A1")
+}
+
+func TestActivityStatus(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddExtracurricularActivity", "A1", "Chess", "Inter-hostel chess", "Hall 1", "01012030", "10", "F1")
+
+	c.mustFail("Invalid activity status", "SetActivityStatus", "A1", activityCancelled)
+	c.mustInvoke("SetActivityStatus", "A1", activityClosed)
+	c.mustFail("is closed for registration", "AddExtracurricularActivityForStudent", "S1", "A1")
+	c.mustInvoke("SetActivityStatus", "A1", activityOpen)
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S1", "A1")
+}
+
+func TestCancelExtracurricularActivity(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("InitialEnrollment", "S2", "Ravi", "BTECH", "CSE")
+	c.mustInvoke("AddExtracurricularActivity", "A1", "Chess", "Inter-hostel chess", "Hall 1", "01012030", "10", "F1")
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S1", "A1")
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S2", "A1")
+	c.mustInvoke("WithdrawFromExtracurricularActivity", "S2", "A1")
+
+	c.mustFail("reason is required", "CancelExtracurricularActivity", "A1", "")
+	c.mustInvoke("CancelExtracurricularActivity", "A1", "Hall under repair")
+
+	// The students still registered are notified
+	select {
+	case event := <-c.stub.ChaincodeEventsChannel:
+		var cancellation ActivityCancellation
+		if err := json.Unmarshal(event.Payload, &cancellation); err != nil {
+			t.Fatal(err)
+		}
+		if event.EventName != "ActivityCancelled" || cancellation.Reason != "Hall under repair" || strings.Join(cancellation.StudentIDs, ",") != "S1" {
+			t.Fatalf("cancellation event %s is %+v, want S1 notified", event.EventName, cancellation)
+		}
+	default:
+		t.Fatal("no event is sent for the cancellation")
+	}
+
+	if activity := c.activity("A1"); activity.Status != activityCancelled || activity.CancellationReason != "Hall under repair" {
+		t.Fatalf("cancelled activity is %+v", activity)
+	}
+	c.mustFail("is cancelled", "SetActivityStatus", "A1", activityOpen)
+	c.mustFail("is cancelled", "CancelExtracurricularActivity", "A1", "again")
+	c.at("01012030")
+	c.as(map[string]string{"facultyID": "F1"})
+	c.mustFail("has been cancelled", "MarkActivityAttendance", "A1", `[{"studentID":"S1","status":"attended"}]`)
+	c.as(nil)
+}
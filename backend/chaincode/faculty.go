@@ -8,65 +8,83 @@ import (
 )
 
 type Faculty struct {
-	FacultyID    string `json:"facultyID"`
-	FacultyName  string `json:"facultyName"`
-	DepartmentID string `json:"department"`
+	FacultyID        string   `json:"facultyID"`
+	FacultyName      string   `json:"facultyName"`
+	DepartmentID     string   `json:"department"`
+	Archived         bool     `json:"archived"`         // Archived faculty cannot be assigned new courses or activities
+	Version          int      `json:"version"`          // Number of edits made to the faculty
+	Designations     []string `json:"designations"`     // professor, associate professor, assistant professor, advisor or HoD
+	JointDepartments []string `json:"jointDepartments"` // Departments other than DepartmentID the faculty is appointed to
+	SchemaVersion    int      `json:"schemaVersion"`
 }
 
-var facultyMapping = make(map[string]Faculty)
-
 // AddFaculty adds a new faculty to the ledger
 func (s *StudentRecordContract) AddFaculty(ctx contractapi.TransactionContextInterface, facultyID string, facultyName string, departmentID string) error {
-	// Check if the faculty already exists
-	facultyKey := fmt.Sprintf("FACULTY-%s", facultyID)
-	facultyJSON, err := ctx.GetStub().GetState(facultyKey)
+	err := s.createFaculty(ctx, facultyID, facultyName, departmentID)
 	if err != nil {
 		return err
 	}
-	if facultyJSON != nil {
-		return fmt.Errorf("Faculty with ID %s already exists", facultyID)
-	}
 
-	// Check if the departmentID is valid
-	_, departmentExists := departmentMapping[departmentID]
-	if !departmentExists {
-		return fmt.Errorf("Department ID %s is not valid", departmentID)
+	// Record the ledger update
+	entry := fmt.Sprintf("Added new faculty: %s", facultyID)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
 	}
 
-	// Create a new faculty
-	newFaculty := Faculty{
-		FacultyID:    facultyID,
-		FacultyName:  facultyName,
-		DepartmentID: departmentID,
-	}
+	return nil
+}
 
-	// Marshal and store the faculty in the ledger
-	facultyJSON, err = json.Marshal(newFaculty)
+// createFaculty stores a new faculty, without recording a ledger update
+func (s *StudentRecordContract) createFaculty(ctx contractapi.TransactionContextInterface, facultyID string, facultyName string, departmentID string) error {
+	newFaculty, err := s.buildFaculty(ctx, nil, facultyID, facultyName, departmentID)
 	if err != nil {
 		return err
 	}
-	err = ctx.GetStub().PutState(facultyKey, facultyJSON)
+
+	// Marshal and store the faculty in the ledger
+	return putCatalogState(ctx, facultyObjectType, facultyID, newFaculty)
+}
+
+// buildFaculty checks and returns a new faculty, without storing it
+// The department is looked up in the staged records before the ledger
+func (s *StudentRecordContract) buildFaculty(ctx contractapi.TransactionContextInterface, staged *stagedRecords, facultyID string, facultyName string, departmentID string) (Faculty, error) {
+	// Check if the faculty already exists
+	facultyJSON, err := getEntityState(ctx, facultyObjectType, facultyID)
 	if err != nil {
-		return err
+		return Faculty{}, err
+	}
+	if facultyJSON != nil {
+		return Faculty{}, fmt.Errorf("Faculty with ID %s already exists", facultyID)
 	}
 
-	facultyMapping[newFaculty.FacultyID] = newFaculty
-
-	// Record the ledger update
-	entry := fmt.Sprintf("Added new faculty: %s", facultyID)
-	err = s.recordLedgerUpdate(ctx, entry)
+	// Check if the departmentID is valid
+	department, departmentExists, err := staged.lookupDepartment(ctx, departmentID)
 	if err != nil {
-		return err
+		return Faculty{}, err
+	}
+	if !departmentExists {
+		return Faculty{}, fmt.Errorf("Department ID %s is not valid", departmentID)
+	}
+	if department.Archived {
+		return Faculty{}, fmt.Errorf("Department %s is archived", departmentID)
 	}
 
-	return nil
+	// Create a new faculty
+	return Faculty{
+		FacultyID:        facultyID,
+		FacultyName:      facultyName,
+		DepartmentID:     departmentID,
+		Designations:     []string{},
+		JointDepartments: []string{},
+		SchemaVersion:    schemaVersion(facultyObjectType),
+	}, nil
 }
 
 // RemoveFaculty removes a faculty from the ledger
 func (s *StudentRecordContract) RemoveFaculty(ctx contractapi.TransactionContextInterface, facultyID string) error {
 	// Check if the faculty exists
-	facultyKey := fmt.Sprintf("FACULTY-%s", facultyID)
-	facultyJSON, err := ctx.GetStub().GetState(facultyKey)
+	facultyJSON, err := getEntityState(ctx, facultyObjectType, facultyID)
 	if err != nil {
 		return err
 	}
@@ -74,13 +92,17 @@ func (s *StudentRecordContract) RemoveFaculty(ctx contractapi.TransactionContext
 		return fmt.Errorf("Faculty with ID %s does not exist", facultyID)
 	}
 
-	// Delete the faculty from the ledger
-	err = ctx.GetStub().DelState(facultyKey)
+	// Refuse to remove a faculty that other records refer to
+	err = s.ensureNoDependents(ctx, entityFaculty, facultyID)
 	if err != nil {
 		return err
 	}
 
-	delete(facultyMapping, facultyID)
+	// Delete the faculty from the ledger
+	err = delEntityState(ctx, facultyObjectType, facultyID)
+	if err != nil {
+		return err
+	}
 
 	// Record the ledger update
 	entry := fmt.Sprintf("Removed faculty: %s", facultyID)
@@ -94,7 +116,11 @@ func (s *StudentRecordContract) RemoveFaculty(ctx contractapi.TransactionContext
 
 // GetFaculty retrieves faculty information by facultyID
 func (s *StudentRecordContract) GetFaculty(ctx contractapi.TransactionContextInterface, facultyID string) (*Faculty, error) {
-	faculty, exists := facultyMapping[facultyID]
+	var faculty Faculty
+	exists, err := readEntity(ctx, facultyObjectType, facultyID, &faculty)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read faculty with ID %s: %v", facultyID, err)
+	}
 	if !exists {
 		return nil, fmt.Errorf("Faculty with ID %s does not exist", facultyID)
 	}
@@ -105,8 +131,16 @@ func (s *StudentRecordContract) GetFaculty(ctx contractapi.TransactionContextInt
 func (s *StudentRecordContract) GetAllFaculties(ctx contractapi.TransactionContextInterface) ([]Faculty, error) {
 	faculties := make([]Faculty, 0)
 
-	for _, faculty := range facultyMapping {
+	err := forEachEntity(ctx, facultyObjectType, func(value []byte) error {
+		var faculty Faculty
+		if err := json.Unmarshal(value, &faculty); err != nil {
+			return err
+		}
 		faculties = append(faculties, faculty)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return faculties, nil
@@ -120,10 +154,10 @@ func (s *StudentRecordContract) GetCoursesByFacultyID(ctx contractapi.Transactio
 		return nil, fmt.Errorf("Failed to get all courses: %v", err)
 	}
 
-	// Filter courses by facultyID
+	// Filter courses by facultyID, as instructor or co-instructor
 	var coursesByFaculty []Course
 	for _, course := range allCourses {
-		if course.FacultyID == facultyID {
+		if teachesCourse(course, facultyID) {
 			coursesByFaculty = append(coursesByFaculty, course)
 		}
 	}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Graduation records the details of a degree conferred on a student
+type Graduation struct {
+	GraduationDate string  `json:"graduationDate"` // Date of graduation in YYYY-MM-DD format
+	CGPA           float64 `json:"cgpa"`           // CGPA at the time of conferral
+	Division       string  `json:"division"`       // Class/division awarded
+	ConferredBy    string  `json:"conferredBy"`    // Identity of the admin who conferred the degree
+}
+
+// DegreeAudit represents the result of checking a student against the graduation requirements of their program
+type DegreeAudit struct {
+	StudentID          string   `json:"studentID"`
+	ProgramType        string   `json:"programType"`
+	RequiredCredits    int      `json:"requiredCredits"`
+	CreditsCompleted   int      `json:"creditsCompleted"`
+	CGPA               float64  `json:"cgpa"`
+	MinCGPA            float64  `json:"minCGPA"`
+	MissingCoreCourses []string `json:"missingCoreCourses"` // Core courses not yet passed
+	UnsatisfiedGroups  []string `json:"unsatisfiedGroups"`  // Curriculum elective groups whose minimum credits are not earned
+	Backlogs           []string `json:"backlogs"`           // Courses with a failing grade, see buildDegreeAudit
+	PendingResults     []string `json:"pendingResults"`     // Courses taken whose result is not declared yet
+	Eligible           bool     `json:"eligible"`
+	Reasons            []string `json:"reasons"` // Reasons the student is not eligible, empty if eligible
+}
+
+// graduationDateLayout is the expected format of the graduation date
+const graduationDateLayout = "2006-01-02"
+
+// RunDegreeAudit checks whether a student satisfies all the graduation requirements of their program
+func (s *StudentRecordContract) RunDegreeAudit(ctx contractapi.TransactionContextInterface, studentID string) (*DegreeAudit, error) {
+	// Fetch the student's existing enrollment
+	enrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildDegreeAudit(ctx, enrollment)
+}
+
+// buildDegreeAudit evaluates the enrollment against the required credits, core courses, minimum CGPA and backlogs
+// A course is taken only once, so a failed course stays a backlog until its grade is updated to a pass after
+// re-examination with UpdateGradeForCourse or UpdatePrivateGradeForCourse
+func (s *StudentRecordContract) buildDegreeAudit(ctx contractapi.TransactionContextInterface, enrollment Enrollment) (*DegreeAudit, error) {
+	program, programExists, err := readProgram(ctx, enrollment.ProgramType)
+	if err != nil {
+		return nil, err
+	}
+	if !programExists {
+		return nil, fmt.Errorf("Program type %s not found", enrollment.ProgramType)
+	}
+
+	cgpa, err := s.CalculateCGPA(ctx, enrollment.StudentID)
+	if err != nil {
+		return nil, err
+	}
+	creditsCompleted, err := s.completedCredits(ctx, enrollment)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := DegreeAudit{
+		StudentID:          enrollment.StudentID,
+		ProgramType:        enrollment.ProgramType,
+		RequiredCredits:    program.RequiredCredits,
+		CreditsCompleted:   creditsCompleted,
+		CGPA:               cgpa,
+		MinCGPA:            program.MinCGPA,
+		MissingCoreCourses: []string{},
+		UnsatisfiedGroups:  []string{},
+		Backlogs:           []string{},
+		PendingResults:     []string{},
+		Reasons:            []string{},
+	}
+
+	// Read the results, including private grades
+	allSemesterResults, err := s.getSemesterResults(ctx, enrollment)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the courses passed and failed across all semesters
+	passedCourses := make(map[string]bool)
+	gradedCourses := make(map[string]bool)
+	for _, semesterResults := range allSemesterResults {
+		for _, result := range semesterResults {
+			gradedCourses[result.CourseID] = true
+			if isFailingGrade(result.Grade) {
+				audit.Backlogs = append(audit.Backlogs, result.CourseID)
+			} else {
+				passedCourses[result.CourseID] = true
+			}
+		}
+	}
+
+	// Collect the courses taken whose results are still awaited
+	for _, courseIDs := range enrollment.CoursesTaken {
+		for _, courseID := range courseIDs {
+			if !gradedCourses[courseID] {
+				audit.PendingResults = append(audit.PendingResults, courseID)
+			}
+		}
+	}
+
+	coreCourses := append([]string{}, program.CoreCourses...)
+
+	// Include the core courses and elective groups of the curriculum, if one is defined for the program and department
+	curriculum, err := s.findCurriculum(ctx, enrollment.ProgramType, enrollment.DepartmentID)
+	if err != nil {
+		return nil, err
+	}
+	if curriculum != nil {
+		progress, err := s.buildCurriculumProgress(ctx, enrollment, curriculum)
+		if err != nil {
+			return nil, err
+		}
+		for _, bucket := range progress.Buckets {
+			if bucket.BucketType == "elective" && !bucket.Satisfied {
+				audit.UnsatisfiedGroups = append(audit.UnsatisfiedGroups, bucket.BucketID)
+			}
+		}
+		for _, courseID := range curriculum.CoreCourses {
+			if !contains(coreCourses, courseID) {
+				coreCourses = append(coreCourses, courseID)
+			}
+		}
+	}
+
+	for _, courseID := range coreCourses {
+		if !passedCourses[courseID] {
+			audit.MissingCoreCourses = append(audit.MissingCoreCourses, courseID)
+		}
+	}
+
+	sort.Strings(audit.Backlogs)
+	sort.Strings(audit.PendingResults)
+
+	if audit.CreditsCompleted < audit.RequiredCredits {
+		audit.Reasons = append(audit.Reasons, fmt.Sprintf("Credits completed (%d) are less than the required credits (%d)", audit.CreditsCompleted, audit.RequiredCredits))
+	}
+	if len(audit.MissingCoreCourses) > 0 {
+		audit.Reasons = append(audit.Reasons, fmt.Sprintf("Core courses not passed: %s", strings.Join(audit.MissingCoreCourses, ", ")))
+	}
+	if len(audit.UnsatisfiedGroups) > 0 {
+		audit.Reasons = append(audit.Reasons, fmt.Sprintf("Minimum credits not earned in elective groups: %s", strings.Join(audit.UnsatisfiedGroups, ", ")))
+	}
+	if audit.CGPA < audit.MinCGPA {
+		audit.Reasons = append(audit.Reasons, fmt.Sprintf("CGPA (%.2f) is less than the minimum required CGPA (%.2f)", audit.CGPA, audit.MinCGPA))
+	}
+	if len(audit.Backlogs) > 0 {
+		audit.Reasons = append(audit.Reasons, fmt.Sprintf("Outstanding backlogs, which need a passing grade on re-examination: %s", strings.Join(audit.Backlogs, ", ")))
+	}
+	if len(audit.PendingResults) > 0 {
+		audit.Reasons = append(audit.Reasons, fmt.Sprintf("Results pending for: %s", strings.Join(audit.PendingResults, ", ")))
+	}
+
+	audit.Eligible = len(audit.Reasons) == 0
+
+	return &audit, nil
+}
+
+// ConferDegree confers the degree on a student who passes the degree audit and freezes their enrollment
+func (s *StudentRecordContract) ConferDegree(ctx contractapi.TransactionContextInterface, studentID string, graduationDate string) error {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can confer a degree")
+	}
+
+	// Validate the graduation date
+	if _, err := time.Parse(graduationDateLayout, graduationDate); err != nil {
+		return fmt.Errorf("Graduation date %s is not valid, expected format YYYY-MM-DD", graduationDate)
+	}
+
+	// Fetch the student's existing enrollment
+	existingEnrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return err
+	}
+
+	if existingEnrollment.Frozen {
+		return fmt.Errorf("Degree has already been conferred on student %s", studentID)
+	}
+	if existingEnrollment.Status != studentActive {
+		return fmt.Errorf("Student %s is %s and not active", studentID, existingEnrollment.Status)
+	}
+
+	// Check if the student satisfies all the graduation requirements
+	audit, err := s.buildDegreeAudit(ctx, existingEnrollment)
+	if err != nil {
+		return err
+	}
+	if !audit.Eligible {
+		return fmt.Errorf("Student %s is not eligible for graduation: %s", studentID, strings.Join(audit.Reasons, "; "))
+	}
+
+	clientID, err := caller.GetID()
+	if err != nil {
+		return err
+	}
+
+	// Record the graduation and freeze the enrollment
+	err = recordStatusChange(ctx, &existingEnrollment, studentGraduated, 0, "Degree conferred")
+	if err != nil {
+		return err
+	}
+	existingEnrollment.Frozen = true
+	existingEnrollment.Graduation = Graduation{
+		GraduationDate: graduationDate,
+		CGPA:           audit.CGPA,
+		Division:       classifyDivision(audit.CGPA),
+		ConferredBy:    clientID,
+	}
+
+	// Update the enrollment in the ledger
+	enrollmentJSON, _ := json.Marshal(existingEnrollment)
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
+	if err != nil {
+		return err
+	}
+
+	// Record the degree for public verification
+	err = s.putVerificationRecord(ctx, VerificationRecord{
+		CredentialID:   fmt.Sprintf("DEGREE-%s", studentID),
+		CredentialType: credentialTypeDegree,
+		StudentID:      studentID,
+		StudentName:    existingEnrollment.Name,
+		Details: map[string]string{
+			"programType":    existingEnrollment.ProgramType,
+			"department":     existingEnrollment.DepartmentID,
+			"graduationDate": graduationDate,
+			"cgpa":           fmt.Sprintf("%.2f", audit.CGPA),
+			"division":       existingEnrollment.Graduation.Division,
+		},
+		DocumentHashes: []string{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Conferred %s degree on student %s with %s", existingEnrollment.ProgramType, studentID, existingEnrollment.Graduation.Division)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// classifyDivision returns the class/division awarded for a CGPA on a 10 point scale
+func classifyDivision(cgpa float64) string {
+	switch {
+	case cgpa >= 8.5:
+		return "First Class with Distinction"
+	case cgpa >= 6.5:
+		return "First Class"
+	case cgpa >= 5.5:
+		return "Second Class"
+	default:
+		return "Pass Class"
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// passCourses takes the courses in the current semester of student S1 and adds their grades
+func (c *testContract) passCourses(coursesJSON string, resultsJSON string) {
+	c.t.Helper()
+	c.mustInvoke("AddCoursesToCurrentSemester", "S1", coursesJSON)
+	c.mustInvoke("AddResultForCurrentSemester", "S1", resultsJSON)
+}
+
+func (c *testContract) degreeAudit(studentID string) DegreeAudit {
+	c.t.Helper()
+	var audit DegreeAudit
+	c.query(&audit, "RunDegreeAudit", studentID)
+	return audit
+}
+
+func TestSetProgramGraduationRequirements(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	var program Program
+	c.query(&program, "GetProgram", "BTECH")
+	if len(program.CoreCourses) != 0 || program.MinCGPA != defaultMinCGPA {
+		t.Fatalf("BTECH starts with core courses %v and minimum CGPA %.2f", program.CoreCourses, program.MinCGPA)
+	}
+
+	c.mustFail("does not exist", "SetProgramGraduationRequirements", "PHD", `["CS101"]`, "6")
+	c.mustFail("not valid", "SetProgramGraduationRequirements", "BTECH", `["CS101"]`, "11")
+	c.mustFail("does not exist", "SetProgramGraduationRequirements", "BTECH", `["CS999"]`, "6")
+	c.mustFail("unmarhsal error", "SetProgramGraduationRequirements", "BTECH", `CS101`, "6")
+
+	c.mustInvoke("SetProgramGraduationRequirements", "BTECH", `["CS101","CS201"]`, "6")
+	c.restart()
+	c.query(&program, "GetProgram", "BTECH")
+	if strings.Join(program.CoreCourses, ",") != "CS101,CS201" || program.MinCGPA != 6 {
+		t.Fatalf("BTECH has core courses %v and minimum CGPA %.2f, want CS101,CS201 and 6", program.CoreCourses, program.MinCGPA)
+	}
+}
+
+func TestRunDegreeAudit(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("SetProgramGraduationRequirements", "BTECH", `["CS101","CS201"]`, "6")
+
+	audit := c.degreeAudit("S1")
+	if audit.Eligible || audit.RequiredCredits != 150 || audit.CreditsCompleted != 0 {
+		t.Fatalf("audit of a new student is %+v", audit)
+	}
+	if strings.Join(audit.MissingCoreCourses, ",") != "CS101,CS201" {
+		t.Fatalf("missing core courses are %v, want CS101,CS201", audit.MissingCoreCourses)
+	}
+
+	c.passCourses(`["CS101","CS102"]`, `[{"courseID":"CS101","grade":"A"},{"courseID":"CS102","grade":"F"}]`)
+	c.mustInvoke("EnrollStudentIntoNextSemester", "S1")
+	c.mustInvoke("AddCoursesToCurrentSemester", "S1", `["CS201","CS202"]`)
+
+	audit = c.degreeAudit("S1")
+	if audit.Eligible || audit.CreditsCompleted != 37 {
+		t.Fatalf("audit with a backlog and pending results is %+v", audit)
+	}
+	if strings.Join(audit.Backlogs, ",") != "CS102" {
+		t.Fatalf("backlogs are %v, want CS102", audit.Backlogs)
+	}
+	if strings.Join(audit.PendingResults, ",") != "CS201,CS202" {
+		t.Fatalf("pending results are %v, want CS201,CS202", audit.PendingResults)
+	}
+	if strings.Join(audit.MissingCoreCourses, ",") != "CS201" {
+		t.Fatalf("missing core courses are %v, want CS201", audit.MissingCoreCourses)
+	}
+
+	// A passing grade on re-examination clears the backlog
+	c.mustInvoke("UpdateGradeForCourse", "S1", "CS102", "B")
+	audit = c.degreeAudit("S1")
+	if len(audit.Backlogs) != 0 || audit.CreditsCompleted != 74 {
+		t.Fatalf("audit after the re-examination is %+v", audit)
+	}
+
+	c.mustFail("does not exist", "RunDegreeAudit", "S9")
+}
+
+func TestConferDegree(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("SetProgramGraduationRequirements", "BTECH", `["CS101","CS301"]`, "6")
+
+	c.passCourses(`["CS101","CS102"]`, `[{"courseID":"CS101","grade":"A"},{"courseID":"CS102","grade":"B"}]`)
+	c.mustInvoke("EnrollStudentIntoNextSemester", "S1")
+	c.passCourses(`["CS201","CS202"]`, `[{"courseID":"CS201","grade":"A"},{"courseID":"CS202","grade":"S"}]`)
+	c.mustFail("less than the required credits", "ConferDegree", "S1", "2026-05-01")
+	c.mustFail("Core courses not passed: CS301", "ConferDegree", "S1", "2026-05-01")
+
+	c.mustInvoke("EnrollStudentIntoNextSemester", "S1")
+	c.mustInvoke("AddCoursesToCurrentSemester", "S1", `["CS301"]`)
+	c.mustFail("Results pending for: CS301", "ConferDegree", "S1", "2026-05-01")
+	c.mustInvoke("AddResultForCurrentSemester", "S1", `[{"courseID":"CS301","grade":"B"}]`)
+
+	audit := c.degreeAudit("S1")
+	if !audit.Eligible || audit.CreditsCompleted != 188 || len(audit.Reasons) != 0 {
+		t.Fatalf("audit after all the courses is %+v", audit)
+	}
+
+	c.mustFail("not valid", "ConferDegree", "S1", "2026-13-01")
+	c.mustFail("does not exist", "ConferDegree", "S9", "2026-05-01")
+	c.mustInvoke("ConferDegree", "S1", "2026-05-01")
+
+	c.restart()
+	enrollment := c.enrollment("S1")
+	if !enrollment.Frozen || enrollment.Status != studentGraduated {
+		t.Fatalf("enrollment after conferral is frozen %t with status %s", enrollment.Frozen, enrollment.Status)
+	}
+	if enrollment.Graduation.GraduationDate != "2026-05-01" || enrollment.Graduation.Division != classifyDivision(audit.CGPA) {
+		t.Fatalf("graduation is %+v, want 2026-05-01 with the division of CGPA %.2f", enrollment.Graduation, audit.CGPA)
+	}
+
+	c.mustFail("already been conferred", "ConferDegree", "S1", "2026-06-01")
+	c.mustFail("frozen", "UpdateGradeForCourse", "S1", "CS101", "F")
+	c.mustFail("frozen", "EnrollStudentIntoNextSemester", "S1")
+}
+
+func TestClassifyDivision(t *testing.T) {
+	tests := []struct {
+		cgpa float64
+		want string
+	}{
+		{9.1, "First Class with Distinction"},
+		{8.5, "First Class with Distinction"},
+		{7.0, "First Class"},
+		{6.0, "Second Class"},
+		{5.0, "Pass Class"},
+	}
+	for _, test := range tests {
+		if got := classifyDivision(test.cgpa); got != test.want {
+			t.Errorf("classifyDivision(%.2f) = %q, want %q", test.cgpa, got, test.want)
+		}
+	}
+}
+
+// graduate passes student S1 in every course of addCatalog and confers the degree
+func (c *testContract) graduate() {
+	c.t.Helper()
+	c.passCourses(`["CS101","CS102"]`, `[{"courseID":"CS101","grade":"A"},{"courseID":"CS102","grade":"B"}]`)
+	c.mustInvoke("EnrollStudentIntoNextSemester", "S1")
+	c.passCourses(`["CS201","CS202"]`, `[{"courseID":"CS201","grade":"A"},{"courseID":"CS202","grade":"A"}]`)
+	c.mustInvoke("EnrollStudentIntoNextSemester", "S1")
+	c.passCourses(`["CS301"]`, `[{"courseID":"CS301","grade":"B"}]`)
+	c.mustInvoke("ConferDegree", "S1", "2026-05-01")
+}
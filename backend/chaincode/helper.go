@@ -1,5 +1,11 @@
 package main
 
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
 // Helper function to check if an item exists in a slice
 func contains(slice []string, item string) bool {
 	for _, element := range slice {
@@ -10,22 +16,32 @@ func contains(slice []string, item string) bool {
 	return false
 }
 
-// // UpdateCourse updates the information of a course in the ledger and the course mapping
-// func (s *StudentRecordContract) UpdateCourse(ctx contractapi.TransactionContextInterface, course Course) error {
-// 	// Marshal and store the course in the ledger
-// 	courseJSON, err := json.Marshal(course)
-// 	if err != nil {
-// 		return err
-// 	}
-
-// 	// Put the updated course data into the ledger
-// 	err = ctx.GetStub().PutState(course.CourseID, courseJSON)
-// 	if err != nil {
-// 		return err
-// 	}
-
-// 	// Update the course in the course mapping
-// 	courseMapping[course.CourseID] = course
-
-// 	return nil
-// }
+// containsInt checks if a number exists in a slice
+func containsInt(slice []int, item int) bool {
+	for _, element := range slice {
+		if element == item {
+			return true
+		}
+	}
+	return false
+}
+
+// txTimestamp returns the transaction timestamp in the Indian time zone, which is the same on every endorsing peer
+func txTimestamp(ctx contractapi.TransactionContextInterface) (string, error) {
+	timestamp, err := txTime(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return timestamp.Format(time.RFC3339), nil
+}
+
+// txTime returns the transaction time in the Indian time zone
+func txTime(ctx contractapi.TransactionContextInterface) (time.Time, error) {
+	timestamp, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return timestamp.AsTime().In(indianTimeZone), nil
+}
@@ -1,13 +1,290 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 )
 
+// Key of the seed in the transient data of InitLedger
+const seedTransientKey = "seed"
+
+// LedgerSeed is a set of records to add in one transaction, in the JSON form the records are read in
+// Departments are added first, then programs, faculty, courses and students, so that each may refer to the ones before it
+type LedgerSeed struct {
+	Departments []Department `json:"departments"`
+	Programs    []Program    `json:"programs"`
+	Faculty     []Faculty    `json:"faculty"`
+	Courses     []Course     `json:"courses"`
+	Students    []Student    `json:"students"` // Enrolled into their first semester
+}
+
 // InitLedger initializes the ledger with some initial data
+// The seed is optional and passed as transient data, so that initializing a network without one still takes no arguments.
+// The built-in programs are stored whoever initializes the network, unless the ledger or the seed already has them; only
+// an admin can pass a seed
 func (s *StudentRecordContract) InitLedger(ctx contractapi.TransactionContextInterface) error {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return fmt.Errorf("Failed to read transient data: %v", err)
+	}
+
+	var seed LedgerSeed
+	seedJSON := transientMap[seedTransientKey]
+	if len(seedJSON) > 0 {
+		if err := json.Unmarshal(seedJSON, &seed); err != nil {
+			return fmt.Errorf("Failed to parse the seed: %v", err)
+		}
+	}
+
+	builtins, err := missingBuiltinPrograms(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Without a seed the missing built-in programs are stored directly
+	if len(seedJSON) == 0 {
+		if len(builtins) == 0 {
+			return nil
+		}
+		for _, program := range builtins {
+			newProgram, err := s.buildProgram(ctx, program.Name, program.MaxSemesters, program.RequiredCredits, program.MaxCreditPerSemester, program.MinCreditPerSemester)
+			if err != nil {
+				return err
+			}
+			err = putCatalogState(ctx, programObjectType, program.Name, newProgram)
+			if err != nil {
+				return err
+			}
+		}
+		return s.recordLedgerUpdate(ctx, fmt.Sprintf("Seeded the ledger with the built-in programs %s", programNames(builtins)))
+	}
+
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can seed the ledger")
+	}
+
+	// The missing built-in programs are stored with the seed
+	for _, program := range builtins {
+		if !seedListsProgram(seed, program.Name) {
+			seed.Programs = append(seed.Programs, program)
+		}
+	}
+
+	return s.importSeed(ctx, seed, "Seeded the ledger with")
+}
+
+// programNames returns the names of the programs, separated by commas
+func programNames(programs []Program) string {
+	names := make([]string, 0, len(programs))
+	for _, program := range programs {
+		names = append(names, program.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// seedListsProgram reports whether the seed lists the program
+func seedListsProgram(seed LedgerSeed, programName string) bool {
+	for _, program := range seed.Programs {
+		if program.Name == programName {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportRecords adds a batch of departments, programs, faculty, courses and students in one transaction
+// Either every record of the batch is added or, if any of them is not valid, none is
+func (s *StudentRecordContract) ImportRecords(ctx contractapi.TransactionContextInterface, recordsJSON string) error {
+	var seed LedgerSeed
+	if err := json.Unmarshal([]byte(recordsJSON), &seed); err != nil {
+		return fmt.Errorf("Failed to parse the seed: %v", err)
+	}
+
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can import records")
+	}
+
+	return s.importSeed(ctx, seed, "Imported")
+}
+
+// importSeed adds the records of the seed and records a single ledger update for all of them
+// Every record is checked before any is written, so that a seed with a record that is not valid writes nothing.
+// The callers check that the caller is an admin
+func (s *StudentRecordContract) importSeed(ctx contractapi.TransactionContextInterface, seed LedgerSeed, action string) error {
+	// Records written in this transaction cannot be read back before it commits, so duplicates are checked in the seed
+	err := checkSeedDuplicates(seed)
+	if err != nil {
+		return err
+	}
+
+	// Check and build every record, records of the seed that refer to others of the seed find them staged
+	staged := &stagedRecords{
+		departments: make(map[string]Department),
+		faculty:     make(map[string]Faculty),
+		programs:    make(map[string]Program),
+	}
+	for _, department := range seed.Departments {
+		staged.departments[department.DepartmentID], err = s.buildDepartment(ctx, department.DepartmentID, department.DepartmentName)
+		if err != nil {
+			return err
+		}
+	}
+	for _, program := range seed.Programs {
+		staged.programs[program.Name], err = s.buildProgram(ctx, program.Name, program.MaxSemesters, program.RequiredCredits, program.MaxCreditPerSemester, program.MinCreditPerSemester)
+		if err != nil {
+			return err
+		}
+	}
+	for _, faculty := range seed.Faculty {
+		staged.faculty[faculty.FacultyID], err = s.buildFaculty(ctx, staged, faculty.FacultyID, faculty.FacultyName, faculty.DepartmentID)
+		if err != nil {
+			return err
+		}
+	}
+	courses := make([]Course, 0, len(seed.Courses))
+	for _, course := range seed.Courses {
+		newCourse, err := s.buildCourse(ctx, staged, course.CourseID, course.CourseName, course.Credits, course.DepartmentID, course.FacultyID, course.Description, course.AcademicYear, course.Semester, course.MaxSeats)
+		if err != nil {
+			return err
+		}
+		courses = append(courses, newCourse)
+	}
+	students := make([]Student, 0, len(seed.Students))
+	enrollments := make([]Enrollment, 0, len(seed.Students))
+	for _, student := range seed.Students {
+		program, programExists, err := staged.lookupProgram(ctx, student.ProgramType)
+		if err != nil {
+			return err
+		}
+		if !programExists {
+			return fmt.Errorf("Invalid program type: %s", student.ProgramType)
+		}
+		newStudent, enrollment, err := s.buildEnrollment(ctx, staged, student.StudentID, student.StudentName, program, student.DepartmentID)
+		if err != nil {
+			return err
+		}
+		students = append(students, newStudent)
+		enrollments = append(enrollments, enrollment)
+	}
+
+	// Every record is valid, store them in the ledger
+	for _, department := range seed.Departments {
+		err = putCatalogState(ctx, departmentObjectType, department.DepartmentID, staged.departments[department.DepartmentID])
+		if err != nil {
+			return err
+		}
+	}
+	for _, program := range seed.Programs {
+		err = putCatalogState(ctx, programObjectType, program.Name, staged.programs[program.Name])
+		if err != nil {
+			return err
+		}
+	}
+	for _, faculty := range seed.Faculty {
+		err = putCatalogState(ctx, facultyObjectType, faculty.FacultyID, staged.faculty[faculty.FacultyID])
+		if err != nil {
+			return err
+		}
+	}
+	for _, course := range courses {
+		err = putCatalogState(ctx, courseObjectType, course.CourseID, course)
+		if err != nil {
+			return err
+		}
+	}
+	for index := range students {
+		err = storeEnrollment(ctx, students[index], enrollments[index])
+		if err != nil {
+			return err
+		}
+	}
+
+	// Record the ledger update, once for the whole seed
+	entry := fmt.Sprintf("%s %d departments, %d programs, %d faculty, %d courses and %d students", action, len(seed.Departments), len(seed.Programs), len(seed.Faculty), len(seed.Courses), len(seed.Students))
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// stagedRecords holds the departments, faculty and programs of a seed that were checked but not written yet
+// A nil stagedRecords has no records, lookups then read the ledger only
+type stagedRecords struct {
+	departments map[string]Department
+	faculty     map[string]Faculty
+	programs    map[string]Program
+}
+
+// lookupDepartment looks up a department in the staged records, then in the ledger
+func (staged *stagedRecords) lookupDepartment(ctx contractapi.TransactionContextInterface, departmentID string) (Department, bool, error) {
+	if staged != nil {
+		if department, exists := staged.departments[departmentID]; exists {
+			return department, true, nil
+		}
+	}
+	var department Department
+	exists, err := readEntity(ctx, departmentObjectType, departmentID, &department)
+	return department, exists, err
+}
+
+// lookupFaculty looks up a faculty in the staged records, then in the ledger
+func (staged *stagedRecords) lookupFaculty(ctx contractapi.TransactionContextInterface, facultyID string) (Faculty, bool, error) {
+	if staged != nil {
+		if faculty, exists := staged.faculty[facultyID]; exists {
+			return faculty, true, nil
+		}
+	}
+	var faculty Faculty
+	exists, err := readEntity(ctx, facultyObjectType, facultyID, &faculty)
+	return faculty, exists, err
+}
+
+// lookupProgram looks up a program in the staged records, then in the ledger
+func (staged *stagedRecords) lookupProgram(ctx contractapi.TransactionContextInterface, programName string) (Program, bool, error) {
+	if staged != nil {
+		if program, exists := staged.programs[programName]; exists {
+			return program, true, nil
+		}
+	}
+	return readProgram(ctx, programName)
+}
 
-	// You can add more initial data such as programs, students, etc. as needed
+// checkSeedDuplicates returns an error if the seed lists a department, program, faculty, course or student more than once
+func checkSeedDuplicates(seed LedgerSeed) error {
+	ids := map[string][]string{}
+	for _, department := range seed.Departments {
+		ids["department"] = append(ids["department"], department.DepartmentID)
+	}
+	for _, program := range seed.Programs {
+		ids["program"] = append(ids["program"], program.Name)
+	}
+	for _, faculty := range seed.Faculty {
+		ids["faculty"] = append(ids["faculty"], faculty.FacultyID)
+	}
+	for _, course := range seed.Courses {
+		ids["course"] = append(ids["course"], course.CourseID)
+	}
+	for _, student := range seed.Students {
+		ids["student"] = append(ids["student"], student.StudentID)
+	}
 
+	for _, kind := range []string{"department", "program", "faculty", "course", "student"} {
+		seen := make(map[string]bool)
+		for _, id := range ids[kind] {
+			if seen[id] {
+				return fmt.Errorf("The seed lists %s %s more than once", kind, id)
+			}
+			seen[id] = true
+		}
+	}
 	return nil
 }
@@ -0,0 +1,90 @@
+package main
+
+import "testing"
+
+const testSeed = `{"departments":[{"departmentID":"EE","departmentName":"Electrical"}],` +
+	`"programs":[{"name":"MTECH","maxSemesters":4,"requiredCredits":60,"maxCreditPerCredits":40,"minCreditPerCredits":10}],` +
+	`"faculty":[{"facultyID":"F2","facultyName":"Ravi Kumar","department":"EE"}],` +
+	`"courses":[{"courseID":"EE101","name":"Circuits","credits":10,"department":"EE","facultyID":"F2","academicYear":2024,"semester":1,"maxSeats":20}],` +
+	`"students":[{"studentID":"S2","studentName":"Meera","programType":"MTECH","department":"EE"}]}`
+
+func TestInitLedger(t *testing.T) {
+	c := newTestContract(t)
+
+	// Without a seed only the built-in programs are stored, once
+	var programs []Program
+	c.query(&programs, "GetAllPrograms")
+	if len(programs) != 1 || programs[0].Name != "BTECH" {
+		t.Fatalf("programs after InitLedger are %+v", programs)
+	}
+	c.mustInvoke("InitLedger")
+	var updates []LedgerUpdate
+	c.query(&updates, "GetAllLedgerUpdates")
+	if len(updates) != 1 || updates[0].Entry != "Seeded the ledger with the built-in programs BTECH" {
+		t.Fatalf("ledger updates are %+v", updates)
+	}
+
+	// Records of the seed refer to the ones before them
+	c.transient(map[string]string{"seed": testSeed})
+	c.mustInvoke("InitLedger")
+	c.transient(nil)
+	if entry := c.lastLedgerUpdate(); entry != "Seeded the ledger with 1 departments, 1 programs, 1 faculty, 1 courses and 1 students" {
+		t.Fatalf("ledger update is %q", entry)
+	}
+	var course Course
+	c.query(&course, "GetCourse", "EE101")
+	if course.DepartmentID != "EE" || course.FacultyID != "F2" || course.MaxSeats != 20 {
+		t.Fatalf("seeded course is %+v", course)
+	}
+	if enrollment := c.enrollment("S2"); enrollment.ProgramType != "MTECH" || enrollment.CurrentSemester != "Semester1" || enrollment.Status != studentActive {
+		t.Fatalf("seeded enrollment is %+v", enrollment)
+	}
+	c.mustInvoke("InitialEnrollment", "S3", "Kiran", "MTECH", "EE")
+}
+
+func TestSeedIsCheckedBeforeItIsWritten(t *testing.T) {
+	c := newTestContract(t)
+
+	tests := []struct {
+		seed string
+		want string
+	}{
+		{`{"departments":[{"departmentID":"ME","departmentName":"Mechanical"},{"departmentID":"ME","departmentName":"Mechanical"}]}`, "The seed lists department ME more than once"},
+		{`{"departments":[{"departmentID":"ME","departmentName":"Mechanical"}],"faculty":[{"facultyID":"F2","facultyName":"Ravi Kumar","department":"CE"}]}`, "Department ID CE is not valid"},
+		{`{"departments":[{"departmentID":"ME","departmentName":"Mechanical"}],"students":[{"studentID":"S2","studentName":"Meera","programType":"PHD","department":"ME"}]}`, "Invalid program type: PHD"},
+		{`{"departments":`, "Failed to parse the seed: unexpected end of JSON input"},
+	}
+	for _, test := range tests {
+		c.transient(map[string]string{"seed": test.seed})
+		c.mustFail(test.want, "InitLedger")
+	}
+	c.transient(nil)
+
+	// Nothing of the seeds is written, not even the records before the one that is not valid
+	c.mustFail("does not exist", "GetDepartment", "ME")
+	var departments []Department
+	c.query(&departments, "GetAllDepartments")
+	if len(departments) != 0 {
+		t.Fatalf("departments are %+v", departments)
+	}
+}
+
+func TestImportRecords(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	c.mustInvoke("ImportRecords", `{"students":[{"studentID":"S7","studentName":"Kiran","programType":"BTECH","department":"CSE"},`+
+		`{"studentID":"S8","studentName":"Ravi","programType":"BTECH","department":"CSE"}]}`)
+	if entry := c.lastLedgerUpdate(); entry != "Imported 0 departments, 0 programs, 0 faculty, 0 courses and 2 students" {
+		t.Fatalf("ledger update is %q", entry)
+	}
+	if enrollment := c.enrollment("S8"); enrollment.DepartmentID != "CSE" || enrollment.CurrentSemester != "Semester1" {
+		t.Fatalf("imported enrollment is %+v", enrollment)
+	}
+
+	// A batch with a student already on the ledger imports none of its students
+	c.mustFail("Student with ID S7 already exists", "ImportRecords", `{"students":[{"studentID":"S9","studentName":"Asha","programType":"BTECH","department":"CSE"},`+
+		`{"studentID":"S7","studentName":"Kiran","programType":"BTECH","department":"CSE"}]}`)
+	c.mustFail("does not exist", "GetStudent", "S9")
+	c.mustFail("Failed to parse the seed: json: cannot unmarshal array", "ImportRecords", `[]`)
+}
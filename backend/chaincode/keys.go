@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Object types of the composite keys records are stored under
+// Each record is stored under a key of its object type and its ID, created with CreateCompositeKey
+const (
+	studentObjectType           = "STUDENT"
+	enrollmentObjectType        = "ENROLLMENT"
+	departmentObjectType        = "DEPARTMENT"
+	facultyObjectType           = "FACULTY"
+	courseObjectType            = "COURSE"
+	programObjectType           = "PROGRAM"
+	activityObjectType          = "EXTRACURRICULAR"
+	clubObjectType              = "CLUB"
+	curriculumObjectType        = "CURRICULUM" // Keyed by program type and department ID
+	ratingWeightsObjectType     = "RATINGWEIGHTS"
+	verificationObjectType      = "VERIFICATION"
+	verificationHashObjectType  = "VERIFICATIONHASH"
+	credentialStatusObjectType  = "VCSTATUS" // Keyed by source credential ID and verifiable credential ID
+	statusListObjectType        = "STATUSLIST"
+	ledgerUpdateObjectType      = "LEDGERUPDATE"
+	ledgerUpdateCountObjectType = "LEDGERUPDATECOUNT" // Single key without attributes
+	studentProfileObjectType    = "PROFILE"           // Stored in the student profile collection
+)
+
+// entityKey returns the composite key a record of the object type is stored under
+func entityKey(ctx contractapi.TransactionContextInterface, objectType string, attributes ...string) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(objectType, attributes)
+}
+
+// getEntityState reads the record of the object type with the ID from the ledger, nil if it does not exist
+// The record is upgraded to the current schema version
+func getEntityState(ctx contractapi.TransactionContextInterface, objectType string, id string) ([]byte, error) {
+	key, err := entityKey(ctx, objectType, id)
+	if err != nil {
+		return nil, err
+	}
+	value, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return nil, err
+	}
+	return upgradeDocument(objectType, value)
+}
+
+// readEntity reads the record of the object type with the ID from the ledger into value
+// Returns false if the record does not exist
+func readEntity(ctx contractapi.TransactionContextInterface, objectType string, id string, value interface{}) (bool, error) {
+	valueJSON, err := getEntityState(ctx, objectType, id)
+	if err != nil {
+		return false, err
+	}
+	if valueJSON == nil {
+		return false, nil
+	}
+	return true, json.Unmarshal(valueJSON, value)
+}
+
+// putEntityState stores the record of the object type with the ID in the ledger
+func putEntityState(ctx contractapi.TransactionContextInterface, objectType string, id string, value []byte) error {
+	key, err := entityKey(ctx, objectType, id)
+	if err != nil {
+		return err
+	}
+	return ctx.GetStub().PutState(key, value)
+}
+
+// delEntityState deletes the record of the object type with the ID from the ledger
+func delEntityState(ctx contractapi.TransactionContextInterface, objectType string, id string) error {
+	key, err := entityKey(ctx, objectType, id)
+	if err != nil {
+		return err
+	}
+	return ctx.GetStub().DelState(key)
+}
+
+// forEachEntity calls fn with the value of every record of the object type in the ledger, in key order
+// Each record is upgraded to the current schema version
+func forEachEntity(ctx contractapi.TransactionContextInterface, objectType string, fn func(value []byte) error) error {
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(objectType, []string{})
+	if err != nil {
+		return err
+	}
+	defer iterator.Close()
+
+	for iterator.HasNext() {
+		result, err := iterator.Next()
+		if err != nil {
+			return err
+		}
+		value, err := upgradeDocument(objectType, result.Value)
+		if err != nil {
+			return err
+		}
+		if err := fn(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
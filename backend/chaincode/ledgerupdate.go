@@ -11,9 +11,10 @@ import (
 
 // LedgerUpdate represents a ledger update entry
 type LedgerUpdate struct {
-	Timestamp string `json:"timestamp"`
-	Entry     string `json:"entry"`
-	UpdatedBy string `json:"updatedBy"`
+	Timestamp     string `json:"timestamp"`
+	Entry         string `json:"entry"`
+	UpdatedBy     string `json:"updatedBy"`
+	SchemaVersion int    `json:"schemaVersion"`
 }
 
 // Define the Indian time zone
@@ -52,33 +53,32 @@ func (s *StudentRecordContract) recordLedgerUpdate(ctx contractapi.TransactionCo
 	}
 
 	ledgerUpdate := LedgerUpdate{
-		Timestamp: timestamp,
-		Entry:     entry,
-		UpdatedBy: clientID, // Use the client ID obtained above
+		Timestamp:     timestamp,
+		Entry:         entry,
+		UpdatedBy:     clientID, // Use the client ID obtained above
+		SchemaVersion: schemaVersion(ledgerUpdateObjectType),
 	}
 
 	// Get the current ledger update count
-	count, err := ctx.GetStub().GetState("LEDGERUPDATE_COUNT")
+	updateCount, err := ledgerUpdateCount(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Convert the count to an integer
-	updateCount := 0
-	if count != nil {
-		updateCount, _ = strconv.Atoi(string(count))
-	}
-
 	// Save the new ledger update with an incremental index
 	updateJSON, _ := json.Marshal(ledgerUpdate)
-	err = ctx.GetStub().PutState(fmt.Sprintf("LEDGERUPDATE-%d", updateCount), updateJSON)
+	err = putEntityState(ctx, ledgerUpdateObjectType, strconv.Itoa(updateCount), updateJSON)
 	if err != nil {
 		return err
 	}
 
 	// Increment the ledger update count
 	updateCount++
-	err = ctx.GetStub().PutState("LEDGERUPDATE_COUNT", []byte(strconv.Itoa(updateCount)))
+	countKey, err := entityKey(ctx, ledgerUpdateCountObjectType)
+	if err != nil {
+		return err
+	}
+	err = ctx.GetStub().PutState(countKey, []byte(strconv.Itoa(updateCount)))
 	if err != nil {
 		return err
 	}
@@ -88,20 +88,15 @@ func (s *StudentRecordContract) recordLedgerUpdate(ctx contractapi.TransactionCo
 
 // GetAllLedgerUpdates retrieves and returns all ledger update histories
 func (s *StudentRecordContract) GetAllLedgerUpdates(ctx contractapi.TransactionContextInterface) ([]LedgerUpdate, error) {
-	count, err := ctx.GetStub().GetState("LEDGERUPDATE_COUNT")
+	updateCount, err := ledgerUpdateCount(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	updateCount := 0
-	if count != nil {
-		updateCount, _ = strconv.Atoi(string(count))
-	}
-
 	var ledgerUpdates []LedgerUpdate
 
 	for i := 0; i < updateCount; i++ {
-		updateJSON, err := ctx.GetStub().GetState(fmt.Sprintf("LEDGERUPDATE-%d", i))
+		updateJSON, err := ledgerUpdateJSON(ctx, i)
 		if err != nil {
 			return nil, err
 		}
@@ -117,3 +112,53 @@ func (s *StudentRecordContract) GetAllLedgerUpdates(ctx contractapi.TransactionC
 
 	return ledgerUpdates, nil
 }
+
+// ledgerUpdateJSON returns the ledger update with the index
+// Until MigrateLedgerKeys has moved them, the updates recorded before composite keys are stored under their legacy keys
+func ledgerUpdateJSON(ctx contractapi.TransactionContextInterface, index int) ([]byte, error) {
+	updateJSON, err := getEntityState(ctx, ledgerUpdateObjectType, strconv.Itoa(index))
+	if err != nil {
+		return nil, err
+	}
+	if updateJSON == nil {
+		updateJSON, err = ctx.GetStub().GetState(legacyLedgerUpdatePrefix + strconv.Itoa(index))
+		if err != nil {
+			return nil, err
+		}
+		updateJSON, err = upgradeDocument(ledgerUpdateObjectType, updateJSON)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if updateJSON == nil {
+		return nil, fmt.Errorf("Ledger update %d does not exist", index)
+	}
+	return updateJSON, nil
+}
+
+// ledgerUpdateCount returns the number of ledger updates recorded so far
+// Until MigrateLedgerKeys has moved it, the count is stored under its legacy key, so updates recorded in between
+// continue the numbering instead of overwriting the earliest updates
+func ledgerUpdateCount(ctx contractapi.TransactionContextInterface) (int, error) {
+	countKey, err := entityKey(ctx, ledgerUpdateCountObjectType)
+	if err != nil {
+		return 0, err
+	}
+	count, err := ctx.GetStub().GetState(countKey)
+	if err != nil {
+		return 0, err
+	}
+	if count == nil {
+		count, err = ctx.GetStub().GetState(legacyLedgerUpdateCount)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// Convert the count to an integer
+	updateCount := 0
+	if count != nil {
+		updateCount, _ = strconv.Atoi(string(count))
+	}
+	return updateCount, nil
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// StatusChange records a change in the lifecycle status of a student
+type StatusChange struct {
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previousStatus"` // Empty for the initial enrollment
+	Semesters      int    `json:"semesters"`      // Number of semesters of leave or suspension
+	Reason         string `json:"reason"`
+	ChangedBy      string `json:"changedBy"`
+	ChangedAt      string `json:"changedAt"`
+	TxID           string `json:"txID"` // ID of the transaction that changed the status
+}
+
+// Lifecycle statuses of a student
+const (
+	studentActive      = "active"
+	studentOnLeave     = "on_leave"
+	studentSuspended   = "suspended"
+	studentWithdrawn   = "withdrawn"
+	studentGraduated   = "graduated"
+	studentTransferred = "transferred"
+)
+
+// studentStatuses lists every lifecycle status
+var studentStatuses = []string{studentActive, studentOnLeave, studentSuspended, studentWithdrawn, studentGraduated, studentTransferred}
+
+// studentStatusTransitions lists the statuses a student can be moved to from each status with ChangeStudentStatus
+// Graduation is recorded by ConferDegree; withdrawn, graduated and transferred students have left the institute
+var studentStatusTransitions = map[string][]string{
+	studentActive:    {studentOnLeave, studentSuspended, studentWithdrawn, studentTransferred},
+	studentOnLeave:   {studentActive, studentWithdrawn},
+	studentSuspended: {studentActive, studentWithdrawn},
+}
+
+// StudentStatusReport summarises the lifecycle of a student and the semesters used against the program maximum
+type StudentStatusReport struct {
+	StudentID          string         `json:"studentID"`
+	Status             string         `json:"status"`
+	CurrentSemester    string         `json:"currentSemester"`
+	LeaveSemesters     int            `json:"leaveSemesters"`     // Semesters on leave, which do not count against the maximum
+	SuspendedSemesters int            `json:"suspendedSemesters"` // Semesters of suspension, which count against the maximum
+	SemestersUsed      int            `json:"semestersUsed"`
+	MaxSemesters       int            `json:"maxSemesters"`
+	History            []StatusChange `json:"history"`
+}
+
+// ChangeStudentStatus moves a student to another lifecycle status
+// semesters is the length of a leave or suspension and must be 0 for the other statuses
+func (s *StudentRecordContract) ChangeStudentStatus(ctx contractapi.TransactionContextInterface, studentID string, status string, semesters int, reason string) error {
+	existingEnrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return err
+	}
+
+	// Check if the caller is authorized (admin or head of the student's department)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) && !s.isDepartmentHead(ctx, caller, existingEnrollment.DepartmentID) {
+		return fmt.Errorf("Unauthorized: only admin or the head of department %s can change the status of its students", existingEnrollment.DepartmentID)
+	}
+	if err := ensureEnrollmentModifiable(existingEnrollment); err != nil {
+		return err
+	}
+
+	// Check that the transition is allowed
+	if status == studentGraduated {
+		return fmt.Errorf("Graduation is recorded by ConferDegree")
+	}
+	if !contains(studentStatusTransitions[existingEnrollment.Status], status) {
+		return fmt.Errorf("Student %s cannot be moved from %s to %s", studentID, existingEnrollment.Status, status)
+	}
+	if reason == "" {
+		return fmt.Errorf("A reason is required to change the status of a student")
+	}
+
+	// Leave and suspension last a number of semesters
+	switch status {
+	case studentOnLeave:
+		if semesters <= 0 {
+			return fmt.Errorf("Leave must be granted for at least one semester")
+		}
+		existingEnrollment.LeaveSemesters += semesters
+	case studentSuspended:
+		if semesters <= 0 {
+			return fmt.Errorf("Suspension must be for at least one semester")
+		}
+		existingEnrollment.SuspendedSemesters += semesters
+	default:
+		if semesters != 0 {
+			return fmt.Errorf("Semesters can only be given for leave or suspension")
+		}
+	}
+
+	err = recordStatusChange(ctx, &existingEnrollment, status, semesters, reason)
+	if err != nil {
+		return err
+	}
+
+	// Update the enrollment in the ledger
+	enrollmentJSON, err := json.Marshal(existingEnrollment)
+	if err != nil {
+		return err
+	}
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Changed status of student %s from %s to %s: %s", studentID, existingEnrollment.StatusHistory[len(existingEnrollment.StatusHistory)-1].PreviousStatus, status, reason)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetStudentStatus returns the lifecycle status and history of a student with the semesters used against the program maximum
+func (s *StudentRecordContract) GetStudentStatus(ctx contractapi.TransactionContextInterface, studentID string) (*StudentStatusReport, error) {
+	enrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	program, programExists, err := readProgram(ctx, enrollment.ProgramType)
+	if err != nil {
+		return nil, err
+	}
+	if !programExists {
+		return nil, fmt.Errorf("Program type %s not found", enrollment.ProgramType)
+	}
+
+	return &StudentStatusReport{
+		StudentID:          studentID,
+		Status:             enrollment.Status,
+		CurrentSemester:    enrollment.CurrentSemester,
+		LeaveSemesters:     enrollment.LeaveSemesters,
+		SuspendedSemesters: enrollment.SuspendedSemesters,
+		SemestersUsed:      semestersUsed(enrollment),
+		MaxSemesters:       program.MaxSemesters,
+		History:            enrollment.StatusHistory,
+	}, nil
+}
+
+// GetStudentsByStatus returns the IDs of the students with a lifecycle status, ordered by ID
+func (s *StudentRecordContract) GetStudentsByStatus(ctx contractapi.TransactionContextInterface, status string) ([]string, error) {
+	if !contains(studentStatuses, status) {
+		return nil, fmt.Errorf("Invalid student status %s", status)
+	}
+
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(enrollmentObjectType, []string{})
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	studentIDs := make([]string, 0)
+	for iterator.HasNext() {
+		result, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		value, err := upgradeDocument(enrollmentObjectType, result.Value)
+		if err != nil {
+			return nil, err
+		}
+		var enrollment Enrollment
+		if err := json.Unmarshal(value, &enrollment); err != nil {
+			return nil, err
+		}
+		normalizeEnrollmentStatus(&enrollment)
+		if enrollment.Status == status {
+			studentIDs = append(studentIDs, enrollment.StudentID)
+		}
+	}
+
+	return studentIDs, nil
+}
+
+// ensureEnrollmentActive returns an error unless the student is active, as only active students can register or be graded
+func ensureEnrollmentActive(enrollment Enrollment) error {
+	if err := ensureEnrollmentModifiable(enrollment); err != nil {
+		return err
+	}
+	if enrollment.Status != studentActive {
+		return fmt.Errorf("Student %s is %s and not active", enrollment.StudentID, enrollment.Status)
+	}
+	return nil
+}
+
+// recordStatusChange sets the status of an enrollment and appends the change to its history
+func recordStatusChange(ctx contractapi.TransactionContextInterface, enrollment *Enrollment, status string, semesters int, reason string) error {
+	changedBy, err := ctx.GetClientIdentity().GetID()
+	if err != nil {
+		return err
+	}
+	changedAt, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	enrollment.StatusHistory = append(enrollment.StatusHistory, StatusChange{
+		Status:         status,
+		PreviousStatus: enrollment.Status,
+		Semesters:      semesters,
+		Reason:         reason,
+		ChangedBy:      changedBy,
+		ChangedAt:      changedAt,
+		TxID:           ctx.GetStub().GetTxID(),
+	})
+	enrollment.Status = status
+	return nil
+}
+
+// normalizeEnrollmentStatus sets the status of enrollments recorded before statuses were kept
+func normalizeEnrollmentStatus(enrollment *Enrollment) {
+	if enrollment.Status == "" {
+		enrollment.Status = studentActive
+		if enrollment.Frozen {
+			enrollment.Status = studentGraduated
+		}
+	}
+	if enrollment.StatusHistory == nil {
+		enrollment.StatusHistory = []StatusChange{}
+	}
+}
+
+// semestersUsed returns the number of semesters a student has used against the program maximum
+// Semesters on leave do not count, semesters of suspension do
+func semestersUsed(enrollment Enrollment) int {
+	return extractSemesterNumber(enrollment.CurrentSemester) + enrollment.SuspendedSemesters
+}
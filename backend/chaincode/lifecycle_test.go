@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChangeStudentStatus(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	c.mustFail("recorded by ConferDegree", "ChangeStudentStatus", "S1", studentGraduated, "0", "Graduated")
+	c.mustFail("cannot be moved from active to active", "ChangeStudentStatus", "S1", studentActive, "0", "Returned")
+	c.mustFail("A reason is required", "ChangeStudentStatus", "S1", studentOnLeave, "2", "")
+	c.mustFail("at least one semester", "ChangeStudentStatus", "S1", studentOnLeave, "0", "Medical")
+	c.mustFail("only be given for leave or suspension", "ChangeStudentStatus", "S1", studentWithdrawn, "1", "Dropped out")
+	c.mustFail("does not exist", "ChangeStudentStatus", "S9", studentOnLeave, "2", "Medical")
+
+	c.mustInvoke("ChangeStudentStatus", "S1", studentOnLeave, "2", "Medical")
+	c.mustFail("cannot be moved from on_leave to suspended", "ChangeStudentStatus", "S1", studentSuspended, "1", "Misconduct")
+	c.mustInvoke("ChangeStudentStatus", "S1", studentActive, "0", "Returned")
+	c.mustInvoke("ChangeStudentStatus", "S1", studentSuspended, "3", "Misconduct")
+	c.mustInvoke("ChangeStudentStatus", "S1", studentActive, "0", "Reinstated")
+	c.mustInvoke("ChangeStudentStatus", "S1", studentWithdrawn, "0", "Dropped out")
+	c.mustFail("cannot be moved from withdrawn to active", "ChangeStudentStatus", "S1", studentActive, "0", "Returned")
+
+	// Every change is kept, after the initial enrollment
+	enrollment := c.enrollment("S1")
+	var history []string
+	for _, change := range enrollment.StatusHistory {
+		history = append(history, change.PreviousStatus+">"+change.Status)
+		if change.Reason == "" || change.ChangedBy == "" || change.ChangedAt == "" || change.TxID == "" {
+			t.Errorf("status change %+v is incomplete", change)
+		}
+	}
+	want := ">active,active>on_leave,on_leave>active,active>suspended,suspended>active,active>withdrawn"
+	if strings.Join(history, ",") != want {
+		t.Fatalf("status history is %v, want %s", history, want)
+	}
+}
+
+func TestOnlyActiveStudentsAreRecorded(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	c.mustInvoke("ChangeStudentStatus", "S1", studentOnLeave, "1", "Medical")
+	c.mustFail("is on_leave and not active", "AddCoursesToCurrentSemester", "S1", `["CS101"]`)
+	c.mustInvoke("ChangeStudentStatus", "S1", studentActive, "0", "Returned")
+	c.mustInvoke("AddCoursesToCurrentSemester", "S1", `["CS101"]`)
+
+	c.mustInvoke("ChangeStudentStatus", "S1", studentWithdrawn, "0", "Dropped out")
+	c.mustFail("is withdrawn and not active", "AddResultForCurrentSemester", "S1", `[{"courseID":"CS101","grade":"A"}]`)
+}
+
+func TestGetStudentStatus(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("ChangeStudentStatus", "S1", studentOnLeave, "2", "Medical")
+	c.mustInvoke("ChangeStudentStatus", "S1", studentActive, "0", "Returned")
+	c.mustInvoke("ChangeStudentStatus", "S1", studentSuspended, "3", "Misconduct")
+
+	// Leave does not count against the maximum, suspension does
+	var report StudentStatusReport
+	c.query(&report, "GetStudentStatus", "S1")
+	if report.Status != studentSuspended || report.LeaveSemesters != 2 || report.SuspendedSemesters != 3 || report.SemestersUsed != 4 || report.MaxSemesters != 8 || len(report.History) != 4 {
+		t.Fatalf("status report is %+v", report)
+	}
+	c.mustFail("does not exist", "GetStudentStatus", "S9")
+}
+
+func TestGetStudentsByStatus(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("InitialEnrollment", "S2", "Ravi", "BTECH", "CSE")
+	c.mustInvoke("InitialEnrollment", "S3", "Meera", "BTECH", "CSE")
+	c.mustInvoke("ChangeStudentStatus", "S2", studentSuspended, "1", "Misconduct")
+
+	tests := map[string]string{
+		studentActive:    "S1,S3",
+		studentSuspended: "S2",
+		studentWithdrawn: "",
+	}
+	for status, want := range tests {
+		var studentIDs []string
+		c.query(&studentIDs, "GetStudentsByStatus", status)
+		if got := strings.Join(studentIDs, ","); got != want {
+			t.Errorf("%s students are %q, want %q", status, got, want)
+		}
+	}
+	c.mustFail("Invalid student status expelled", "GetStudentsByStatus", "expelled")
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// legacyKeyPrefixes maps the prefixes of the keys records were stored under before composite keys to their object types
+// Curricula were stored under CURRICULUM-programType-departmentID and are read from their value instead
+var legacyKeyPrefixes = []struct {
+	prefix     string
+	objectType string
+}{
+	{"STUDENT-", studentObjectType},
+	{"ENROLLMENT-", enrollmentObjectType},
+	{"DEPARTMENT-", departmentObjectType},
+	{"FACULTY-", facultyObjectType},
+	{"COURSE-", courseObjectType},
+	{"EXTRACURRICULAR-", activityObjectType},
+	{"CLUB-", clubObjectType},
+	{"RATINGWEIGHTS-", ratingWeightsObjectType},
+	{"VERIFICATION-", verificationObjectType},
+	{"VERIFICATIONHASH-", verificationHashObjectType},
+	{"VCSTATUS-", credentialStatusObjectType},
+	{"STATUSLIST-", statusListObjectType},
+	{legacyLedgerUpdatePrefix, ledgerUpdateObjectType},
+}
+
+// Legacy keys that do not follow the PREFIX-id scheme
+const (
+	legacyCurriculumPrefix     = "CURRICULUM-"
+	legacyLedgerUpdateCount    = "LEDGERUPDATE_COUNT"
+	legacyLedgerUpdatePrefix   = "LEDGERUPDATE-"
+	legacyStudentProfilePrefix = "PROFILE-"
+)
+
+// KeyMigration reports the result of moving the records stored under legacy keys to composite keys
+type KeyMigration struct {
+	Migrated         map[string]int `json:"migrated"`         // Map of object type to the number of records moved
+	Superseded       int            `json:"superseded"`       // Legacy records dropped because the composite key was already written
+	SeatCountsMerged int            `json:"seatCountsMerged"` // Courses whose seat count was taken from the copy under the bare course ID
+	Profiles         int            `json:"profiles"`         // Private student profiles moved in the profile collection
+	Unknown          []string       `json:"unknown"`          // Legacy keys left in place because they match no known scheme
+}
+
+// legacyRecord is a record read from a legacy key, with the composite key it moves to
+type legacyRecord struct {
+	legacyKey  string
+	objectType string
+	key        string
+	value      []byte
+}
+
+// MigrateLedgerKeys moves every record stored under a legacy PREFIX-id key to the composite key of its object type
+// Seat counts that course registration wrote under the bare course ID are merged into the course and the copies deleted.
+// Records already written under their composite key are kept and the legacy copy is dropped. Moving the private
+// student profiles reads the profile collection, so the transaction must be endorsed by a member of it
+func (s *StudentRecordContract) MigrateLedgerKeys(ctx contractapi.TransactionContextInterface) (*KeyMigration, error) {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return nil, fmt.Errorf("Unauthorized: only admin can migrate ledger keys")
+	}
+
+	migration := KeyMigration{Migrated: make(map[string]int), Unknown: []string{}}
+
+	// Read every simple key in the ledger, composite keys are not part of the range
+	records, seatCopies, err := readLegacyRecords(ctx, &migration)
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge the seat counts into the courses, a bare copy without a course becomes the course
+	courseRecords := make(map[string]int)
+	for index, record := range records {
+		if record.objectType == courseObjectType {
+			courseRecords[record.key] = index
+		}
+	}
+	courseIDs := make([]string, 0, len(seatCopies))
+	for courseID := range seatCopies {
+		courseIDs = append(courseIDs, courseID)
+	}
+	sort.Strings(courseIDs)
+	for _, courseID := range courseIDs {
+		seatCopy := seatCopies[courseID]
+		key, err := entityKey(ctx, courseObjectType, courseID)
+		if err != nil {
+			return nil, err
+		}
+
+		var course Course
+		if index, exists := courseRecords[key]; exists {
+			if err := json.Unmarshal(records[index].value, &course); err != nil {
+				return nil, fmt.Errorf("unmarhsal error")
+			}
+		} else {
+			course = seatCopy
+		}
+		course.SeatsFilled = seatCopy.SeatsFilled
+		courseJSON, err := json.Marshal(course)
+		if err != nil {
+			return nil, err
+		}
+
+		if index, exists := courseRecords[key]; exists {
+			records[index].value = courseJSON
+		} else {
+			records = append(records, legacyRecord{objectType: courseObjectType, key: key, value: courseJSON})
+		}
+		if err := ctx.GetStub().DelState(courseID); err != nil {
+			return nil, err
+		}
+		migration.SeatCountsMerged++
+	}
+
+	// Move the records, keeping any record already written under its composite key
+	for _, record := range records {
+		existing, err := ctx.GetStub().GetState(record.key)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			if err := ctx.GetStub().PutState(record.key, record.value); err != nil {
+				return nil, err
+			}
+			migration.Migrated[record.objectType]++
+		} else {
+			migration.Superseded++
+		}
+		if record.legacyKey != "" {
+			if err := ctx.GetStub().DelState(record.legacyKey); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Move the private profiles of the students that have one
+	for _, record := range records {
+		if record.objectType != studentObjectType {
+			continue
+		}
+		var student Student
+		if err := json.Unmarshal(record.value, &student); err != nil {
+			return nil, fmt.Errorf("unmarhsal error")
+		}
+		if student.ProfileHash == "" {
+			continue
+		}
+		moved, err := migrateStudentProfile(ctx, student.StudentID)
+		if err != nil {
+			return nil, err
+		}
+		if moved {
+			migration.Profiles++
+		}
+	}
+
+	// Record the ledger update
+	objectTypes := make([]string, 0, len(migration.Migrated))
+	for objectType := range migration.Migrated {
+		objectTypes = append(objectTypes, objectType)
+	}
+	sort.Strings(objectTypes)
+	counts := []string{}
+	total := 0
+	for _, objectType := range objectTypes {
+		counts = append(counts, fmt.Sprintf("%s %d", objectType, migration.Migrated[objectType]))
+		total += migration.Migrated[objectType]
+	}
+	entry := fmt.Sprintf("Migrated %d records to composite keys", total)
+	if len(counts) > 0 {
+		entry += fmt.Sprintf(" (%s)", strings.Join(counts, ", "))
+	}
+	entry += fmt.Sprintf(", merged %d course seat counts, moved %d student profiles", migration.SeatCountsMerged, migration.Profiles)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &migration, nil
+}
+
+// readLegacyRecords reads the records stored under legacy keys and the course copies stored under bare course IDs
+// Keys matching no known scheme are added to the unknown keys of the migration
+func readLegacyRecords(ctx contractapi.TransactionContextInterface, migration *KeyMigration) ([]legacyRecord, map[string]Course, error) {
+	iterator, err := ctx.GetStub().GetStateByRange("", "")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer iterator.Close()
+
+	records := []legacyRecord{}
+	seatCopies := make(map[string]Course)
+	for iterator.HasNext() {
+		result, err := iterator.Next()
+		if err != nil {
+			return nil, nil, err
+		}
+		legacyKey := result.Key
+
+		// Composite keys start with a null byte and are already migrated
+		if strings.HasPrefix(legacyKey, "\x00") {
+			continue
+		}
+
+		record := legacyRecord{legacyKey: legacyKey, value: result.Value}
+		switch {
+		case legacyKey == legacyLedgerUpdateCount:
+			record.objectType = ledgerUpdateCountObjectType
+			record.key, err = entityKey(ctx, ledgerUpdateCountObjectType)
+		case strings.HasPrefix(legacyKey, legacyCurriculumPrefix):
+			var curriculum Curriculum
+			if err := json.Unmarshal(result.Value, &curriculum); err != nil {
+				return nil, nil, fmt.Errorf("unmarhsal error")
+			}
+			record.objectType = curriculumObjectType
+			record.key, err = curriculumKey(ctx, curriculum.ProgramType, curriculum.DepartmentID)
+		default:
+			for _, legacy := range legacyKeyPrefixes {
+				if strings.HasPrefix(legacyKey, legacy.prefix) {
+					record.objectType = legacy.objectType
+					record.key, err = entityKey(ctx, legacy.objectType, strings.TrimPrefix(legacyKey, legacy.prefix))
+					break
+				}
+			}
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if record.objectType == "" {
+			// Course registration stored seat counts under the bare course ID
+			var course Course
+			if json.Unmarshal(result.Value, &course) == nil && course.CourseID == legacyKey {
+				seatCopies[legacyKey] = course
+			} else {
+				migration.Unknown = append(migration.Unknown, legacyKey)
+			}
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records, seatCopies, nil
+}
+
+// migrateStudentProfile moves the private profile of a student from its legacy key to its composite key
+// Returns false if the student has no profile under the legacy key
+func migrateStudentProfile(ctx contractapi.TransactionContextInterface, studentID string) (bool, error) {
+	legacyKey := legacyStudentProfilePrefix + studentID
+	profileJSON, err := ctx.GetStub().GetPrivateData(studentProfileCollection, legacyKey)
+	if err != nil {
+		return false, fmt.Errorf("Failed to read profile of student %s: %v", studentID, err)
+	}
+	if profileJSON == nil {
+		return false, nil
+	}
+
+	key, err := entityKey(ctx, studentProfileObjectType, studentID)
+	if err != nil {
+		return false, err
+	}
+	if err := ctx.GetStub().PutPrivateData(studentProfileCollection, key, profileJSON); err != nil {
+		return false, err
+	}
+	if err := ctx.GetStub().DelPrivateData(studentProfileCollection, legacyKey); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// legacyLayout moves the records to the PREFIX-id keys they were stored under before composite keys
+func (c *testContract) legacyLayout() {
+	c.t.Helper()
+	legacyPrefixes := map[string]string{curriculumObjectType: legacyCurriculumPrefix}
+	for _, legacy := range legacyKeyPrefixes {
+		legacyPrefixes[legacy.objectType] = legacy.prefix
+	}
+
+	c.stub.MockTransactionStart("legacy")
+	defer c.stub.MockTransactionEnd("legacy")
+	keys := make([]string, 0, len(c.stub.State))
+	for key := range c.stub.State {
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		objectType, attributes, err := c.stub.SplitCompositeKey(key)
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		legacyKey := legacyPrefixes[objectType] + strings.Join(attributes, "-")
+		switch {
+		case objectType == ledgerUpdateCountObjectType:
+			legacyKey = legacyLedgerUpdateCount
+		case legacyPrefixes[objectType] == "":
+			continue
+		}
+		value := c.stub.State[key]
+		if err := c.stub.DelState(key); err != nil {
+			c.t.Fatal(err)
+		}
+		if err := c.stub.PutState(legacyKey, value); err != nil {
+			c.t.Fatal(err)
+		}
+	}
+
+	// Profiles were kept under PROFILE-studentID in the collection
+	profiles := c.stub.PvtState[studentProfileCollection]
+	for key, value := range profiles {
+		_, attributes, err := c.stub.SplitCompositeKey(key)
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		delete(profiles, key)
+		profiles[legacyStudentProfilePrefix+attributes[0]] = value
+	}
+}
+
+func TestMigrateLedgerKeys(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddCoursesToCurrentSemester", "S1", `["CS101"]`)
+	c.mustInvoke("SetCurriculum", "BTECH", "CSE", `{"coreCourses":["CS101"]}`)
+	c.transient(map[string]string{"profile": testProfile, "salt": testSalt})
+	c.mustInvoke("SetStudentProfile", "S1")
+	c.transient(nil)
+	var updates []LedgerUpdate
+	c.query(&updates, "GetAllLedgerUpdates")
+	c.legacyLayout()
+
+	// The ledger updates are read from their legacy keys until they are migrated
+	var legacyUpdates []LedgerUpdate
+	c.query(&legacyUpdates, "GetAllLedgerUpdates")
+	if len(legacyUpdates) != len(updates) || legacyUpdates[len(updates)-1] != updates[len(updates)-1] {
+		t.Fatalf("ledger updates before the migration are %+v, want %+v", legacyUpdates, updates)
+	}
+
+	// Course registration wrote the seat counts to a copy of the course under its bare ID
+	c.stub.MockTransactionStart("legacy-seats")
+	seatCopy, err := json.Marshal(Course{CourseID: "CS101", CourseName: "Programming", Credits: 37, DepartmentID: "CSE", FacultyID: "F1", SeatsFilled: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.stub.PutState("CS101", seatCopy); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.stub.PutState("MYSTERY", []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	c.stub.MockTransactionEnd("legacy-seats")
+
+	var migration KeyMigration
+	c.query(&migration, "MigrateLedgerKeys")
+	if migration.Migrated[courseObjectType] != 5 || migration.Migrated[curriculumObjectType] != 1 || migration.Migrated[studentObjectType] != 1 ||
+		migration.Migrated[ledgerUpdateCountObjectType] != 1 || migration.SeatCountsMerged != 1 || migration.Profiles != 1 ||
+		migration.Superseded != 0 || strings.Join(migration.Unknown, ",") != "MYSTERY" {
+		t.Fatalf("migration is %+v", migration)
+	}
+
+	// Only the unknown key is left outside the composite keys
+	for key := range c.stub.State {
+		if !strings.HasPrefix(key, "\x00") && key != "MYSTERY" {
+			t.Errorf("legacy key %q is left", key)
+		}
+	}
+
+	// The records are read from their composite keys, with the merged seat count
+	var course Course
+	c.query(&course, "GetCourse", "CS101")
+	if course.SeatsFilled != 7 || course.MaxSeats != 30 {
+		t.Fatalf("migrated course is %+v", course)
+	}
+	var curriculum Curriculum
+	c.query(&curriculum, "GetCurriculum", "BTECH", "CSE")
+	if strings.Join(curriculum.CoreCourses, ",") != "CS101" {
+		t.Fatalf("migrated curriculum is %+v", curriculum)
+	}
+	var profile StudentProfile
+	c.query(&profile, "GetStudentProfile", "S1")
+	if profile.Email != "asha@example.org" {
+		t.Fatalf("migrated profile is %+v", profile)
+	}
+	if entry := c.lastLedgerUpdate(); !strings.HasPrefix(entry, "Migrated ") || !strings.HasSuffix(entry, "merged 1 course seat counts, moved 1 student profiles") {
+		t.Fatalf("ledger update is %q", entry)
+	}
+	c.mustInvoke("DropCoursesFromCurrentSemester", "S1", `["CS101"]`)
+	c.query(&course, "GetCourse", "CS101")
+	if course.SeatsFilled != 6 {
+		t.Fatalf("course has %d seats filled after the drop, want 6", course.SeatsFilled)
+	}
+
+	// Migrating again moves nothing
+	var again KeyMigration
+	c.query(&again, "MigrateLedgerKeys")
+	if len(again.Migrated) != 0 || again.SeatCountsMerged != 0 || again.Profiles != 0 {
+		t.Fatalf("second migration is %+v", again)
+	}
+}
+
+func TestMigrateLedgerKeysKeepsNewerRecords(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	// A legacy copy left next to the record written under its composite key
+	c.stub.MockTransactionStart("legacy")
+	legacyDepartment, err := json.Marshal(Department{DepartmentID: "CSE", DepartmentName: "Computing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.stub.PutState("DEPARTMENT-CSE", legacyDepartment); err != nil {
+		t.Fatal(err)
+	}
+	c.stub.MockTransactionEnd("legacy")
+
+	var migration KeyMigration
+	c.query(&migration, "MigrateLedgerKeys")
+	if migration.Superseded != 1 || len(migration.Migrated) != 0 {
+		t.Fatalf("migration is %+v", migration)
+	}
+	var department Department
+	c.query(&department, "GetDepartment", "CSE")
+	if department.DepartmentName != "Computer Science" {
+		t.Fatalf("department is %+v, want the record under the composite key", department)
+	}
+	if _, exists := c.stub.State["DEPARTMENT-CSE"]; exists {
+		t.Fatal("legacy copy is left")
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Participation records a student's registration for an extracurricular activity and whether they took part
+type Participation struct {
+	ActivityID   string  `json:"activityID"`
+	Status       string  `json:"status"`       // registered, attended, absent or withdrawn
+	Hours        float64 `json:"hours"`        // Hours of participation credited to the student
+	Points       int     `json:"points"`       // Points earned for the activity
+	RegisteredAt string  `json:"registeredAt"` // Time the student registered for the activity
+	MarkedBy     string  `json:"markedBy"`     // Faculty who marked the attendance
+	MarkedAt     string  `json:"markedAt"`     // Time the attendance was marked
+}
+
+// Participation statuses
+const (
+	participationRegistered = "registered"
+	participationAttended   = "attended"
+	participationAbsent     = "absent"
+	participationWithdrawn  = "withdrawn"
+)
+
+// AttendanceEntry is the attendance of one student in a bulk attendance update
+type AttendanceEntry struct {
+	StudentID string  `json:"studentID"`
+	Status    string  `json:"status"` // attended, absent or withdrawn
+	Hours     float64 `json:"hours"`
+	Points    int     `json:"points"`
+}
+
+// ActivityParticipant is a registered student of an activity together with their participation
+type ActivityParticipant struct {
+	StudentID     string        `json:"studentID"`
+	StudentName   string        `json:"studentName"`
+	Participation Participation `json:"participation"`
+}
+
+// ActivityRecord is an activity on a student's profile
+type ActivityRecord struct {
+	ActivityID        string        `json:"activityID"`
+	ActivityName      string        `json:"activityName"`
+	Date              string        `json:"date"`
+	FacultyID         string        `json:"facultyID"`
+	Participation     Participation `json:"participation"`
+	CertificateStatus string        `json:"certificateStatus"` // Review status of the certificate, empty if none was submitted
+}
+
+// ActivityProfile summarises a student's extracurricular participation
+type ActivityProfile struct {
+	StudentID            string           `json:"studentID"`
+	Name                 string           `json:"name"`
+	Activities           []ActivityRecord `json:"activities"`
+	RegisteredCount      int              `json:"registeredCount"`
+	AttendedCount        int              `json:"attendedCount"`
+	TotalHours           float64          `json:"totalHours"`
+	TotalPoints          int              `json:"totalPoints"`
+	ApprovedCertificates int              `json:"approvedCertificates"`
+}
+
+// MarkActivityAttendance marks the attendance, hours and points of the registered students of an activity in bulk
+// attendanceJSON is a list of AttendanceEntry; only the faculty in charge of the activity can mark attendance, and the
+// faculty is the one named by the facultyID attribute of the caller's certificate
+func (s *StudentRecordContract) MarkActivityAttendance(ctx contractapi.TransactionContextInterface, activityID string, attendanceJSON string) error {
+	caller := ctx.GetClientIdentity()
+	if !s.isFaculty(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only faculty can mark attendance")
+	}
+	facultyID, found := s.callerFacultyID(ctx, caller)
+	if !found {
+		return fmt.Errorf("Unauthorized: the caller's certificate has no facultyID attribute")
+	}
+
+	var attendance []AttendanceEntry
+	if err := json.Unmarshal([]byte(attendanceJSON), &attendance); err != nil {
+		return fmt.Errorf("unmarhsal error")
+	}
+	if len(attendance) == 0 {
+		return fmt.Errorf("No attendance entries given")
+	}
+
+	// Check that the faculty is in charge of the activity
+	activity, err := s.GetExtracurricularActivity(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	if facultyID != activity.FacultyID {
+		return fmt.Errorf("Faculty %s is not in charge of extracurricular activity %s", facultyID, activityID)
+	}
+	if activity.Status == activityCancelled {
+		return fmt.Errorf("Extracurricular activity %s has been cancelled", activityID)
+	}
+
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	markedAt := now.Format(time.RFC3339)
+
+	// Validate every entry before updating any enrollment
+	enrollments := make(map[string]Enrollment)
+	for _, entry := range attendance {
+		if _, duplicate := enrollments[entry.StudentID]; duplicate {
+			return fmt.Errorf("Attendance for student %s is given more than once", entry.StudentID)
+		}
+		if entry.Status != participationAttended && entry.Status != participationAbsent && entry.Status != participationWithdrawn {
+			return fmt.Errorf("Invalid attendance status %s for student %s", entry.Status, entry.StudentID)
+		}
+		if entry.Hours < 0 || entry.Points < 0 {
+			return fmt.Errorf("Hours and points for student %s cannot be negative", entry.StudentID)
+		}
+		if entry.Status != participationAttended && (entry.Hours != 0 || entry.Points != 0) {
+			return fmt.Errorf("Student %s is marked %s and cannot earn hours or points", entry.StudentID, entry.Status)
+		}
+		// Students can be marked withdrawn ahead of the activity, but not as attended or absent
+		if entry.Status != participationWithdrawn {
+			if err := ensureActivityStarted(activity, now); err != nil {
+				return err
+			}
+		}
+
+		enrollment, err := s.GetEnrollment(ctx, entry.StudentID)
+		if err != nil {
+			return err
+		}
+		if err := ensureEnrollmentActive(enrollment); err != nil {
+			return err
+		}
+		participation, registered := participationFor(enrollment, activityID)
+		if !registered {
+			return fmt.Errorf("Student %s has not registered for extracurricular activity %s", entry.StudentID, activityID)
+		}
+		// A withdrawn student no longer holds a place, so must register again, within the capacity, to be marked
+		if participation.Status == participationWithdrawn {
+			return fmt.Errorf("Student %s has withdrawn from extracurricular activity %s", entry.StudentID, activityID)
+		}
+
+		participation.Status = entry.Status
+		participation.Hours = entry.Hours
+		participation.Points = entry.Points
+		participation.MarkedBy = facultyID
+		participation.MarkedAt = markedAt
+		setParticipation(&enrollment, participation)
+		enrollments[entry.StudentID] = enrollment
+	}
+
+	// Update the enrollments and roster in the order of the entries
+	for _, entry := range attendance {
+		enrollment := enrollments[entry.StudentID]
+
+		// Update the enrollment in the ledger
+		enrollmentJSON, _ := json.Marshal(enrollment)
+		err = putEntityState(ctx, enrollmentObjectType, entry.StudentID, enrollmentJSON)
+		if err != nil {
+			return err
+		}
+
+		change, err := putRosterEntry(ctx, enrollment, enrollment.Participation[activityID])
+		if err != nil {
+			return err
+		}
+		activity.ParticipantCount += change
+	}
+
+	// Students marked withdrawn free their places
+	err = s.putExtracurricularActivity(ctx, activity)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Marked attendance of %d students for extracurricular activity %s by faculty %s", len(attendance), activityID, facultyID)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ensureActivityStarted checks that the first day of an activity has come
+func ensureActivityStarted(activity ExtracurricularActivity, now time.Time) error {
+	start, err := time.ParseInLocation(activityDateLayout, activity.StartDate, indianTimeZone)
+	if err != nil {
+		return fmt.Errorf("Invalid start date %s for extracurricular activity %s", activity.StartDate, activity.ActivityID)
+	}
+	if now.Before(start) {
+		return fmt.Errorf("Extracurricular activity %s starts on %s, attendance cannot be marked before it", activity.ActivityID, activity.StartDate)
+	}
+
+	return nil
+}
+
+// GetActivityParticipants returns the students on the roster of an activity with their participation
+func (s *StudentRecordContract) GetActivityParticipants(ctx contractapi.TransactionContextInterface, activityID string) ([]ActivityParticipant, error) {
+	_, err := s.GetExtracurricularActivity(ctx, activityID)
+	if err != nil {
+		return nil, err
+	}
+
+	roster, err := getActivityRoster(ctx, activityID)
+	if err != nil {
+		return nil, err
+	}
+
+	participants := make([]ActivityParticipant, 0, len(roster))
+	for _, entry := range roster {
+		enrollment, err := s.GetEnrollment(ctx, entry.StudentID)
+		if err != nil {
+			return nil, err
+		}
+		participation, _ := participationFor(enrollment, activityID)
+		participants = append(participants, ActivityParticipant{
+			StudentID:     entry.StudentID,
+			StudentName:   enrollment.Name,
+			Participation: participation,
+		})
+	}
+
+	return participants, nil
+}
+
+// GetStudentActivityProfile returns a student's extracurricular activities, participation, hours, points and certificates
+func (s *StudentRecordContract) GetStudentActivityProfile(ctx contractapi.TransactionContextInterface, studentID string) (*ActivityProfile, error) {
+	enrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := ActivityProfile{
+		StudentID:  studentID,
+		Name:       enrollment.Name,
+		Activities: []ActivityRecord{},
+	}
+
+	for _, activityID := range enrollment.Extracurricular {
+		participation, _ := participationFor(enrollment, activityID)
+		record := ActivityRecord{
+			ActivityID:    activityID,
+			Participation: participation,
+		}
+
+		// The activity may have been removed since the student registered
+		activity, err := s.GetExtracurricularActivity(ctx, activityID)
+		if err == nil {
+			record.ActivityName = activity.ActivityName
+			record.Date = activity.Date
+			record.FacultyID = activity.FacultyID
+		}
+
+		index := certificateIndex(enrollment.Certificates, activityID)
+		if index >= 0 {
+			certificate := enrollment.Certificates[index]
+			record.CertificateStatus = certificate.Status
+			if certificate.isApproved() {
+				record.CertificateStatus = certificateApproved
+				profile.ApprovedCertificates++
+			}
+		}
+
+		if participation.Status != participationWithdrawn {
+			profile.RegisteredCount++
+		}
+		if participation.Status == participationAttended {
+			profile.AttendedCount++
+			profile.TotalHours += participation.Hours
+			profile.TotalPoints += participation.Points
+		}
+
+		profile.Activities = append(profile.Activities, record)
+	}
+
+	return &profile, nil
+}
+
+// participationFor returns the participation of a student in an activity and whether the student registered for it
+// Registrations made before participation was recorded are treated as registered
+func participationFor(enrollment Enrollment, activityID string) (Participation, bool) {
+	if participation, exists := enrollment.Participation[activityID]; exists {
+		return participation, true
+	}
+	if contains(enrollment.Extracurricular, activityID) {
+		return Participation{ActivityID: activityID, Status: participationRegistered}, true
+	}
+	return Participation{}, false
+}
+
+// setParticipation stores the participation of a student in an activity on the enrollment
+func setParticipation(enrollment *Enrollment, participation Participation) {
+	if enrollment.Participation == nil {
+		enrollment.Participation = make(map[string]Participation)
+	}
+	enrollment.Participation[participation.ActivityID] = participation
+}
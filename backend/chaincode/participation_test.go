@@ -0,0 +1,89 @@
+package main
+
+import "testing"
+
+func TestMarkActivityAttendance(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddFaculty", "F2", "Second Faculty", "CSE")
+	c.mustInvoke("InitialEnrollment", "S2", "Ravi", "BTECH", "CSE")
+	c.mustInvoke("AddExtracurricularActivity", "A1", "Chess", "Inter-hostel chess", "Hall 1", "01012030", "10", "F1")
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S1", "A1")
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S2", "A1")
+
+	// The faculty is the one of the caller's certificate
+	c.at("01012030")
+	c.mustFail("the caller's certificate has no facultyID attribute", "MarkActivityAttendance", "A1", `[{"studentID":"S1","status":"attended","hours":2}]`)
+	c.as(map[string]string{"facultyID": "F2"})
+	c.mustFail("Faculty F2 is not in charge of", "MarkActivityAttendance", "A1", `[{"studentID":"S1","status":"attended","hours":2}]`)
+	c.as(map[string]string{"facultyID": "F1"})
+
+	// Attendance cannot be marked before the activity starts
+	c.at("31122029")
+	c.mustFail("attendance cannot be marked before it", "MarkActivityAttendance", "A1", `[{"studentID":"S1","status":"attended","hours":2}]`)
+
+	c.at("01012030")
+	c.mustFail("No attendance entries", "MarkActivityAttendance", "A1", `[]`)
+	c.mustFail("Invalid attendance status registered", "MarkActivityAttendance", "A1", `[{"studentID":"S1","status":"registered"}]`)
+	c.mustFail("cannot earn hours or points", "MarkActivityAttendance", "A1", `[{"studentID":"S1","status":"absent","hours":2}]`)
+	c.mustFail("cannot be negative", "MarkActivityAttendance", "A1", `[{"studentID":"S1","status":"attended","hours":-1}]`)
+	c.mustFail("more than once", "MarkActivityAttendance", "A1", `[{"studentID":"S1","status":"absent"},{"studentID":"S1","status":"absent"}]`)
+	c.mustFail("does not exist", "MarkActivityAttendance", "A1", `[{"studentID":"S9","status":"absent"}]`)
+	c.mustInvoke("InitialEnrollment", "S3", "Meera", "BTECH", "CSE")
+	c.mustFail("has not registered", "MarkActivityAttendance", "A1", `[{"studentID":"S2","status":"absent"},{"studentID":"S3","status":"absent"}]`)
+
+	// A failed bulk update leaves every student as they were
+	if participation := c.enrollment("S2").Participation["A1"]; participation.Status != participationRegistered {
+		t.Fatalf("participation of S2 after a failed update is %+v", participation)
+	}
+
+	c.mustInvoke("MarkActivityAttendance", "A1", `[{"studentID":"S1","status":"attended","hours":2.5,"points":10},{"studentID":"S2","status":"absent"}]`)
+	c.as(nil)
+	c.restart()
+	var participants []ActivityParticipant
+	c.query(&participants, "GetActivityParticipants", "A1")
+	if len(participants) != 2 {
+		t.Fatalf("participants are %+v", participants)
+	}
+	for _, participant := range participants {
+		participation := participant.Participation
+		switch participant.StudentID {
+		case "S1":
+			if participation.Status != participationAttended || participation.Hours != 2.5 || participation.Points != 10 || participation.MarkedBy != "F1" {
+				t.Fatalf("participation of S1 is %+v", participation)
+			}
+		case "S2":
+			if participation.Status != participationAbsent || participation.Hours != 0 || participant.StudentName != "Ravi" {
+				t.Fatalf("participation of S2 is %+v", participant)
+			}
+		}
+	}
+
+	// Only students who attended can submit a certificate
+	c.mustInvoke("AddCertificateForStudent", "S1", "A1", "key1", testCertificateHash, "10", "application/pdf", "")
+	c.mustFail("not been marked as attended", "AddCertificateForStudent", "S2", "A1", "key2", testFileHash, "10", "application/pdf", "")
+}
+
+func TestGetStudentActivityProfile(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.attendActivity()
+	c.mustInvoke("AddExtracurricularActivity", "A2", "Debate", "Debating society", "Hall 2", "05012030", "10", "F1")
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S1", "A2")
+	c.mustInvoke("AddCertificateForStudent", "S1", "A1", "key1", testCertificateHash, "10", "application/pdf", "")
+	if _, err := c.review("F1", certificateApproved, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	var profile ActivityProfile
+	c.query(&profile, "GetStudentActivityProfile", "S1")
+	if profile.RegisteredCount != 2 || profile.AttendedCount != 1 || profile.TotalHours != 2 || profile.ApprovedCertificates != 1 {
+		t.Fatalf("activity profile is %+v", profile)
+	}
+	if len(profile.Activities) != 2 || profile.Activities[0].ActivityName != "Chess" || profile.Activities[0].CertificateStatus != certificateApproved {
+		t.Fatalf("activities on the profile are %+v", profile.Activities)
+	}
+	if second := profile.Activities[1]; second.Participation.Status != participationRegistered || second.CertificateStatus != "" {
+		t.Fatalf("activity A2 on the profile is %+v", second)
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// gradesCollection is the private data collection holding the grades of students whose results are private
+// Each grade is stored as a Result under the composite key RESULT~studentID~courseID; the public enrollment keeps the
+// course and transaction of every result with an empty grade, so other orgs can check a grade against the private data hash
+const gradesCollection = "gradesCollection"
+
+// privateResultObjectType is the object type of the keys of private grades
+const privateResultObjectType = "RESULT"
+
+// Keys of the grades in the transient data of the private result transactions
+const (
+	resultsTransientKey = "results"
+	gradeTransientKey   = "grade"
+)
+
+// EnablePrivateResults moves the grades of a student to the grades collection and keeps future grades there
+// Grades recorded before remain in the history of the public ledger. The completed credits, which tell which courses
+// were passed, are left out of the public enrollment from then on. A random salt of at least 16 bytes is passed in the
+// transient data under the key "salt", as for every transaction storing private grades
+func (s *StudentRecordContract) EnablePrivateResults(ctx contractapi.TransactionContextInterface, studentID string) error {
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can make results private")
+	}
+
+	enrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	if err := ensureEnrollmentModifiable(enrollment); err != nil {
+		return err
+	}
+	if enrollment.PrivateResults {
+		return fmt.Errorf("Results of student %s are already private", studentID)
+	}
+
+	// Move the grades to the collection, keeping the transaction that recorded each of them
+	for semester, semesterResults := range enrollment.SemesterResults {
+		for index, result := range semesterResults {
+			if err := putPrivateResult(ctx, studentID, result); err != nil {
+				return err
+			}
+			enrollment.SemesterResults[semester][index].Grade = ""
+		}
+	}
+	enrollment.PrivateResults = true
+	enrollment.CreditsCompleted = 0
+
+	// Update the enrollment in the ledger
+	enrollmentJSON, err := json.Marshal(enrollment)
+	if err != nil {
+		return err
+	}
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Made results of student %s private", studentID)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddPrivateResultForCurrentSemester adds results for the current semester of a student whose results are private
+// The results are passed as a JSON list of Result in the transient data under the key "results", with a salt under "salt"
+func (s *StudentRecordContract) AddPrivateResultForCurrentSemester(ctx contractapi.TransactionContextInterface, studentID string) error {
+	resultsJSON, err := transientValue(ctx, resultsTransientKey)
+	if err != nil {
+		return err
+	}
+
+	var resultsToAdd []Result
+	if err := json.Unmarshal(resultsJSON, &resultsToAdd); err != nil {
+		return fmt.Errorf("unmarhsal error")
+	}
+
+	return s.addResultsForCurrentSemester(ctx, studentID, resultsToAdd, true)
+}
+
+// UpdatePrivateGradeForCourse updates the grade of a course for a student whose results are private
+// The new grade is passed in the transient data under the key "grade", with a salt under "salt"
+func (s *StudentRecordContract) UpdatePrivateGradeForCourse(ctx contractapi.TransactionContextInterface, studentID string, courseID string) error {
+	newGrade, err := transientValue(ctx, gradeTransientKey)
+	if err != nil {
+		return err
+	}
+
+	return s.updateGradeForCourse(ctx, studentID, courseID, string(newGrade), true)
+}
+
+// VerifyPrivateResult checks a grade claimed by a student, with the salt stored with it, against the hash of the private grade
+// Works on the peers of every org, as the hashes of private data are on the public ledger
+func (s *StudentRecordContract) VerifyPrivateResult(ctx contractapi.TransactionContextInterface, studentID string, courseID string, grade string, salt string) (bool, error) {
+	enrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return false, err
+	}
+	if !enrollment.PrivateResults {
+		return false, fmt.Errorf("Results of student %s are not private", studentID)
+	}
+
+	// The public result names the transaction that recorded the grade
+	txID := ""
+	for _, semesterResults := range enrollment.SemesterResults {
+		for _, result := range semesterResults {
+			if result.CourseID == courseID {
+				txID = result.TxID
+			}
+		}
+	}
+	if txID == "" {
+		return false, fmt.Errorf("No result for course %s for student %s", courseID, studentID)
+	}
+
+	key, err := ctx.GetStub().CreateCompositeKey(privateResultObjectType, []string{studentID, courseID})
+	if err != nil {
+		return false, err
+	}
+	privateHash, err := ctx.GetStub().GetPrivateDataHash(gradesCollection, key)
+	if err != nil {
+		return false, fmt.Errorf("Failed to read hash of private grade of course %s for student %s: %v", courseID, studentID, err)
+	}
+	if privateHash == nil {
+		return false, fmt.Errorf("No private grade of course %s for student %s", courseID, studentID)
+	}
+
+	claimedJSON, err := json.Marshal(Result{CourseID: courseID, Grade: grade, TxID: txID, Salt: salt})
+	if err != nil {
+		return false, err
+	}
+	claimedHash := sha256.Sum256(claimedJSON)
+
+	return bytes.Equal(claimedHash[:], privateHash), nil
+}
+
+// getSemesterResults returns the results of every semester of a student, with the grades read from the grades
+// collection if the results are private
+// Only peers of orgs that are members of the collection can read private grades
+func (s *StudentRecordContract) getSemesterResults(ctx contractapi.TransactionContextInterface, enrollment Enrollment) (map[string][]Result, error) {
+	if !enrollment.PrivateResults {
+		return enrollment.SemesterResults, nil
+	}
+
+	semesterResults := make(map[string][]Result)
+	for semester, publicResults := range enrollment.SemesterResults {
+		results := make([]Result, 0, len(publicResults))
+		for _, publicResult := range publicResults {
+			key, err := ctx.GetStub().CreateCompositeKey(privateResultObjectType, []string{enrollment.StudentID, publicResult.CourseID})
+			if err != nil {
+				return nil, err
+			}
+			resultJSON, err := ctx.GetStub().GetPrivateData(gradesCollection, key)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to read private grade of course %s for student %s: %v", publicResult.CourseID, enrollment.StudentID, err)
+			}
+			if resultJSON == nil {
+				return nil, fmt.Errorf("Private grade of course %s for student %s does not exist", publicResult.CourseID, enrollment.StudentID)
+			}
+
+			var result Result
+			if err := json.Unmarshal(resultJSON, &result); err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+		}
+		semesterResults[semester] = results
+	}
+
+	return semesterResults, nil
+}
+
+// putPrivateResult stores the grade of a course in the grades collection, salted with the salt in the transient data
+// Each grade gets a salt of its own, derived from the salt of the transaction, so that a student showing one grade
+// with its salt does not help guess the other grades recorded with it
+func putPrivateResult(ctx contractapi.TransactionContextInterface, studentID string, result Result) error {
+	key, err := ctx.GetStub().CreateCompositeKey(privateResultObjectType, []string{studentID, result.CourseID})
+	if err != nil {
+		return err
+	}
+	salt, err := transientSalt(ctx)
+	if err != nil {
+		return err
+	}
+	resultSalt := sha256.Sum256([]byte(salt + "~" + studentID + "~" + result.CourseID))
+	result.Salt = hex.EncodeToString(resultSalt[:])
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return ctx.GetStub().PutPrivateData(gradesCollection, key, resultJSON)
+}
+
+// ensureResultPrivacy checks that results are submitted the way the results of the student are kept
+func ensureResultPrivacy(enrollment Enrollment, private bool) error {
+	if enrollment.PrivateResults && !private {
+		return fmt.Errorf("Results of student %s are private and must be submitted as transient data", enrollment.StudentID)
+	}
+	if !enrollment.PrivateResults && private {
+		return fmt.Errorf("Results of student %s are not private", enrollment.StudentID)
+	}
+	return nil
+}
+
+// transientValue returns the value of a key in the transient data of the transaction
+func transientValue(ctx contractapi.TransactionContextInterface, key string) ([]byte, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read transient data: %v", err)
+	}
+	value, exists := transientMap[key]
+	if !exists {
+		return nil, fmt.Errorf("The transient data must contain the key %s", key)
+	}
+	return value, nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/shimtest"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const testResultSalt = "0123456789abcdef0123"
+
+// privateHashStub gives the hashes of private data, which the MockStub does not implement
+type privateHashStub struct {
+	*shimtest.MockStub
+}
+
+func (stub *privateHashStub) GetPrivateDataHash(collection string, key string) ([]byte, error) {
+	value, err := stub.GetPrivateData(collection, key)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(value)
+	return hash[:], nil
+}
+
+// privateResults enrolls S1 in CS101 and CS102, grades CS101 in public and makes the results private
+func (c *testContract) privateResults() {
+	c.t.Helper()
+	c.mustInvoke("AddCoursesToCurrentSemester", "S1", `["CS101","CS102"]`)
+	c.mustInvoke("AddResultForCurrentSemester", "S1", `[{"courseID":"CS101","grade":"A"}]`)
+	c.transient(map[string]string{"salt": testResultSalt})
+	c.mustInvoke("EnablePrivateResults", "S1")
+}
+
+func TestEnablePrivateResults(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.transient(map[string]string{"results": `[{"courseID":"CS101","grade":"A"}]`, "salt": testResultSalt})
+	c.mustFail("are not private", "AddPrivateResultForCurrentSemester", "S1")
+	c.privateResults()
+	c.mustFail("already private", "EnablePrivateResults", "S1")
+	c.transient(nil)
+
+	// The public enrollment keeps the course and transaction of the grade, but neither the grade nor the credits
+	enrollment := c.enrollment("S1")
+	results := enrollment.SemesterResults["Semester1"]
+	if !enrollment.PrivateResults || enrollment.CreditsCompleted != 0 || len(results) != 1 || results[0].Grade != "" || results[0].TxID == "" {
+		t.Fatalf("public enrollment is %+v", enrollment)
+	}
+
+	// Members of the collection still read the grade
+	var semester []Result
+	c.query(&semester, "GetResultForSemester", "S1", "Semester1")
+	if len(semester) != 1 || semester[0].Grade != "A" || semester[0].TxID != results[0].TxID || semester[0].Salt == "" {
+		t.Fatalf("private results are %+v", semester)
+	}
+	c.mustFail("must be submitted as transient data", "AddResultForCurrentSemester", "S1", `[{"courseID":"CS102","grade":"B"}]`)
+}
+
+func TestAddPrivateResultForCurrentSemester(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.privateResults()
+
+	c.mustFail("transient data must contain the key results", "AddPrivateResultForCurrentSemester", "S1")
+	c.transient(map[string]string{"results": `[{"courseID":"CS102","grade":"F"}]`})
+	c.mustFail("transient data must contain the key salt", "AddPrivateResultForCurrentSemester", "S1")
+	c.transient(map[string]string{"results": `[{"courseID":"CS102","grade":"F"}]`, "salt": testResultSalt})
+	c.mustInvoke("AddPrivateResultForCurrentSemester", "S1")
+
+	c.transient(map[string]string{"grade": "C", "salt": testResultSalt})
+	c.mustInvoke("UpdatePrivateGradeForCourse", "S1", "CS102")
+	c.transient(nil)
+	c.mustFail("must be submitted as transient data", "UpdateGradeForCourse", "S1", "CS102", "D")
+
+	// Grades are computed from the private data, and never written to the public ledger
+	if cgpa := c.mustInvoke("CalculateCGPA", "S1"); cgpa != "8" {
+		t.Fatalf("CGPA is %s, want 8", cgpa)
+	}
+	if audit := c.degreeAudit("S1"); audit.CreditsCompleted != 74 {
+		t.Fatalf("degree audit is %+v, want 74 credits completed", audit)
+	}
+	if enrollment := c.mustInvoke("GetEnrollment", "S1"); strings.Contains(enrollment, `"grade":"C"`) || strings.Contains(enrollment, `"grade":"A"`) {
+		t.Fatalf("public enrollment shows a grade: %s", enrollment)
+	}
+}
+
+func TestVerifyPrivateResult(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustFail("are not private", "VerifyPrivateResult", "S1", "CS101", "A", "salt")
+	c.privateResults()
+	c.transient(nil)
+	c.mustFail("No result for course CS102", "VerifyPrivateResult", "S1", "CS102", "A", "salt")
+
+	// The student shows the grade with the salt stored with it
+	var semester []Result
+	c.query(&semester, "GetResultForSemester", "S1", "Semester1")
+	salt := semester[0].Salt
+
+	ctx := new(contractapi.TransactionContext)
+	ctx.SetStub(&privateHashStub{MockStub: c.stub})
+	contract := new(StudentRecordContract)
+	tests := []struct {
+		grade string
+		salt  string
+		want  bool
+	}{
+		{"A", salt, true},
+		{"B", salt, false},
+		{"A", testResultSalt, false},
+	}
+	for _, test := range tests {
+		got, err := contract.VerifyPrivateResult(ctx, "S1", "CS101", test.grade, test.salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("VerifyPrivateResult of grade %s with salt %s returned %t, want %t", test.grade, test.salt, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// StudentProfile holds the personal details of a student
+// Profiles are kept in the studentProfileCollection private data collection, defined in collections_config.json, so only
+// the admin org holds them; the public student record keeps the SHA-256 digest of the profile. The profile is stored
+// with a random salt, so that the digest cannot be matched against guessed profiles
+type StudentProfile struct {
+	StudentID     string `json:"studentID"`
+	DateOfBirth   string `json:"dateOfBirth"` // DDMMYYYY
+	Email         string `json:"email"`
+	Phone         string `json:"phone"`
+	GuardianName  string `json:"guardianName"`
+	GuardianPhone string `json:"guardianPhone"`
+	Address       string `json:"address"`
+	PhotoRef      string `json:"photoRef"` // Storage key of the student's photo
+	UpdatedAt     string `json:"updatedAt"`
+	Salt          string `json:"salt"` // Hex encoded random salt, hashed with the rest of the profile
+}
+
+// studentProfileCollection is the private data collection holding the student profiles
+const studentProfileCollection = "studentProfileCollection"
+
+// studentProfileTransientKey is the key of the profile in the transient data of SetStudentProfile
+const studentProfileTransientKey = "profile"
+
+// saltTransientKey is the key of the random salt in the transient data of the transactions storing private data
+// The salt is chosen by the client, as a value generated by the chaincode would differ between the endorsing peers
+const saltTransientKey = "salt"
+
+// minSaltLength is the minimum number of bytes of a salt
+const minSaltLength = 16
+
+// SetStudentProfile stores the profile of a student in the private data collection
+// The profile is passed as JSON in the transient data under the key "profile" so that it never appears in the transaction,
+// together with a random salt of at least 16 bytes under the key "salt"
+func (s *StudentRecordContract) SetStudentProfile(ctx contractapi.TransactionContextInterface, studentID string) error {
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) && !s.isStudentSelf(ctx, caller, studentID) {
+		return fmt.Errorf("Unauthorized: only admin or the student can set the student profile")
+	}
+
+	profileJSON, err := transientValue(ctx, studentProfileTransientKey)
+	if err != nil {
+		return err
+	}
+
+	salt, err := transientSalt(ctx)
+	if err != nil {
+		return err
+	}
+
+	var profile StudentProfile
+	if err := json.Unmarshal(profileJSON, &profile); err != nil {
+		return fmt.Errorf("unmarhsal error")
+	}
+	if profile.StudentID != "" && profile.StudentID != studentID {
+		return fmt.Errorf("Profile is for student %s, not %s", profile.StudentID, studentID)
+	}
+	if profile.DateOfBirth != "" {
+		if _, err := time.Parse(activityDateLayout, profile.DateOfBirth); err != nil {
+			return fmt.Errorf("Invalid date of birth %s: expected DDMMYYYY", profile.DateOfBirth)
+		}
+	}
+	if profile.Email != "" && !strings.Contains(profile.Email, "@") {
+		return fmt.Errorf("Invalid email address %s", profile.Email)
+	}
+
+	student, err := s.GetStudent(ctx, studentID)
+	if err != nil {
+		return err
+	}
+
+	profile.StudentID = studentID
+	profile.Salt = salt
+	profile.UpdatedAt, err = txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+	profileBytes, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+	profileKey, err := entityKey(ctx, studentProfileObjectType, studentID)
+	if err != nil {
+		return err
+	}
+	err = ctx.GetStub().PutPrivateData(studentProfileCollection, profileKey, profileBytes)
+	if err != nil {
+		return err
+	}
+
+	// Keep the digest of the profile on the public student record
+	student.ProfileHash = profileHash(profileBytes)
+	studentJSON, err := json.Marshal(student)
+	if err != nil {
+		return err
+	}
+	err = putEntityState(ctx, studentObjectType, studentID, studentJSON)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update without any of the private details
+	entry := fmt.Sprintf("Updated private profile of student %s (sha256 %s)", studentID, student.ProfileHash)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetStudentProfile retrieves the profile of a student from the private data collection
+// Only peers of the admin org hold the profiles, so the query must be sent to one of them
+func (s *StudentRecordContract) GetStudentProfile(ctx contractapi.TransactionContextInterface, studentID string) (*StudentProfile, error) {
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) && !s.isStudentSelf(ctx, caller, studentID) {
+		return nil, fmt.Errorf("Unauthorized: only admin or the student can read the student profile")
+	}
+
+	profileKey, err := entityKey(ctx, studentProfileObjectType, studentID)
+	if err != nil {
+		return nil, err
+	}
+	profileJSON, err := ctx.GetStub().GetPrivateData(studentProfileCollection, profileKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read profile of student %s: %v", studentID, err)
+	}
+	if profileJSON == nil {
+		return nil, fmt.Errorf("Profile of student %s does not exist", studentID)
+	}
+
+	var profile StudentProfile
+	err = json.Unmarshal(profileJSON, &profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+// VerifyStudentProfile checks a profile shared by a student against the digest on the public student record
+// profileJSON must be the profile exactly as returned by GetStudentProfile, salt included
+func (s *StudentRecordContract) VerifyStudentProfile(ctx contractapi.TransactionContextInterface, studentID string, profileJSON string) (bool, error) {
+	student, err := s.GetStudent(ctx, studentID)
+	if err != nil {
+		return false, err
+	}
+	if student.ProfileHash == "" {
+		return false, fmt.Errorf("Student %s has no profile", studentID)
+	}
+
+	var profile StudentProfile
+	if err := json.Unmarshal([]byte(profileJSON), &profile); err != nil {
+		return false, fmt.Errorf("unmarhsal error")
+	}
+	profileBytes, err := json.Marshal(profile)
+	if err != nil {
+		return false, err
+	}
+
+	return profileHash(profileBytes) == student.ProfileHash, nil
+}
+
+// profileHash returns the hex encoded SHA-256 digest of a stored profile
+func profileHash(profileBytes []byte) string {
+	digest := sha256.Sum256(profileBytes)
+	return hex.EncodeToString(digest[:])
+}
+
+// transientSalt returns the hex encoded salt in the transient data of the transaction
+func transientSalt(ctx contractapi.TransactionContextInterface) (string, error) {
+	salt, err := transientValue(ctx, saltTransientKey)
+	if err != nil {
+		return "", err
+	}
+	if len(salt) < minSaltLength {
+		return "", fmt.Errorf("The salt must be at least %d bytes", minSaltLength)
+	}
+	return hex.EncodeToString(salt), nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const (
+	testProfile = `{"dateOfBirth":"01022003","email":"asha@example.org","address":"Hostel 4"}`
+	testSalt    = "0123456789abcdef0123"
+)
+
+func TestSetStudentProfile(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	c.mustFail("transient data must contain the key profile", "SetStudentProfile", "S1")
+	c.transient(map[string]string{"profile": testProfile})
+	c.mustFail("transient data must contain the key salt", "SetStudentProfile", "S1")
+	c.transient(map[string]string{"profile": testProfile, "salt": "short"})
+	c.mustFail("salt must be at least 16 bytes", "SetStudentProfile", "S1")
+	c.transient(map[string]string{"profile": `{"dateOfBirth":"31022003"}`, "salt": testSalt})
+	c.mustFail("Invalid date of birth", "SetStudentProfile", "S1")
+	c.transient(map[string]string{"profile": `{"email":"asha"}`, "salt": testSalt})
+	c.mustFail("Invalid email address", "SetStudentProfile", "S1")
+	c.transient(map[string]string{"profile": `{"studentID":"S2"}`, "salt": testSalt})
+	c.mustFail("not S1", "SetStudentProfile", "S1")
+	c.transient(map[string]string{"profile": testProfile, "salt": testSalt})
+	c.mustFail("does not exist", "SetStudentProfile", "S9")
+	c.mustInvoke("SetStudentProfile", "S1")
+	c.transient(nil)
+
+	var profile StudentProfile
+	c.query(&profile, "GetStudentProfile", "S1")
+	if profile.StudentID != "S1" || profile.Email != "asha@example.org" || profile.Salt == "" || profile.UpdatedAt == "" {
+		t.Fatalf("profile is %+v", profile)
+	}
+	c.mustFail("does not exist", "GetStudentProfile", "S2")
+
+	// Only the digest is public, and no private detail is in the ledger updates
+	var student Student
+	c.query(&student, "GetStudent", "S1")
+	if len(student.ProfileHash) != 64 {
+		t.Fatalf("student record has profile hash %q", student.ProfileHash)
+	}
+	updates := c.mustInvoke("GetAllLedgerUpdates")
+	if strings.Contains(updates, "asha@example.org") || strings.Contains(c.mustInvoke("GetStudent", "S1"), "Hostel 4") {
+		t.Fatal("private profile details are public")
+	}
+}
+
+func TestVerifyStudentProfile(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustFail("has no profile", "VerifyStudentProfile", "S1", testProfile)
+
+	c.transient(map[string]string{"profile": testProfile, "salt": testSalt})
+	c.mustInvoke("SetStudentProfile", "S1")
+	c.transient(nil)
+
+	shared := c.mustInvoke("GetStudentProfile", "S1")
+	if c.mustInvoke("VerifyStudentProfile", "S1", shared) != "true" {
+		t.Fatal("profile shared by the student does not verify")
+	}
+
+	var profile StudentProfile
+	if err := json.Unmarshal([]byte(shared), &profile); err != nil {
+		t.Fatal(err)
+	}
+	profile.Address = "Hostel 5"
+	altered, _ := json.Marshal(profile)
+	if c.mustInvoke("VerifyStudentProfile", "S1", string(altered)) != "false" {
+		t.Fatal("altered profile verifies")
+	}
+
+	// Without the salt, a guessed profile does not match the digest
+	profile.Address = "Hostel 4"
+	profile.Salt = ""
+	guessed, _ := json.Marshal(profile)
+	if c.mustInvoke("VerifyStudentProfile", "S1", string(guessed)) != "false" {
+		t.Fatal("profile without the salt verifies")
+	}
+}
@@ -1,35 +1,83 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 )
 
 // Program represents program information including maximum allowed semesters
 type Program struct {
-	Name                 string `json:"name"`
-	MaxSemesters         int    `json:"maxSemesters"`
-	RequiredCredits      int    `json:"requiredCredits"`
-	MaxCreditPerSemester int    `json:"maxCreditPerCredits"`
-	MinCreditPerSemester int    `json:"minCreditPerCredits"`
+	Name                 string   `json:"name"`
+	MaxSemesters         int      `json:"maxSemesters"`
+	RequiredCredits      int      `json:"requiredCredits"`
+	MaxCreditPerSemester int      `json:"maxCreditPerCredits"`
+	MinCreditPerSemester int      `json:"minCreditPerCredits"`
+	CoreCourses          []string `json:"coreCourses"` // Courses every student of the program must pass to graduate
+	MinCGPA              float64  `json:"minCGPA"`     // Minimum CGPA required for degree conferral
+	Archived             bool     `json:"archived"`    // Archived programs cannot take new students
+	Version              int      `json:"version"`     // Number of edits made to the program
+	SchemaVersion        int      `json:"schemaVersion"`
 }
 
-// var programMapping = make(map[string]Program)
+// Minimum CGPA required for degree conferral, until SetProgramGraduationRequirements sets another
+const defaultMinCGPA = 5.0
 
 // Define the programs with their maximum allowed semesters
-var programMapping = map[string]Program{
-	"BTECH": {
+// The built-in programs are stored on the ledger by InitLedger, like programs added with AddProgram
+var builtinPrograms = []Program{
+	{
 		Name:                 "BTECH",
 		MaxSemesters:         8,
 		RequiredCredits:      150, // Update with actual required credits
 		MaxCreditPerSemester: 75,
 		MinCreditPerSemester: 36,
+		CoreCourses:          []string{},
+		MinCGPA:              defaultMinCGPA,
 	},
 }
 
-func GetProgramMaxSemesters(programType string) (int, error) {
-	program, exists := programMapping[programType]
+// readProgram reads a program from the ledger, the returned bool is false if it does not exist
+func readProgram(ctx contractapi.TransactionContextInterface, programName string) (Program, bool, error) {
+	programJSON, err := getEntityState(ctx, programObjectType, programName)
+	if err != nil {
+		return Program{}, false, fmt.Errorf("Failed to read program %s: %v", programName, err)
+	}
+	if programJSON == nil {
+		return Program{}, false, nil
+	}
+
+	var program Program
+	err = json.Unmarshal(programJSON, &program)
+	if err != nil {
+		return Program{}, false, err
+	}
+
+	return program, true, nil
+}
+
+// missingBuiltinPrograms returns the built-in programs that are not on the ledger yet
+func missingBuiltinPrograms(ctx contractapi.TransactionContextInterface) ([]Program, error) {
+	missing := []Program{}
+	for _, program := range builtinPrograms {
+		_, programExists, err := readProgram(ctx, program.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !programExists {
+			missing = append(missing, program)
+		}
+	}
+	return missing, nil
+}
+
+func GetProgramMaxSemesters(ctx contractapi.TransactionContextInterface, programType string) (int, error) {
+	program, exists, err := readProgram(ctx, programType)
+	if err != nil {
+		return 0, err
+	}
 	if !exists {
 		return 0, fmt.Errorf("Program type %s not found", programType)
 	}
@@ -37,8 +85,11 @@ func GetProgramMaxSemesters(programType string) (int, error) {
 }
 
 // GetProgramMaxCreditsPerSemester retrieves the maximum allowed credits per semester for a program
-func (s *StudentRecordContract) GetProgramMaxCreditsPerSemester(programType string) (int, error) {
-	program, exists := programMapping[programType]
+func (s *StudentRecordContract) GetProgramMaxCreditsPerSemester(ctx contractapi.TransactionContextInterface, programType string) (int, error) {
+	program, exists, err := readProgram(ctx, programType)
+	if err != nil {
+		return 0, err
+	}
 	if !exists {
 		return 0, fmt.Errorf("Program type %s is not valid", programType)
 	}
@@ -47,8 +98,11 @@ func (s *StudentRecordContract) GetProgramMaxCreditsPerSemester(programType stri
 }
 
 // GetProgramMinCreditsPerSemester retrieves the maximum allowed credits per semester for a program
-func (s *StudentRecordContract) GetProgramMinCreditsPerSemester(programType string) (int, error) {
-	program, exists := programMapping[programType]
+func (s *StudentRecordContract) GetProgramMinCreditsPerSemester(ctx contractapi.TransactionContextInterface, programType string) (int, error) {
+	program, exists, err := readProgram(ctx, programType)
+	if err != nil {
+		return 0, err
+	}
 	if !exists {
 		return 0, fmt.Errorf("Program type %s is not valid", programType)
 	}
@@ -57,28 +111,109 @@ func (s *StudentRecordContract) GetProgramMinCreditsPerSemester(programType stri
 }
 
 // AddProgram adds a new program to the ledger
+// The program has no core courses and the default minimum CGPA until SetProgramGraduationRequirements sets them
 func (s *StudentRecordContract) AddProgram(ctx contractapi.TransactionContextInterface, programName string, maxSemesters int, requiredCredits int, maxCreditPerSemester int, minCreditPerSemester int) error {
+	_, err := s.createProgram(ctx, programName, maxSemesters, requiredCredits, maxCreditPerSemester, minCreditPerSemester)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Added new program: %s", programName)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// createProgram stores a new program, without recording a ledger update
+func (s *StudentRecordContract) createProgram(ctx contractapi.TransactionContextInterface, programName string, maxSemesters int, requiredCredits int, maxCreditPerSemester int, minCreditPerSemester int) (Program, error) {
+	newProgram, err := s.buildProgram(ctx, programName, maxSemesters, requiredCredits, maxCreditPerSemester, minCreditPerSemester)
+	if err != nil {
+		return Program{}, err
+	}
+
+	// Store the new program in the ledger
+	err = putCatalogState(ctx, programObjectType, programName, newProgram)
+	if err != nil {
+		return Program{}, err
+	}
+
+	return newProgram, nil
+}
+
+// buildProgram checks and returns a new program, without storing it
+func (s *StudentRecordContract) buildProgram(ctx contractapi.TransactionContextInterface, programName string, maxSemesters int, requiredCredits int, maxCreditPerSemester int, minCreditPerSemester int) (Program, error) {
 	// Check if the program already exists
-	_, programExists := programMapping[programName]
+	_, programExists, err := readProgram(ctx, programName)
+	if err != nil {
+		return Program{}, err
+	}
 	if programExists {
-		return fmt.Errorf("Program %s already exists", programName)
+		return Program{}, fmt.Errorf("Program %s already exists", programName)
 	}
 
 	// Create a new program
-	newProgram := Program{
+	return Program{
 		Name:                 programName,
 		MaxSemesters:         maxSemesters,
 		RequiredCredits:      requiredCredits,
 		MaxCreditPerSemester: maxCreditPerSemester,
 		MinCreditPerSemester: minCreditPerSemester,
+		CoreCourses:          []string{},
+		MinCGPA:              defaultMinCGPA,
+		SchemaVersion:        schemaVersion(programObjectType),
+	}, nil
+}
+
+// SetProgramGraduationRequirements sets the core courses and minimum CGPA a student of the program needs to graduate
+func (s *StudentRecordContract) SetProgramGraduationRequirements(ctx contractapi.TransactionContextInterface, programName string, coreCoursesJSON string, minCGPA float64) error {
+	var coreCourses []string
+	if err := json.Unmarshal([]byte(coreCoursesJSON), &coreCourses); err != nil {
+		return fmt.Errorf("unmarhsal error")
+	}
+
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can set graduation requirements")
+	}
+
+	// Check if the program exists
+	program, programExists, err := readProgram(ctx, programName)
+	if err != nil {
+		return err
+	}
+	if !programExists {
+		return fmt.Errorf("Program %s does not exist", programName)
+	}
+
+	if minCGPA < 0 || minCGPA > 10 {
+		return fmt.Errorf("Minimum CGPA %.2f is not valid", minCGPA)
+	}
+
+	// Check if all the core courses exist
+	for _, courseID := range coreCourses {
+		if _, err := s.GetCourse(ctx, courseID); err != nil {
+			return err
+		}
 	}
 
-	// Add the new program to the programs map
-	programMapping[programName] = newProgram
+	program.CoreCourses = append([]string{}, coreCourses...)
+	program.MinCGPA = minCGPA
+	program.Version++
+
+	// Store the requirements with the program in the ledger
+	err = putCatalogState(ctx, programObjectType, programName, program)
+	if err != nil {
+		return err
+	}
 
 	// Record the ledger update
-	entry := fmt.Sprintf("Added new program: %s", programName)
-	err := s.recordLedgerUpdate(ctx, entry)
+	entry := fmt.Sprintf("Updated graduation requirements for program %s: core courses %s, minimum CGPA %.2f", programName, strings.Join(coreCourses, ", "), minCGPA)
+	err = s.recordLedgerUpdate(ctx, entry)
 	if err != nil {
 		return err
 	}
@@ -89,17 +224,29 @@ func (s *StudentRecordContract) AddProgram(ctx contractapi.TransactionContextInt
 // RemoveProgram removes a program from the ledger
 func (s *StudentRecordContract) RemoveProgram(ctx contractapi.TransactionContextInterface, programName string) error {
 	// Check if the program exists
-	_, programExists := programMapping[programName]
+	_, programExists, err := readProgram(ctx, programName)
+	if err != nil {
+		return err
+	}
 	if !programExists {
 		return fmt.Errorf("Program %s does not exist", programName)
 	}
 
-	// Delete the program from the programs map
-	delete(programMapping, programName)
+	// Refuse to remove a program that other records refer to
+	err = s.ensureNoDependents(ctx, entityProgram, programName)
+	if err != nil {
+		return err
+	}
+
+	// Delete the program from the ledger
+	err = delEntityState(ctx, programObjectType, programName)
+	if err != nil {
+		return err
+	}
 
 	// Record the ledger update
 	entry := fmt.Sprintf("Removed program: %s", programName)
-	err := s.recordLedgerUpdate(ctx, entry)
+	err = s.recordLedgerUpdate(ctx, entry)
 	if err != nil {
 		return err
 	}
@@ -109,7 +256,10 @@ func (s *StudentRecordContract) RemoveProgram(ctx contractapi.TransactionContext
 
 // GetProgram retrieves program information by programName
 func (s *StudentRecordContract) GetProgram(ctx contractapi.TransactionContextInterface, programName string) (*Program, error) {
-	program, exists := programMapping[programName]
+	program, exists, err := readProgram(ctx, programName)
+	if err != nil {
+		return nil, err
+	}
 	if !exists {
 		return nil, fmt.Errorf("Program with name %s does not exist", programName)
 	}
@@ -120,8 +270,16 @@ func (s *StudentRecordContract) GetProgram(ctx contractapi.TransactionContextInt
 func (s *StudentRecordContract) GetAllPrograms(ctx contractapi.TransactionContextInterface) ([]Program, error) {
 	programs := make([]Program, 0)
 
-	for _, program := range programMapping {
+	err := forEachEntity(ctx, programObjectType, func(value []byte) error {
+		var program Program
+		if err := json.Unmarshal(value, &program); err != nil {
+			return err
+		}
 		programs = append(programs, program)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return programs, nil
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// RatingWeights configures how a program rates its students
+// Each factor is normalised to the range 0 to 1 and weighted; the targets are the values at which a factor is full
+type RatingWeights struct {
+	ProgramType         string  `json:"programType"`
+	CGPAWeight          float64 `json:"cgpaWeight"`
+	CreditsWeight       float64 `json:"creditsWeight"`       // Progress towards the credits required by the program
+	ParticipationWeight float64 `json:"participationWeight"` // Activities attended
+	HoursWeight         float64 `json:"hoursWeight"`         // Hours of extracurricular participation
+	CertificatesWeight  float64 `json:"certificatesWeight"`  // Approved certificates
+	ActivitiesTarget    int     `json:"activitiesTarget"`
+	HoursTarget         float64 `json:"hoursTarget"`
+	CertificatesTarget  int     `json:"certificatesTarget"`
+	SchemaVersion       int     `json:"schemaVersion"`
+}
+
+// RatingFactor is the contribution of one factor to a student's rating
+type RatingFactor struct {
+	Factor       string  `json:"factor"`
+	Value        float64 `json:"value"`        // Value of the factor for the student, such as the CGPA or hours
+	Target       float64 `json:"target"`       // Value at which the factor is full
+	Normalized   float64 `json:"normalized"`   // Value divided by the target, capped at 1
+	Weight       float64 `json:"weight"`       // Weight of the factor
+	Contribution float64 `json:"contribution"` // Points the factor adds to the score out of 100
+}
+
+// StudentRating is a student's score out of 100 together with the breakdown of the factors
+type StudentRating struct {
+	StudentID   string         `json:"studentID"`
+	ProgramType string         `json:"programType"`
+	Score       float64        `json:"score"`
+	Factors     []RatingFactor `json:"factors"`
+	Weights     RatingWeights  `json:"weights"`
+}
+
+// defaultRatingWeights are used for programs that have not set their own weights
+func defaultRatingWeights(programType string) RatingWeights {
+	return RatingWeights{
+		ProgramType:         programType,
+		CGPAWeight:          50,
+		CreditsWeight:       20,
+		ParticipationWeight: 10,
+		HoursWeight:         10,
+		CertificatesWeight:  10,
+		ActivitiesTarget:    5,
+		HoursTarget:         40,
+		CertificatesTarget:  3,
+		SchemaVersion:       schemaVersion(ratingWeightsObjectType),
+	}
+}
+
+// SetRatingWeights sets the rating weights and targets of a program
+func (s *StudentRecordContract) SetRatingWeights(ctx contractapi.TransactionContextInterface, programType string, weightsJSON string) error {
+	var weights RatingWeights
+	if err := json.Unmarshal([]byte(weightsJSON), &weights); err != nil {
+		return fmt.Errorf("unmarhsal error")
+	}
+
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can set rating weights")
+	}
+
+	// Check if the program exists
+	_, programExists, err := readProgram(ctx, programType)
+	if err != nil {
+		return err
+	}
+	if !programExists {
+		return fmt.Errorf("Program %s does not exist", programType)
+	}
+
+	// Validate the weights and targets
+	weights.ProgramType = programType
+	weights.SchemaVersion = schemaVersion(ratingWeightsObjectType)
+	for _, weight := range []float64{weights.CGPAWeight, weights.CreditsWeight, weights.ParticipationWeight, weights.HoursWeight, weights.CertificatesWeight} {
+		if weight < 0 {
+			return fmt.Errorf("Rating weights cannot be negative")
+		}
+	}
+	if weights.totalWeight() == 0 {
+		return fmt.Errorf("At least one rating weight must be positive")
+	}
+	if weights.ActivitiesTarget <= 0 || weights.HoursTarget <= 0 || weights.CertificatesTarget <= 0 {
+		return fmt.Errorf("Rating targets must be positive")
+	}
+
+	weightsBytes, err := json.Marshal(weights)
+	if err != nil {
+		return err
+	}
+	err = putEntityState(ctx, ratingWeightsObjectType, programType, weightsBytes)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Updated rating weights for program %s", programType)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetRatingWeights returns the rating weights of a program, or the default weights if none are set
+func (s *StudentRecordContract) GetRatingWeights(ctx contractapi.TransactionContextInterface, programType string) (*RatingWeights, error) {
+	weightsJSON, err := getEntityState(ctx, ratingWeightsObjectType, programType)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read rating weights for program %s: %v", programType, err)
+	}
+	if weightsJSON == nil {
+		weights := defaultRatingWeights(programType)
+		return &weights, nil
+	}
+
+	var weights RatingWeights
+	err = json.Unmarshal(weightsJSON, &weights)
+	if err != nil {
+		return nil, err
+	}
+
+	return &weights, nil
+}
+
+// GetStudentRating rates a student out of 100 from their CGPA, credit progress, extracurricular participation,
+// hours and approved certificates, using the rating weights of their program
+func (s *StudentRecordContract) GetStudentRating(ctx contractapi.TransactionContextInterface, studentID string) (*StudentRating, error) {
+	enrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	program, programExists, err := readProgram(ctx, enrollment.ProgramType)
+	if err != nil {
+		return nil, err
+	}
+	if !programExists {
+		return nil, fmt.Errorf("Program type %s not found", enrollment.ProgramType)
+	}
+
+	weights, err := s.GetRatingWeights(ctx, enrollment.ProgramType)
+	if err != nil {
+		return nil, err
+	}
+
+	cgpa, err := s.CalculateCGPA(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.GetStudentActivityProfile(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	creditsCompleted, err := s.completedCredits(ctx, enrollment)
+	if err != nil {
+		return nil, err
+	}
+
+	factors := []RatingFactor{
+		newRatingFactor("cgpa", cgpa, 10, weights.CGPAWeight),
+		newRatingFactor("credits", float64(creditsCompleted), float64(program.RequiredCredits), weights.CreditsWeight),
+		newRatingFactor("participation", float64(profile.AttendedCount), float64(weights.ActivitiesTarget), weights.ParticipationWeight),
+		newRatingFactor("hours", profile.TotalHours, weights.HoursTarget, weights.HoursWeight),
+		newRatingFactor("certificates", float64(profile.ApprovedCertificates), float64(weights.CertificatesTarget), weights.CertificatesWeight),
+	}
+
+	// Each factor contributes its share of the total weight
+	totalWeight := weights.totalWeight()
+	score := 0.0
+	for i := range factors {
+		factors[i].Contribution = roundRating(factors[i].Normalized * factors[i].Weight * 100 / totalWeight)
+		score += factors[i].Normalized * factors[i].Weight * 100 / totalWeight
+	}
+
+	return &StudentRating{
+		StudentID:   studentID,
+		ProgramType: enrollment.ProgramType,
+		Score:       roundRating(score),
+		Factors:     factors,
+		Weights:     *weights,
+	}, nil
+}
+
+// newRatingFactor normalises the value of a factor against its target
+func newRatingFactor(name string, value float64, target float64, weight float64) RatingFactor {
+	normalized := 0.0
+	if target > 0 {
+		normalized = value / target
+	}
+	if normalized > 1 {
+		normalized = 1
+	}
+
+	return RatingFactor{
+		Factor:     name,
+		Value:      value,
+		Target:     target,
+		Normalized: roundRating(normalized),
+		Weight:     weight,
+	}
+}
+
+// totalWeight returns the sum of the factor weights
+func (weights RatingWeights) totalWeight() float64 {
+	return weights.CGPAWeight + weights.CreditsWeight + weights.ParticipationWeight + weights.HoursWeight + weights.CertificatesWeight
+}
+
+// roundRating rounds a rating value to two digits after the floating point
+func roundRating(value float64) float64 {
+	rounded, _ := strconv.ParseFloat(fmt.Sprintf("%.2f", value), 64)
+	return rounded
+}
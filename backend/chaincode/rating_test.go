@@ -0,0 +1,72 @@
+package main
+
+import "testing"
+
+func (c *testContract) rating(studentID string) StudentRating {
+	c.t.Helper()
+	var rating StudentRating
+	c.query(&rating, "GetStudentRating", studentID)
+	return rating
+}
+
+func TestGetStudentRating(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	if rating := c.rating("S1"); rating.Score != 0 || len(rating.Factors) != 5 || rating.Weights.CGPAWeight != 50 {
+		t.Fatalf("rating of a new student is %+v", rating)
+	}
+
+	// 1 of 5 activities, 2 of 40 hours and 1 of 3 certificates, with the default weights and the normalized values rounded
+	c.attendActivity()
+	c.mustInvoke("AddCertificateForStudent", "S1", "A1", "key1", testCertificateHash, "10", "application/pdf", "")
+	if _, err := c.review("F1", certificateApproved, ""); err != nil {
+		t.Fatal(err)
+	}
+	rating := c.rating("S1")
+	if rating.Score != 5.8 {
+		t.Fatalf("rating is %+v, want a score of 5.8", rating)
+	}
+	want := map[string]float64{"cgpa": 0, "credits": 0, "participation": 2, "hours": 0.5, "certificates": 3.3}
+	for _, factor := range rating.Factors {
+		if factor.Contribution != want[factor.Factor] {
+			t.Errorf("%s contributes %.2f, want %.2f", factor.Factor, factor.Contribution, want[factor.Factor])
+		}
+	}
+
+	// Passing a course with an S adds the full CGPA and a quarter of the credits
+	c.passCourses(`["CS101"]`, `[{"courseID":"CS101","grade":"S"}]`)
+	if rating := c.rating("S1"); rating.Score != 60.8 {
+		t.Fatalf("rating after a course is %+v, want a score of 60.8", rating)
+	}
+}
+
+func TestSetRatingWeights(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.attendActivity()
+
+	c.mustFail("does not exist", "SetRatingWeights", "PHD", `{"cgpaWeight":1,"activitiesTarget":1,"hoursTarget":1,"certificatesTarget":1}`)
+	c.mustFail("cannot be negative", "SetRatingWeights", "BTECH", `{"cgpaWeight":-1,"hoursWeight":2,"activitiesTarget":1,"hoursTarget":1,"certificatesTarget":1}`)
+	c.mustFail("must be positive", "SetRatingWeights", "BTECH", `{"activitiesTarget":1,"hoursTarget":1,"certificatesTarget":1}`)
+	c.mustFail("targets must be positive", "SetRatingWeights", "BTECH", `{"cgpaWeight":1}`)
+
+	c.mustInvoke("SetRatingWeights", "BTECH", `{"hoursWeight":1,"certificatesWeight":1,"activitiesTarget":1,"hoursTarget":10,"certificatesTarget":2}`)
+	c.restart()
+	var weights RatingWeights
+	c.query(&weights, "GetRatingWeights", "BTECH")
+	if weights.ProgramType != "BTECH" || weights.CGPAWeight != 0 || weights.HoursWeight != 1 || weights.HoursTarget != 10 {
+		t.Fatalf("BTECH rating weights are %+v", weights)
+	}
+
+	// 2 of 10 hours is half the score at equal weights with certificates
+	if rating := c.rating("S1"); rating.Score != 10 {
+		t.Fatalf("rating with the BTECH weights is %+v, want a score of 10", rating)
+	}
+
+	// Other programs keep the default weights
+	c.query(&weights, "GetRatingWeights", "MTECH")
+	if weights != defaultRatingWeights("MTECH") {
+		t.Fatalf("MTECH rating weights are %+v, want the defaults", weights)
+	}
+}
@@ -12,10 +12,10 @@ import (
 type Result struct {
 	CourseID string `json:"courseID"`
 	Grade    string `json:"grade"`
+	TxID     string `json:"txID"`                                // ID of the transaction that recorded the grade
+	Salt     string `json:"salt,omitempty" metadata:",optional"` // Random salt stored with a private grade, so that its hash cannot be matched by guessing
 }
 
-var sgpaMapping = make(map[string]map[string]float64)
-
 // AddResultForCurrentSemester allows a faculty member to add results for courses in the current semester
 func (s *StudentRecordContract) AddResultForCurrentSemester(ctx contractapi.TransactionContextInterface, studentID string, resultsToAddjson string) error {
 
@@ -24,6 +24,12 @@ func (s *StudentRecordContract) AddResultForCurrentSemester(ctx contractapi.Tran
 		return fmt.Errorf("unmarhsal error")
 	}
 
+	return s.addResultsForCurrentSemester(ctx, studentID, resultsToAdd, false)
+}
+
+// addResultsForCurrentSemester validates and adds results for the current semester
+// With private set, the grades go to the grades collection and the public enrollment keeps only the course and transaction
+func (s *StudentRecordContract) addResultsForCurrentSemester(ctx contractapi.TransactionContextInterface, studentID string, resultsToAdd []Result, private bool) error {
 	// Check if the caller is authorized (admin or faculty)
 	caller := ctx.GetClientIdentity()
 	if !s.isAdmin(ctx, caller) && !s.isFaculty(ctx, caller) {
@@ -36,6 +42,16 @@ func (s *StudentRecordContract) AddResultForCurrentSemester(ctx contractapi.Tran
 		return err
 	}
 
+	// Check if the student is active
+	if err := ensureEnrollmentActive(existingEnrollment); err != nil {
+		return err
+	}
+
+	// Check that the results are submitted the way the student's results are kept
+	if err := ensureResultPrivacy(existingEnrollment, private); err != nil {
+		return err
+	}
+
 	// Check if the current semester exists in the enrollment
 	currentSemester := existingEnrollment.CurrentSemester
 	if currentSemester == "" {
@@ -60,8 +76,14 @@ func (s *StudentRecordContract) AddResultForCurrentSemester(ctx contractapi.Tran
 		}
 	}
 
+	// Read the results to derive the credits from, private grades written by this transaction cannot be read back
+	// For public results this is the map of the enrollment itself, so the new results are added to it below
+	allSemesterResults, err := s.getSemesterResults(ctx, existingEnrollment)
+	if err != nil {
+		return err
+	}
+
 	// Validate and add results for courses in the current semester
-	totalCredits := 0
 	for _, result := range resultsToAdd {
 		courseID := result.CourseID
 
@@ -87,36 +109,36 @@ func (s *StudentRecordContract) AddResultForCurrentSemester(ctx contractapi.Tran
 		}
 
 		// Add the result to the current semester
-		existingEnrollment.SemesterResults[currentSemester] = append(existingEnrollment.SemesterResults[currentSemester], result)
-
-		// Fetch the credits of the course
-		course, err := s.GetCourse(ctx, courseID)
-		if err != nil {
-			return fmt.Errorf("Error fetching course %s: %s", courseID, err.Error())
-		}
-
-		// // Accumulate the credits
-		// totalCredits += course.Credits
-		// Accumulate the credits only if the grade is not "F"
-		if result.Grade != "F" {
-			totalCredits += course.Credits
+		result.TxID = ctx.GetStub().GetTxID()
+		publicResult := result
+		if private {
+			if err := putPrivateResult(ctx, studentID, result); err != nil {
+				return err
+			}
+			publicResult.Grade = ""
+			allSemesterResults[currentSemester] = append(allSemesterResults[currentSemester], result)
 		}
+		existingEnrollment.SemesterResults[currentSemester] = append(existingEnrollment.SemesterResults[currentSemester], publicResult)
 	}
 
-	// Update the creditsCompleted with the total credits accumulated
-	existingEnrollment.CreditsCompleted += totalCredits
+	// Derive the completed credits from the results
+	err = s.updateCredits(ctx, &existingEnrollment, allSemesterResults)
+	if err != nil {
+		return err
+	}
 
-	// Update the enrollment mapping
-	enrollmentMapping[studentID] = existingEnrollment
 	// Update the enrollment in the ledger
 	enrollmentJSON, _ := json.Marshal(existingEnrollment)
-	err = ctx.GetStub().PutState(fmt.Sprintf("ENROLLMENT-%s", studentID), enrollmentJSON)
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
 	if err != nil {
 		return err
 	}
 
 	// Record the ledger update
 	entry := fmt.Sprintf("Added results for current semester for student %s", studentID)
+	if private {
+		entry = fmt.Sprintf("Added private results for current semester for student %s", studentID)
+	}
 	err = s.recordLedgerUpdate(ctx, entry)
 	if err != nil {
 		return err
@@ -127,19 +149,35 @@ func (s *StudentRecordContract) AddResultForCurrentSemester(ctx contractapi.Tran
 
 // UpdateGradeForCourse updates the grade for a specific course in the current semester,
 func (s *StudentRecordContract) UpdateGradeForCourse(ctx contractapi.TransactionContextInterface, studentID string, courseID string, newGrade string) error {
+	return s.updateGradeForCourse(ctx, studentID, courseID, newGrade, false)
+}
+
+// updateGradeForCourse updates the grade of a course, in the grades collection if private is set
+func (s *StudentRecordContract) updateGradeForCourse(ctx contractapi.TransactionContextInterface, studentID string, courseID string, newGrade string, private bool) error {
 	// Get the student's enrollment
 	existingEnrollment, err := s.GetEnrollment(ctx, studentID)
 	if err != nil {
 		return err
 	}
 
+	// Check if the student is active
+	if err := ensureEnrollmentActive(existingEnrollment); err != nil {
+		return err
+	}
+
+	// Check that the grade is submitted the way the student's results are kept
+	if err := ensureResultPrivacy(existingEnrollment, private); err != nil {
+		return err
+	}
+
 	// Check if the current semester exists
 	currentSemester := existingEnrollment.CurrentSemester
 	if currentSemester == "" {
 		return fmt.Errorf("Current semester not found for student %s", studentID)
 	}
 
-	// Check if the course has been taken in any previous semester and record the semester number
+	// Check if the course has been taken in any semester and record the semester number
+	// Every semester is searched, so that a failed course of an earlier semester can be re-graded after re-examination
 	courseTakenSemester := ""
 	for semester, semesterResults := range existingEnrollment.SemesterResults {
 
@@ -151,11 +189,7 @@ func (s *StudentRecordContract) UpdateGradeForCourse(ctx contractapi.Transaction
 		}
 
 		if courseTakenSemester != "" {
-			break // Stop checking if the course is found in any previous semester
-		}
-
-		if semester == currentSemester {
-			break // Stop checking once we reach the current semester
+			break // Stop checking if the course is found in any semester
 		}
 	}
 
@@ -163,27 +197,52 @@ func (s *StudentRecordContract) UpdateGradeForCourse(ctx contractapi.Transaction
 		return fmt.Errorf("Course %s has not been taken in any previous semester", courseID)
 	}
 
+	// Read the results to derive the credits from, private grades written by this transaction cannot be read back
+	allSemesterResults, err := s.getSemesterResults(ctx, existingEnrollment)
+	if err != nil {
+		return err
+	}
+
 	// Update the grade for the specified course in the corresponding semester
 	for index, result := range existingEnrollment.SemesterResults[courseTakenSemester] {
 		if result.CourseID == courseID {
-			existingEnrollment.SemesterResults[courseTakenSemester][index].Grade = newGrade
+			if private {
+				err = putPrivateResult(ctx, studentID, Result{CourseID: courseID, Grade: newGrade, TxID: ctx.GetStub().GetTxID()})
+				if err != nil {
+					return err
+				}
+			} else {
+				existingEnrollment.SemesterResults[courseTakenSemester][index].Grade = newGrade
+			}
+			existingEnrollment.SemesterResults[courseTakenSemester][index].TxID = ctx.GetStub().GetTxID()
+			allSemesterResults[courseTakenSemester][index].Grade = newGrade
 			break
 		}
 	}
 
-	// Update the enrollment mapping
-	enrollmentMapping[studentID] = existingEnrollment
+	// A grade moving to or from a fail changes the completed credits
+	err = s.updateCredits(ctx, &existingEnrollment, allSemesterResults)
+	if err != nil {
+		return err
+	}
+
 	// Update the enrollment in the ledger
 	enrollmentJSON, err := json.Marshal(existingEnrollment)
 	if err != nil {
 		return err
 	}
 
-	err = ctx.GetStub().PutState(fmt.Sprintf("ENROLLMENT-%s", studentID), enrollmentJSON)
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
 	if err != nil {
 		return err
 	}
 
+	// Private grades are left out of the ledger update
+	if private {
+		entry := fmt.Sprintf("Updated private grade for course %s for student %s", courseID, studentID)
+		return s.recordLedgerUpdate(ctx, entry)
+	}
+
 	//it needs to be called after some time, otherwise might not really update the sgpa as the ledger state might not be updated by then
 	//if grade is getting updated, recalculate sgpa and cgpa
 	_, err1 := s.CalculateSGPA(ctx, studentID, courseTakenSemester)
@@ -214,8 +273,14 @@ func (s *StudentRecordContract) CalculateSGPA(ctx contractapi.TransactionContext
 		return 0, err
 	}
 
+	// Read the results, including private grades
+	semesterResults, err := s.getSemesterResults(ctx, existingEnrollment)
+	if err != nil {
+		return 0, err
+	}
+
 	// Check if the semester exists in the enrollment
-	_, semesterExists := existingEnrollment.SemesterResults[semester]
+	_, semesterExists := semesterResults[semester]
 	if !semesterExists {
 		return 0, fmt.Errorf("Result of %s does not exist in the enrollment for student %s", semester, studentID)
 	}
@@ -247,7 +312,7 @@ func (s *StudentRecordContract) CalculateSGPA(ctx contractapi.TransactionContext
 	}
 
 	// Calculate SGPA for the semester
-	for _, result := range existingEnrollment.SemesterResults[semester] {
+	for _, result := range semesterResults[semester] {
 		// Get the course for the result
 		course, err := s.GetCourse(ctx, result.CourseID)
 		if err != nil {
@@ -277,15 +342,6 @@ func (s *StudentRecordContract) CalculateSGPA(ctx contractapi.TransactionContext
 	// Parse the formatted SGPA back to a float64
 	parsedSGPA, _ := strconv.ParseFloat(formattedSGPA, 64)
 
-	// Store the SGPA in the mapping
-	if sgpaMapping == nil {
-		sgpaMapping = make(map[string]map[string]float64)
-	}
-	if sgpaMapping[studentID] == nil {
-		sgpaMapping[studentID] = make(map[string]float64)
-	}
-	sgpaMapping[studentID][semester] = parsedSGPA
-
 	return parsedSGPA, nil
 }
 
@@ -298,11 +354,6 @@ func (s *StudentRecordContract) CalculateCGPA(ctx contractapi.TransactionContext
 		return 0, err
 	}
 
-	// Calculate CGPA based on SGPA values stored in the mapping
-	if sgpaMapping == nil {
-		return 0, fmt.Errorf("SGPA data not found for student %s", studentID)
-	}
-
 	totalSemesters := 0
 
 	// Iterate through all the semesters
@@ -337,8 +388,14 @@ func (s *StudentRecordContract) CalculateCGPA(ctx contractapi.TransactionContext
 		return 0.0, fmt.Errorf("Current semester not found for student %s", studentID)
 	}
 
-	// Iterate through previous semester and record the totalGradePoints and totalCredits
-	for semester, semesterResults := range existingEnrollment.SemesterResults {
+	// Read the results, including private grades
+	allSemesterResults, err := s.getSemesterResults(ctx, existingEnrollment)
+	if err != nil {
+		return 0, err
+	}
+
+	// Iterate through all semesters with results and record the totalGradePoints and totalCredits
+	for _, semesterResults := range allSemesterResults {
 
 		for _, result := range semesterResults {
 			// Get the course for the result
@@ -359,18 +416,13 @@ func (s *StudentRecordContract) CalculateCGPA(ctx contractapi.TransactionContext
 		}
 
 		totalSemesters++
-
-		if semester == currentSemester {
-			break // Stop checking once we reach the current semester
-		}
 	}
 
-	cgpa := totalGradePoints / float64(totalCredits)
-
 	// Calculate CGPA
-	if totalSemesters == 0 {
+	if totalSemesters == 0 || totalCredits == 0 {
 		return 0, nil // Avoid division by zero
 	}
+	cgpa := totalGradePoints / float64(totalCredits)
 
 	// Format the CGPA with two digits after the floating point
 	formattedCGPA := fmt.Sprintf("%.2f", cgpa)
@@ -381,13 +433,24 @@ func (s *StudentRecordContract) CalculateCGPA(ctx contractapi.TransactionContext
 	return parsedCGPA, nil
 }
 
-// QuerySGPAMapping retrieves the SGPA mapping for a student
+// GetSGPA returns the SGPA of every semester with results for a student, calculated from the results on the ledger
 func (s *StudentRecordContract) GetSGPA(ctx contractapi.TransactionContextInterface, studentID string) (map[string]float64, error) {
-	sgpaMapping, exists := sgpaMapping[studentID]
-	if !exists {
+	existingEnrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existingEnrollment.SemesterResults) == 0 {
 		return nil, fmt.Errorf("SGPA data not found for student %s", studentID)
 	}
-	return sgpaMapping, nil
+
+	sgpas := make(map[string]float64)
+	for semester := range existingEnrollment.SemesterResults {
+		sgpas[semester], err = s.CalculateSGPA(ctx, studentID, semester)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sgpas, nil
 }
 
 // GetResultForCourse retrieves the result (grade) for a specific course in all semesters up to the current semester for a student
@@ -398,10 +461,16 @@ func (s *StudentRecordContract) GetResultForCourse(ctx contractapi.TransactionCo
 		return nil, err
 	}
 
+	// Read the results, including private grades
+	allSemesterResults, err := s.getSemesterResults(ctx, enrollment)
+	if err != nil {
+		return nil, err
+	}
+
 	results := make(map[string]string)
 
 	// Iterate through all semesters in the enrollment record
-	for semester, semesterResults := range enrollment.SemesterResults {
+	for semester, semesterResults := range allSemesterResults {
 		// Check if the course result exists for the specified course in the current semester
 		for _, result := range semesterResults {
 			if result.CourseID == courseID {
@@ -422,13 +491,19 @@ func (s *StudentRecordContract) GetResultForSemester(ctx contractapi.Transaction
 		return nil, err
 	}
 
+	// Read the results, including private grades
+	semesterResults, err := s.getSemesterResults(ctx, enrollment)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if the specified semester exists in the enrollment record
-	_, semesterExists := enrollment.SemesterResults[semester]
+	_, semesterExists := semesterResults[semester]
 	if !semesterExists {
 		return nil, fmt.Errorf("semester %s does not exist for student %s", semester, studentID)
 	}
 
-	return enrollment.SemesterResults[semester], nil
+	return semesterResults[semester], nil
 }
 
 // GetResultsForAllSemesters retrieves the results (grades) for all courses taken by a student in all semesters up to the current semester
@@ -439,5 +514,5 @@ func (s *StudentRecordContract) GetResultsForAllSemesters(ctx contractapi.Transa
 		return nil, err
 	}
 
-	return enrollment.SemesterResults, nil
+	return s.getSemesterResults(ctx, enrollment)
 }
@@ -0,0 +1,20 @@
+package main
+
+import "testing"
+
+func TestResultsRecordTheirTransaction(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddCoursesToCurrentSemester", "S1", `["CS101"]`)
+	c.mustInvoke("AddResultForCurrentSemester", "S1", `[{"courseID":"CS101","grade":"F"}]`)
+	added := c.enrollment("S1").SemesterResults["Semester1"][0]
+	if added.TxID == "" {
+		t.Fatalf("result %+v does not record the transaction that added it", added)
+	}
+
+	c.mustInvoke("UpdateGradeForCourse", "S1", "CS101", "B")
+	updated := c.enrollment("S1").SemesterResults["Semester1"][0]
+	if updated.Grade != "B" || updated.TxID == "" || updated.TxID == added.TxID {
+		t.Fatalf("updated result is %+v, want grade B recorded by a transaction other than %s", updated, added.TxID)
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// RosterEntry is a student on the roster of an extracurricular activity
+// Entries are stored under the composite key ROSTER~activityID~studentID so that the roster of an activity is read
+// with a single partial key query instead of scanning every enrollment
+type RosterEntry struct {
+	ActivityID   string `json:"activityID"`
+	StudentID    string `json:"studentID"`
+	StudentName  string `json:"studentName"`
+	Status       string `json:"status"` // Participation status of the student
+	RegisteredAt string `json:"registeredAt"`
+}
+
+// rosterObjectType is the object type of the composite keys of roster entries
+const rosterObjectType = "ROSTER"
+
+// RosterRebuild reports the result of rebuilding the activity rosters from the enrollments
+type RosterRebuild struct {
+	Activities   int            `json:"activities"`
+	Entries      int            `json:"entries"`
+	Participants map[string]int `json:"participants"` // Map of activity ID to the rebuilt participant count
+}
+
+// RebuildActivityRosters rebuilds the rosters and participant counts of all activities from the enrollments in the ledger
+// Used once to index the registrations made before rosters were kept, and to repair the counts
+func (s *StudentRecordContract) RebuildActivityRosters(ctx contractapi.TransactionContextInterface) (*RosterRebuild, error) {
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return nil, fmt.Errorf("Unauthorized: only admin can rebuild activity rosters")
+	}
+
+	// Remove the existing roster entries
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(rosterObjectType, []string{})
+	if err != nil {
+		return nil, err
+	}
+	var staleKeys []string
+	for iterator.HasNext() {
+		entry, err := iterator.Next()
+		if err != nil {
+			iterator.Close()
+			return nil, err
+		}
+		staleKeys = append(staleKeys, entry.Key)
+	}
+	iterator.Close()
+	for _, key := range staleKeys {
+		if err := ctx.GetStub().DelState(key); err != nil {
+			return nil, err
+		}
+	}
+
+	// Read every activity from the ledger
+	activities := make(map[string]ExtracurricularActivity)
+	activityIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(activityObjectType, []string{})
+	if err != nil {
+		return nil, err
+	}
+	for activityIterator.HasNext() {
+		result, err := activityIterator.Next()
+		if err != nil {
+			activityIterator.Close()
+			return nil, err
+		}
+		value, err := upgradeDocument(activityObjectType, result.Value)
+		if err != nil {
+			activityIterator.Close()
+			return nil, err
+		}
+		var activity ExtracurricularActivity
+		if err := json.Unmarshal(value, &activity); err != nil {
+			activityIterator.Close()
+			return nil, err
+		}
+		activity.ParticipantCount = 0
+		activities[activity.ActivityID] = activity
+	}
+	activityIterator.Close()
+
+	rebuild := RosterRebuild{Activities: len(activities), Participants: make(map[string]int)}
+
+	// Add every registration of every enrollment in the ledger to the roster of its activity
+	enrollmentIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(enrollmentObjectType, []string{})
+	if err != nil {
+		return nil, err
+	}
+	defer enrollmentIterator.Close()
+	for enrollmentIterator.HasNext() {
+		result, err := enrollmentIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		value, err := upgradeDocument(enrollmentObjectType, result.Value)
+		if err != nil {
+			return nil, err
+		}
+		var enrollment Enrollment
+		if err := json.Unmarshal(value, &enrollment); err != nil {
+			return nil, err
+		}
+
+		for _, activityID := range enrollment.Extracurricular {
+			activity, exists := activities[activityID]
+			if !exists {
+				continue
+			}
+			participation, _ := participationFor(enrollment, activityID)
+			if _, err := putRosterEntry(ctx, enrollment, participation); err != nil {
+				return nil, err
+			}
+			rebuild.Entries++
+			if participation.Status != participationWithdrawn {
+				activity.ParticipantCount++
+				activities[activityID] = activity
+			}
+		}
+	}
+
+	// Store the rebuilt counts in a fixed order
+	activityIDs := make([]string, 0, len(activities))
+	for activityID := range activities {
+		activityIDs = append(activityIDs, activityID)
+	}
+	sort.Strings(activityIDs)
+	for _, activityID := range activityIDs {
+		if err := s.putExtracurricularActivity(ctx, activities[activityID]); err != nil {
+			return nil, err
+		}
+		rebuild.Participants[activityID] = activities[activityID].ParticipantCount
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Rebuilt rosters of %d extracurricular activities with %d entries", rebuild.Activities, rebuild.Entries)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rebuild, nil
+}
+
+// GetActivityRoster returns the roster of an activity, including students who withdrew
+func (s *StudentRecordContract) GetActivityRoster(ctx contractapi.TransactionContextInterface, activityID string) ([]RosterEntry, error) {
+	_, err := s.GetExtracurricularActivity(ctx, activityID)
+	if err != nil {
+		return nil, err
+	}
+
+	return getActivityRoster(ctx, activityID)
+}
+
+// getActivityRoster reads the roster entries of an activity, ordered by student ID
+func getActivityRoster(ctx contractapi.TransactionContextInterface, activityID string) ([]RosterEntry, error) {
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(rosterObjectType, []string{activityID})
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	roster := make([]RosterEntry, 0)
+	for iterator.HasNext() {
+		result, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		var entry RosterEntry
+		if err := json.Unmarshal(result.Value, &entry); err != nil {
+			return nil, err
+		}
+		roster = append(roster, entry)
+	}
+
+	return roster, nil
+}
+
+// putRosterEntry stores a student's participation on the roster of the activity
+// Returns the change in the number of participants, which counts every student who has not withdrawn
+func putRosterEntry(ctx contractapi.TransactionContextInterface, enrollment Enrollment, participation Participation) (int, error) {
+	key, err := ctx.GetStub().CreateCompositeKey(rosterObjectType, []string{participation.ActivityID, enrollment.StudentID})
+	if err != nil {
+		return 0, err
+	}
+
+	wasParticipant := false
+	existingJSON, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return 0, err
+	}
+	if existingJSON != nil {
+		var existing RosterEntry
+		if err := json.Unmarshal(existingJSON, &existing); err != nil {
+			return 0, err
+		}
+		wasParticipant = existing.Status != participationWithdrawn
+	}
+
+	entry := RosterEntry{
+		ActivityID:   participation.ActivityID,
+		StudentID:    enrollment.StudentID,
+		StudentName:  enrollment.Name,
+		Status:       participation.Status,
+		RegisteredAt: participation.RegisteredAt,
+	}
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		return 0, err
+	}
+	if err := ctx.GetStub().PutState(key, entryJSON); err != nil {
+		return 0, err
+	}
+
+	isParticipant := participation.Status != participationWithdrawn
+	switch {
+	case isParticipant && !wasParticipant:
+		return 1, nil
+	case !isParticipant && wasParticipant:
+		return -1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// deleteActivityRoster removes all the roster entries of an activity
+func deleteActivityRoster(ctx contractapi.TransactionContextInterface, activityID string) error {
+	roster, err := getActivityRoster(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	for _, entry := range roster {
+		key, err := ctx.GetStub().CreateCompositeKey(rosterObjectType, []string{activityID, entry.StudentID})
+		if err != nil {
+			return err
+		}
+		if err := ctx.GetStub().DelState(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rosterStudentIDs returns the IDs of the students on a roster with one of the given statuses
+func rosterStudentIDs(roster []RosterEntry, statuses ...string) []string {
+	studentIDs := make([]string, 0, len(roster))
+	for _, entry := range roster {
+		if contains(statuses, entry.Status) {
+			studentIDs = append(studentIDs, entry.StudentID)
+		}
+	}
+	return studentIDs
+}
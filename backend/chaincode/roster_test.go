@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestActivityCapacity(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("InitialEnrollment", "S2", "Ravi", "BTECH", "CSE")
+	c.mustInvoke("InitialEnrollment", "S3", "Meera", "BTECH", "CSE")
+	c.mustInvoke("AddExtracurricularActivity", "A1", "Chess", "Inter-hostel chess", "Hall 1", "01012030", "2", "F1")
+
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S1", "A1")
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S2", "A1")
+	c.mustFail("Maximum count reached", "AddExtracurricularActivityForStudent", "S3", "A1")
+
+	// A withdrawal frees a place
+	c.mustInvoke("WithdrawFromExtracurricularActivity", "S1", "A1")
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S3", "A1")
+	c.mustFail("Maximum count reached", "AddExtracurricularActivityForStudent", "S1", "A1")
+
+	// So does a student marked withdrawn by the faculty
+	c.at("02012030")
+	c.as(map[string]string{"facultyID": "F1"})
+	c.mustInvoke("MarkActivityAttendance", "A1", `[{"studentID":"S2","status":"withdrawn"}]`)
+	c.as(nil)
+	c.restart()
+	if activity := c.activity("A1"); activity.ParticipantCount != 1 {
+		t.Fatalf("participant count is %d, want 1", activity.ParticipantCount)
+	}
+
+	// The roster keeps the students who withdrew, ordered by student ID
+	var roster []RosterEntry
+	c.query(&roster, "GetActivityRoster", "A1")
+	var entries []string
+	for _, entry := range roster {
+		entries = append(entries, entry.StudentID+":"+entry.Status)
+	}
+	if strings.Join(entries, ",") != "S1:withdrawn,S2:withdrawn,S3:registered" {
+		t.Fatalf("roster is %v", entries)
+	}
+	var studentIDs []string
+	c.query(&studentIDs, "GetStudentsByActivityIDInExtracurricular", "A1")
+	if strings.Join(studentIDs, ",") != "S3" {
+		t.Fatalf("students of the activity are %v, want S3", studentIDs)
+	}
+	c.mustFail("does not exist", "GetActivityRoster", "A9")
+}
+
+func TestRebuildActivityRosters(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("InitialEnrollment", "S2", "Ravi", "BTECH", "CSE")
+	c.mustInvoke("AddExtracurricularActivity", "A1", "Chess", "Inter-hostel chess", "Hall 1", "01012030", "5", "F1")
+	c.mustInvoke("AddExtracurricularActivity", "A2", "Debate", "Debating society", "Hall 2", "01012030", "5", "F1")
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S1", "A1")
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S2", "A1")
+	c.mustInvoke("WithdrawFromExtracurricularActivity", "S2", "A1")
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S2", "A2")
+
+	// Lose the rosters, as in a ledger written before rosters were kept
+	c.stub.MockTransactionStart("lose-rosters")
+	for _, activityID := range []string{"A1", "A2"} {
+		if err := deleteActivityRoster(c.context(), activityID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.stub.MockTransactionEnd("lose-rosters")
+	var roster []RosterEntry
+	c.query(&roster, "GetActivityRoster", "A1")
+	if len(roster) != 0 {
+		t.Fatalf("roster after losing it is %+v", roster)
+	}
+
+	var rebuild RosterRebuild
+	c.query(&rebuild, "RebuildActivityRosters")
+	if rebuild.Activities != 2 || rebuild.Entries != 3 || rebuild.Participants["A1"] != 1 || rebuild.Participants["A2"] != 1 {
+		t.Fatalf("rebuild is %+v", rebuild)
+	}
+	c.query(&roster, "GetActivityRoster", "A1")
+	if len(roster) != 2 || roster[0].StudentID != "S1" || roster[1].Status != participationWithdrawn {
+		t.Fatalf("rebuilt roster is %+v", roster)
+	}
+
+	// Rebuilding again gives the same rosters
+	c.query(&rebuild, "RebuildActivityRosters")
+	if rebuild.Entries != 3 || c.activity("A1").ParticipantCount != 1 {
+		t.Fatalf("second rebuild is %+v", rebuild)
+	}
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// documentUpgrader upgrades a stored document from one schema version to the next, in place
+type documentUpgrader func(document map[string]interface{})
+
+// schemaUpgraders holds the upgraders of the documents of each object type, upgraders[v] upgrades version v to v+1
+// The current schema version of an object type is the number of its upgraders. Documents written before schema
+// versions were recorded have version 0. Object types without upgraders, such as roster entries that are rebuilt
+// from the enrollments, are not versioned
+var schemaUpgraders = map[string][]documentUpgrader{
+	studentObjectType:          {stampSchemaVersion},
+	enrollmentObjectType:       {upgradeEnrollmentV0, upgradeEnrollmentV1},
+	departmentObjectType:       {stampSchemaVersion},
+	facultyObjectType:          {upgradeFacultyV0},
+	courseObjectType:           {upgradeCourseV0},
+	programObjectType:          {upgradeProgramV0},
+	activityObjectType:         {upgradeActivityV0},
+	clubObjectType:             {upgradeClubV0},
+	curriculumObjectType:       {stampSchemaVersion},
+	ratingWeightsObjectType:    {stampSchemaVersion},
+	verificationObjectType:     {stampSchemaVersion},
+	credentialStatusObjectType: {stampSchemaVersion},
+	statusListObjectType:       {stampSchemaVersion},
+	ledgerUpdateObjectType:     {stampSchemaVersion},
+}
+
+// unlimitedSeats is the seat limit of courses recorded before courses had one
+const unlimitedSeats = math.MaxInt32
+
+// defaultSchemaBatchSize is the number of documents MigrateSchema upgrades when no batch size is given
+const defaultSchemaBatchSize = 100
+
+// SchemaStatus reports the schema version of the documents of an object type
+type SchemaStatus struct {
+	ObjectType     string `json:"objectType"`
+	CurrentVersion int    `json:"currentVersion"`
+	Documents      int    `json:"documents"`
+	Outdated       int    `json:"outdated"` // Documents below the current version, upgraded when read
+}
+
+// SchemaMigration reports the result of a batch of MigrateSchema
+type SchemaMigration struct {
+	Upgraded  map[string]int `json:"upgraded"`  // Map of object type to the number of documents upgraded in this batch
+	Remaining int            `json:"remaining"` // Outdated documents left for the next batches
+}
+
+// schemaVersion returns the schema version documents of the object type are written with
+func schemaVersion(objectType string) int {
+	return len(schemaUpgraders[objectType])
+}
+
+// GetSchemaStatus returns, for every versioned object type, the current schema version and the number of outdated documents
+func (s *StudentRecordContract) GetSchemaStatus(ctx contractapi.TransactionContextInterface) ([]SchemaStatus, error) {
+	statuses := make([]SchemaStatus, 0, len(schemaUpgraders))
+	for _, objectType := range versionedObjectTypes() {
+		status := SchemaStatus{ObjectType: objectType, CurrentVersion: schemaVersion(objectType)}
+		err := forEachStoredDocument(ctx, objectType, func(key string, value []byte, version int) error {
+			status.Documents++
+			if version < status.CurrentVersion {
+				status.Outdated++
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// MigrateSchema rewrites up to batchSize outdated documents in the current schema version
+// Documents are upgraded when read either way; the migration stores the upgrades so that old versions and their
+// upgraders can eventually be retired. Invoke it again until no documents remain
+func (s *StudentRecordContract) MigrateSchema(ctx contractapi.TransactionContextInterface, batchSize int) (*SchemaMigration, error) {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return nil, fmt.Errorf("Unauthorized: only admin can migrate the schema")
+	}
+
+	if batchSize < 0 {
+		return nil, fmt.Errorf("Batch size %d is not valid", batchSize)
+	}
+	if batchSize == 0 {
+		batchSize = defaultSchemaBatchSize
+	}
+
+	migration := SchemaMigration{Upgraded: make(map[string]int)}
+	upgraded := 0
+	for _, objectType := range versionedObjectTypes() {
+		currentVersion := schemaVersion(objectType)
+		err := forEachStoredDocument(ctx, objectType, func(key string, value []byte, version int) error {
+			if version == currentVersion {
+				return nil
+			}
+			if upgraded == batchSize {
+				migration.Remaining++
+				return nil
+			}
+
+			upgradedValue, err := upgradeDocument(objectType, value)
+			if err != nil {
+				return err
+			}
+			if err := ctx.GetStub().PutState(key, upgradedValue); err != nil {
+				return err
+			}
+			migration.Upgraded[objectType]++
+			upgraded++
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Migrated %d documents to the current schema, %d remaining", upgraded, migration.Remaining)
+	err := s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &migration, nil
+}
+
+// upgradeDocument upgrades a stored document of the object type to the current schema version
+// Documents already in the current version, and documents of object types that are not versioned, are returned as they are
+func upgradeDocument(objectType string, value []byte) ([]byte, error) {
+	upgraders, versioned := schemaUpgraders[objectType]
+	if !versioned || value == nil {
+		return value, nil
+	}
+
+	document, version, err := decodeDocument(objectType, value)
+	if err != nil {
+		return nil, err
+	}
+	if version == len(upgraders) {
+		return value, nil
+	}
+
+	for ; version < len(upgraders); version++ {
+		upgraders[version](document)
+	}
+	document["schemaVersion"] = len(upgraders)
+
+	return json.Marshal(document)
+}
+
+// decodeDocument decodes a stored document and returns its schema version
+// Documents written by a newer version of the chaincode are refused rather than read with missing fields
+func decodeDocument(objectType string, value []byte) (map[string]interface{}, int, error) {
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	var document map[string]interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, 0, fmt.Errorf("unmarhsal error")
+	}
+
+	version := 0
+	if number, exists := document["schemaVersion"].(json.Number); exists {
+		parsed, err := number.Int64()
+		if err != nil {
+			return nil, 0, fmt.Errorf("Invalid schema version %s in %s document", number, strings.ToLower(objectType))
+		}
+		version = int(parsed)
+	}
+	if version > schemaVersion(objectType) {
+		return nil, 0, fmt.Errorf("The %s document has schema version %d, newer than version %d of this chaincode", strings.ToLower(objectType), version, schemaVersion(objectType))
+	}
+
+	return document, version, nil
+}
+
+// forEachStoredDocument calls fn with the key, stored value and schema version of every document of the object type,
+// without upgrading it
+func forEachStoredDocument(ctx contractapi.TransactionContextInterface, objectType string, fn func(key string, value []byte, version int) error) error {
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(objectType, []string{})
+	if err != nil {
+		return err
+	}
+	defer iterator.Close()
+
+	for iterator.HasNext() {
+		result, err := iterator.Next()
+		if err != nil {
+			return err
+		}
+		_, version, err := decodeDocument(objectType, result.Value)
+		if err != nil {
+			return err
+		}
+		if err := fn(result.Key, result.Value, version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// versionedObjectTypes returns the object types with a schema version, sorted
+func versionedObjectTypes() []string {
+	objectTypes := make([]string, 0, len(schemaUpgraders))
+	for objectType := range schemaUpgraders {
+		objectTypes = append(objectTypes, objectType)
+	}
+	sort.Strings(objectTypes)
+	return objectTypes
+}
+
+// setDefault sets a field of a document that is missing or null
+func setDefault(document map[string]interface{}, field string, value interface{}) {
+	if current, exists := document[field]; !exists || current == nil {
+		document[field] = value
+	}
+}
+
+// stampSchemaVersion upgrades a document whose fields did not change, only its schema version is recorded
+func stampSchemaVersion(document map[string]interface{}) {}
+
+// upgradeEnrollmentV0 fills in the participation, lifecycle, transfer and course plan fields added to enrollments
+func upgradeEnrollmentV0(document map[string]interface{}) {
+	setDefault(document, "semesterResults", map[string]interface{}{})
+	setDefault(document, "coursesTaken", map[string]interface{}{})
+	setDefault(document, "extracurricular", []interface{}{})
+	setDefault(document, "certificates", []interface{}{})
+	setDefault(document, "participation", map[string]interface{}{})
+	setDefault(document, "statusHistory", []interface{}{})
+	setDefault(document, "transfers", []interface{}{})
+	setDefault(document, "coursePlans", []interface{}{})
+
+	// Enrollments recorded before the lifecycle was tracked are active, or graduated once frozen
+	if status, _ := document["status"].(string); status == "" {
+		document["status"] = studentActive
+		if frozen, _ := document["frozen"].(bool); frozen {
+			document["status"] = studentGraduated
+		}
+	}
+}
+
+// upgradeEnrollmentV1 marks the certificates recorded before the review workflow, which have no status, as approved
+// Version 1 enrollments may still hold them, as the version 0 upgrade left certificates as they were
+func upgradeEnrollmentV1(document map[string]interface{}) {
+	certificates, _ := document["certificates"].([]interface{})
+	for _, value := range certificates {
+		certificate, isObject := value.(map[string]interface{})
+		if !isObject {
+			continue
+		}
+		if status, _ := certificate["status"].(string); status == "" {
+			certificate["status"] = certificateApproved
+		}
+	}
+}
+
+// upgradeFacultyV0 fills in the designations and joint appointments added to faculty
+func upgradeFacultyV0(document map[string]interface{}) {
+	setDefault(document, "designations", []interface{}{})
+	setDefault(document, "jointDepartments", []interface{}{})
+}
+
+// upgradeCourseV0 fills in the seats and co-instructors added to courses
+// Courses recorded before seat limits had none, so they get an unlimited number of seats
+func upgradeCourseV0(document map[string]interface{}) {
+	setDefault(document, "maxSeats", unlimitedSeats)
+	setDefault(document, "seatsFilled", 0)
+	setDefault(document, "coInstructors", []interface{}{})
+}
+
+// upgradeProgramV0 fills in the graduation requirements of programs stored before they had them
+func upgradeProgramV0(document map[string]interface{}) {
+	setDefault(document, "coreCourses", []interface{}{})
+	setDefault(document, "minCGPA", defaultMinCGPA)
+}
+
+// upgradeActivityV0 fills in the lifecycle added to extracurricular activities
+// Activities recorded before it are open and last the day of the activity; without a deadline registration stays open
+func upgradeActivityV0(document map[string]interface{}) {
+	if status, _ := document["status"].(string); status == "" {
+		document["status"] = activityOpen
+	}
+	date, _ := document["date"].(string)
+	if startDate, _ := document["startDate"].(string); startDate == "" {
+		document["startDate"] = date
+	}
+	if endDate, _ := document["endDate"].(string); endDate == "" {
+		document["endDate"] = date
+	}
+}
+
+// upgradeClubV0 fills in the advisor and coordinator lists of clubs
+func upgradeClubV0(document map[string]interface{}) {
+	setDefault(document, "facultyAdvisors", []interface{}{})
+	setDefault(document, "studentCoordinators", []interface{}{})
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// putDocument stores a document as written by an earlier version of the chaincode
+func (c *testContract) putDocument(objectType string, id string, document string) {
+	c.t.Helper()
+	c.stub.MockTransactionStart("document")
+	defer c.stub.MockTransactionEnd("document")
+	if err := putEntityState(c.context(), objectType, id, []byte(document)); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// schemaStatus returns the schema status of the documents of an object type
+func (c *testContract) schemaStatus(objectType string) SchemaStatus {
+	c.t.Helper()
+	var statuses []SchemaStatus
+	c.query(&statuses, "GetSchemaStatus")
+	for _, status := range statuses {
+		if status.ObjectType == objectType {
+			return status
+		}
+	}
+	c.t.Fatalf("no schema status for %s in %+v", objectType, statuses)
+	return SchemaStatus{}
+}
+
+func TestDocumentsAreUpgradedWhenRead(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.putDocument(courseObjectType, "CS100", `{"courseID":"CS100","name":"Basics","credits":3,"department":"CSE","facultyID":"F1"}`)
+	c.putDocument(enrollmentObjectType, "S9", `{"studentID":"S9","name":"Kiran","programType":"BTECH","department":"CSE","currentSemester":"Semester8","frozen":true,`+
+		`"certificates":[{"certificateID":"C1","activityID":"A1"}]}`)
+	c.putDocument(programObjectType, "MTECH", `{"name":"MTECH","maxSemesters":4,"requiredCredits":60,"maxCreditPerCredits":40,"minCreditPerCredits":10}`)
+	c.putDocument(activityObjectType, "A0", `{"activityID":"A0","activityName":"Quiz","date":"01012020","maxCount":5,"facultyID":"F1"}`)
+
+	// Courses recorded before seat limits had none
+	var course Course
+	c.query(&course, "GetCourse", "CS100")
+	if course.MaxSeats != unlimitedSeats || course.CoInstructors == nil || course.SchemaVersion != 1 {
+		t.Fatalf("upgraded course is %+v", course)
+	}
+
+	// A frozen enrollment has graduated, and its certificates predate the review workflow
+	enrollment := c.enrollment("S9")
+	if enrollment.Status != studentGraduated || enrollment.Transfers == nil || len(enrollment.Certificates) != 1 ||
+		enrollment.Certificates[0].Status != certificateApproved || enrollment.SchemaVersion != 2 {
+		t.Fatalf("upgraded enrollment is %+v", enrollment)
+	}
+
+	var program Program
+	c.query(&program, "GetProgram", "MTECH")
+	if program.MinCGPA != defaultMinCGPA || program.CoreCourses == nil {
+		t.Fatalf("upgraded program is %+v", program)
+	}
+	if activity := c.activity("A0"); activity.Status != activityOpen || activity.StartDate != "01012020" || activity.EndDate != "01012020" {
+		t.Fatalf("upgraded activity is %+v", activity)
+	}
+}
+
+func TestMigrateSchema(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.putDocument(courseObjectType, "CS100", `{"courseID":"CS100","name":"Basics","credits":3,"department":"CSE","facultyID":"F1"}`)
+	c.putDocument(enrollmentObjectType, "S9", `{"studentID":"S9","name":"Kiran","programType":"BTECH","department":"CSE","currentSemester":"Semester1"}`)
+
+	if status := c.schemaStatus(courseObjectType); status.CurrentVersion != 1 || status.Documents != 6 || status.Outdated != 1 {
+		t.Fatalf("course schema status is %+v", status)
+	}
+	c.mustFail("Batch size -1 is not valid", "MigrateSchema", "-1")
+
+	// Each batch upgrades up to the batch size
+	var migration SchemaMigration
+	c.query(&migration, "MigrateSchema", "1")
+	if migration.Upgraded[courseObjectType] != 1 || migration.Remaining != 1 {
+		t.Fatalf("first batch is %+v", migration)
+	}
+	if status := c.schemaStatus(enrollmentObjectType); status.CurrentVersion != 2 || status.Outdated != 1 {
+		t.Fatalf("enrollment schema status is %+v", status)
+	}
+	var second SchemaMigration
+	c.query(&second, "MigrateSchema", "0")
+	if second.Upgraded[enrollmentObjectType] != 1 || second.Remaining != 0 {
+		t.Fatalf("second batch is %+v", second)
+	}
+	if entry := c.lastLedgerUpdate(); entry != "Migrated 1 documents to the current schema, 0 remaining" {
+		t.Fatalf("ledger update is %q", entry)
+	}
+
+	// The upgrade is stored, keeping the fields as they were written
+	stored, err := getEntityState(c.context(), courseObjectType, "CS100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var document map[string]interface{}
+	if err := json.Unmarshal(stored, &document); err != nil {
+		t.Fatal(err)
+	}
+	if document["schemaVersion"] != 1.0 || document["maxSeats"] != float64(unlimitedSeats) || document["name"] != "Basics" {
+		t.Fatalf("stored course is %v", document)
+	}
+
+	var third SchemaMigration
+	c.query(&third, "MigrateSchema", "0")
+	if len(third.Upgraded) != 0 || third.Remaining != 0 {
+		t.Fatalf("third batch is %+v", third)
+	}
+}
+
+func TestNewerSchemaVersionsAreRefused(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.putDocument(departmentObjectType, "EE", `{"departmentID":"EE","departmentName":"Electrical","schemaVersion":7}`)
+
+	c.mustFail("The department document has schema version 7, newer than version 1 of this chaincode", "GetDepartment", "EE")
+	c.mustFail("newer than version 1 of this chaincode", "GetSchemaStatus")
+	c.mustFail("newer than version 1 of this chaincode", "MigrateSchema", "0")
+}
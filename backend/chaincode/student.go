@@ -8,18 +8,18 @@ import (
 )
 
 type Student struct {
-	StudentID    string `json:"studentID"`
-	StudentName  string `json:"studentName"`
-	ProgramType  string `json:"programType"`
-	DepartmentID string `json:"department"`
-	MaxSemesters int    `json:"maxSemesters"`
+	StudentID     string `json:"studentID"`
+	StudentName   string `json:"studentName"`
+	ProgramType   string `json:"programType"`
+	DepartmentID  string `json:"department"`
+	MaxSemesters  int    `json:"maxSemesters"`
+	ProfileHash   string `json:"profileHash"` // Hex encoded SHA-256 digest of the private profile, empty if none is stored
+	SchemaVersion int    `json:"schemaVersion"`
 }
 
-var studentMapping = make(map[string]Student)
-
 // GetStudent retrieves a student by their ID from the ledger
 func (s *StudentRecordContract) GetStudent(ctx contractapi.TransactionContextInterface, studentID string) (Student, error) {
-	studentJSON, err := ctx.GetStub().GetState(fmt.Sprintf("STUDENT-%s", studentID))
+	studentJSON, err := getEntityState(ctx, studentObjectType, studentID)
 	if err != nil {
 		return Student{}, fmt.Errorf("Failed to read student with ID %s: %v", studentID, err)
 	}
@@ -40,8 +40,16 @@ func (s *StudentRecordContract) GetStudent(ctx contractapi.TransactionContextInt
 func (s *StudentRecordContract) GetAllStudents(ctx contractapi.TransactionContextInterface) ([]Student, error) {
 	students := make([]Student, 0)
 
-	for _, student := range studentMapping {
+	err := forEachEntity(ctx, studentObjectType, func(value []byte) error {
+		var student Student
+		if err := json.Unmarshal(value, &student); err != nil {
+			return err
+		}
 		students = append(students, student)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return students, nil
@@ -49,7 +57,7 @@ func (s *StudentRecordContract) GetAllStudents(ctx contractapi.TransactionContex
 
 // GetStudentsByCourseIDInCoursesTaken retrieves all students who have a particular course with courseID in their CoursesTaken map
 func (s *StudentRecordContract) GetStudentsByCourseIDInCoursesTaken(ctx contractapi.TransactionContextInterface, courseID string) ([]string, error) {
-	// Retrieve all enrollments from the enrollmentMapping
+	// Retrieve all enrollments from the ledger
 	enrollments, err := s.GetAllEnrollments(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to retrieve enrollments: %v", err)
@@ -76,27 +84,13 @@ func (s *StudentRecordContract) GetStudentsByCourseIDInCoursesTaken(ctx contract
 }
 
 // GetStudentsByActivityIDInExtracurricular retrieves all students who have a particular extracurricular activity with ActivityID
+// Students who withdrew from the activity are left out
 func (s *StudentRecordContract) GetStudentsByActivityIDInExtracurricular(ctx contractapi.TransactionContextInterface, activityID string) ([]string, error) {
-	// Retrieve all enrollments from the enrollmentMapping
-	enrollments, err := s.GetAllEnrollments(ctx)
+	// Read the roster of the activity
+	roster, err := getActivityRoster(ctx, activityID)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to retrieve enrollments: %v", err)
-	}
-
-	// Initialize a collection to hold studentIDs who meet the criteria
-	var studentsWithActivity []string
-
-	// Iterate through each enrollment to check for the target activityID
-	for _, enrollment := range enrollments {
-		// Check each student's extracurricular activities for the target activityID
-		for _, extracurricularID := range enrollment.Extracurricular {
-			if extracurricularID == activityID {
-				// If the activityID matches, add the studentID to the result
-				studentsWithActivity = append(studentsWithActivity, enrollment.StudentID)
-				break // No need to check further activities for this student
-			}
-		}
+		return nil, fmt.Errorf("Failed to retrieve the roster of activity %s: %v", activityID, err)
 	}
 
-	return studentsWithActivity, nil
+	return rosterStudentIDs(roster, participationRegistered, participationAttended, participationAbsent), nil
 }
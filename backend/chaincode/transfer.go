@@ -0,0 +1,225 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// TransferRecord records the transfer of a student to another program or department
+type TransferRecord struct {
+	FromProgram     string   `json:"fromProgram"`
+	ToProgram       string   `json:"toProgram"`
+	FromDepartment  string   `json:"fromDepartment"`
+	ToDepartment    string   `json:"toDepartment"`
+	Semester        string   `json:"semester"`        // Semester in which the student was transferred
+	CreditsBefore   int      `json:"creditsBefore"`   // Credits completed under the previous program, 0 if the results are private
+	CreditsAfter    int      `json:"creditsAfter"`    // Credits accepted by the new program, 0 if the results are private
+	ExcludedCourses []string `json:"excludedCourses"` // Passed courses outside the curriculum of the new program, failed ones too if the results are private
+	Reason          string   `json:"reason"`
+	ApprovedBy      string   `json:"approvedBy"`
+	TransferredAt   string   `json:"transferredAt"`
+	TxID            string   `json:"txID"` // ID of the transaction that recorded the transfer
+}
+
+// TransferStudent moves a student to another program or department
+// The completed credits are re-evaluated against the curriculum of the new program and department: passed courses outside
+// it no longer count. Courses taken and results are kept as they are, and the transfer is added to the transfer history
+func (s *StudentRecordContract) TransferStudent(ctx contractapi.TransactionContextInterface, studentID string, programType string, departmentID string, reason string) error {
+	// Check if the caller is authorized (admin or head of the department the student moves to)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) && !s.isDepartmentHead(ctx, caller, departmentID) {
+		return fmt.Errorf("Unauthorized: only admin or the head of department %s can transfer students into it", departmentID)
+	}
+
+	student, err := s.GetStudent(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	existingEnrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	if err := ensureEnrollmentActive(existingEnrollment); err != nil {
+		return err
+	}
+
+	// Validate the new program and department
+	program, programExists, err := readProgram(ctx, programType)
+	if err != nil {
+		return err
+	}
+	if !programExists {
+		return fmt.Errorf("Invalid program type: %s", programType)
+	}
+	if program.Archived {
+		return fmt.Errorf("Program %s is archived", programType)
+	}
+	var department Department
+	departmentExists, err := readEntity(ctx, departmentObjectType, departmentID, &department)
+	if err != nil {
+		return err
+	}
+	if !departmentExists {
+		return fmt.Errorf("Department ID %s is not valid", departmentID)
+	}
+	if department.Archived {
+		return fmt.Errorf("Department %s is archived", departmentID)
+	}
+	if programType == existingEnrollment.ProgramType && departmentID == existingEnrollment.DepartmentID {
+		return fmt.Errorf("Student %s is already in program %s of department %s", studentID, programType, departmentID)
+	}
+	if reason == "" {
+		return fmt.Errorf("A reason is required to transfer a student")
+	}
+
+	// Check the rules of the new program
+	if semestersUsed(existingEnrollment) >= program.MaxSemesters {
+		return fmt.Errorf("Student %s has used %d semesters, the maximum of program %s is %d", studentID, semestersUsed(existingEnrollment), programType, program.MaxSemesters)
+	}
+	if existingEnrollment.CreditsThisSemester > program.MaxCreditPerSemester {
+		return fmt.Errorf("Credits this semester (%d) exceed the maximum of %d per semester for program %s", existingEnrollment.CreditsThisSemester, program.MaxCreditPerSemester, programType)
+	}
+
+	// Re-evaluate the completed credits against the new curriculum
+	creditsAfter, excludedCourses, err := s.transferableCredits(ctx, existingEnrollment, program, departmentID)
+	if err != nil {
+		return err
+	}
+
+	approvedBy, err := caller.GetID()
+	if err != nil {
+		return err
+	}
+	transferredAt, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+
+	transfer := TransferRecord{
+		FromProgram:     existingEnrollment.ProgramType,
+		ToProgram:       programType,
+		FromDepartment:  existingEnrollment.DepartmentID,
+		ToDepartment:    departmentID,
+		Semester:        existingEnrollment.CurrentSemester,
+		CreditsBefore:   existingEnrollment.CreditsCompleted,
+		CreditsAfter:    publicCreditsCompleted(existingEnrollment, creditsAfter),
+		ExcludedCourses: excludedCourses,
+		Reason:          reason,
+		ApprovedBy:      approvedBy,
+		TransferredAt:   transferredAt,
+		TxID:            ctx.GetStub().GetTxID(),
+	}
+
+	existingEnrollment.ProgramType = programType
+	existingEnrollment.DepartmentID = departmentID
+	existingEnrollment.CreditsCompleted = publicCreditsCompleted(existingEnrollment, creditsAfter)
+	existingEnrollment.Transfers = append(existingEnrollment.Transfers, transfer)
+
+	// An advisor outside the new department no longer advises the student, and a plan awaiting review is withdrawn
+	var advisor Faculty
+	advisorExists, err := readEntity(ctx, facultyObjectType, existingEnrollment.AdvisorID, &advisor)
+	if err != nil {
+		return err
+	}
+	if advisorExists && !inDepartment(advisor, departmentID) {
+		existingEnrollment.AdvisorID = ""
+		if index := pendingCoursePlan(existingEnrollment); index >= 0 {
+			existingEnrollment.CoursePlans[index].Status = planWithdrawn
+		}
+	}
+
+	student.ProgramType = programType
+	student.DepartmentID = departmentID
+	student.MaxSemesters = program.MaxSemesters
+
+	// Update the student and enrollment in the ledger
+	studentJSON, err := json.Marshal(student)
+	if err != nil {
+		return err
+	}
+	err = putEntityState(ctx, studentObjectType, studentID, studentJSON)
+	if err != nil {
+		return err
+	}
+	enrollmentJSON, err := json.Marshal(existingEnrollment)
+	if err != nil {
+		return err
+	}
+	err = putEntityState(ctx, enrollmentObjectType, studentID, enrollmentJSON)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Transferred student %s from %s in %s to %s in %s with %d of %d credits accepted", studentID, transfer.FromProgram, transfer.FromDepartment, programType, departmentID, creditsAfter, transfer.CreditsBefore)
+	if existingEnrollment.PrivateResults {
+		entry = fmt.Sprintf("Transferred student %s from %s in %s to %s in %s", studentID, transfer.FromProgram, transfer.FromDepartment, programType, departmentID)
+	}
+	if len(excludedCourses) > 0 {
+		entry += fmt.Sprintf(", excluding %s", strings.Join(excludedCourses, ", "))
+	}
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetTransferHistory returns the transfers of a student, oldest first
+func (s *StudentRecordContract) GetTransferHistory(ctx contractapi.TransactionContextInterface, studentID string) ([]TransferRecord, error) {
+	enrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return enrollment.Transfers, nil
+}
+
+// transferableCredits returns the credits of the passed courses accepted by a program and department, and the passed
+// courses that are not accepted
+// Without a curriculum for the program and department every passed course is accepted
+func (s *StudentRecordContract) transferableCredits(ctx contractapi.TransactionContextInterface, enrollment Enrollment, program Program, departmentID string) (int, []string, error) {
+	curriculum, err := s.findCurriculum(ctx, program.Name, departmentID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Collect the courses of the new curriculum
+	var accepted map[string]bool
+	if curriculum != nil {
+		accepted = curriculumCourses(*curriculum)
+		for _, courseID := range program.CoreCourses {
+			accepted[courseID] = true
+		}
+	}
+
+	allSemesterResults, err := s.getSemesterResults(ctx, enrollment)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Exclude the passed courses outside the curriculum
+	// The list is public, so for students whose results are private the failed courses are listed too, which earn no
+	// credits either way
+	excludedCourses := []string{}
+	for _, semesterResults := range allSemesterResults {
+		for _, result := range semesterResults {
+			if (enrollment.PrivateResults || !isFailingGrade(result.Grade)) && accepted != nil && !accepted[result.CourseID] {
+				excludedCourses = append(excludedCourses, result.CourseID)
+			}
+		}
+	}
+	sort.Strings(excludedCourses)
+
+	summary, err := s.creditSummary(ctx, enrollment, allSemesterResults, excludedCourses)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return summary.Earned, excludedCourses, nil
+}
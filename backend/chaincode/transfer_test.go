@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTransferStudent(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddDepartment", "EE", "Electrical")
+	c.mustInvoke("AddDepartment", "ME", "Mechanical")
+	c.mustInvoke("AddProgram", "MTECH", "4", "64", "40", "10")
+	c.passCourses(`["CS101","CS102"]`, `[{"courseID":"CS101","grade":"A"},{"courseID":"CS102","grade":"B"}]`)
+
+	c.mustFail("already in program BTECH of department CSE", "TransferStudent", "S1", "BTECH", "CSE", "Branch change")
+	c.mustFail("Invalid program type", "TransferStudent", "S1", "PHD", "CSE", "Branch change")
+	c.mustFail("is not valid", "TransferStudent", "S1", "BTECH", "CE", "Branch change")
+	c.mustFail("A reason is required", "TransferStudent", "S1", "BTECH", "EE", "")
+	c.mustFail("exceed the maximum of 40 per semester", "TransferStudent", "S1", "MTECH", "CSE", "Program change")
+	c.mustInvoke("ArchiveEntity", entityDepartment, "ME")
+	c.mustFail("Department ME is archived", "TransferStudent", "S1", "BTECH", "ME", "Branch change")
+
+	// Only the courses in the curriculum of the new department keep their credits
+	c.mustInvoke("SetCurriculum", "BTECH", "EE", `{"coreCourses":["CS101"]}`)
+	c.mustInvoke("TransferStudent", "S1", "BTECH", "EE", "Branch change")
+	c.restart()
+
+	var transfers []TransferRecord
+	c.query(&transfers, "GetTransferHistory", "S1")
+	if len(transfers) != 1 {
+		t.Fatalf("transfer history is %+v", transfers)
+	}
+	transfer := transfers[0]
+	if transfer.FromDepartment != "CSE" || transfer.ToDepartment != "EE" || transfer.Semester != "Semester1" || transfer.CreditsBefore != 74 || transfer.CreditsAfter != 37 ||
+		strings.Join(transfer.ExcludedCourses, ",") != "CS102" || transfer.ApprovedBy == "" || transfer.TxID == "" {
+		t.Fatalf("transfer is %+v", transfer)
+	}
+
+	// Courses taken and results are kept
+	enrollment := c.enrollment("S1")
+	if enrollment.DepartmentID != "EE" || enrollment.CreditsCompleted != 37 || len(enrollment.SemesterResults["Semester1"]) != 2 {
+		t.Fatalf("enrollment after the transfer is %+v", enrollment)
+	}
+	var student Student
+	c.query(&student, "GetStudent", "S1")
+	if student.DepartmentID != "EE" || student.ProgramType != "BTECH" {
+		t.Fatalf("student after the transfer is %+v", student)
+	}
+}
+
+func TestTransferStudentAdvisor(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddDepartment", "EE", "Electrical")
+	c.mustInvoke("SetFacultyDesignations", "F1", `["advisor"]`)
+	c.mustInvoke("AssignAdvisor", "S1", "F1")
+	c.mustInvoke("SubmitCoursePlan", "S1", `["CS101"]`)
+
+	// A transfer without a curriculum keeps every credit, but an advisor of the old department no longer advises the student
+	c.mustInvoke("TransferStudent", "S1", "BTECH", "EE", "Branch change")
+	enrollment := c.enrollment("S1")
+	if enrollment.AdvisorID != "" || len(enrollment.CoursePlans) != 1 || enrollment.CoursePlans[0].Status != planWithdrawn {
+		t.Fatalf("enrollment after the transfer is %+v", enrollment)
+	}
+	if transfers := enrollment.Transfers; len(transfers) != 1 || len(transfers[0].ExcludedCourses) != 0 {
+		t.Fatalf("transfers are %+v", transfers)
+	}
+}
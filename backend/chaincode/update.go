@@ -0,0 +1,437 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// The Update* transactions take the version of the record the caller last read and a JSON object with the fields to change
+// Fields left out of the JSON keep their value; the update is refused if the record changed since it was read
+
+// CourseUpdate holds the course fields that can be changed
+type CourseUpdate struct {
+	CourseName   *string `json:"name"`
+	Credits      *int    `json:"credits"`
+	FacultyID    *string `json:"facultyID"`
+	Description  *string `json:"description"`
+	AcademicYear *int    `json:"academicYear"`
+	Semester     *int    `json:"semester"`
+	MaxSeats     *int    `json:"maxSeats"`
+}
+
+// FacultyUpdate holds the faculty fields that can be changed
+type FacultyUpdate struct {
+	FacultyName  *string `json:"facultyName"`
+	DepartmentID *string `json:"department"`
+}
+
+// DepartmentUpdate holds the department fields that can be changed
+type DepartmentUpdate struct {
+	DepartmentName *string `json:"departmentName"`
+}
+
+// ProgramUpdate holds the program fields that can be changed
+// Core courses and the minimum CGPA are set with SetProgramGraduationRequirements
+type ProgramUpdate struct {
+	MaxSemesters         *int `json:"maxSemesters"`
+	RequiredCredits      *int `json:"requiredCredits"`
+	MaxCreditPerSemester *int `json:"maxCreditPerCredits"`
+	MinCreditPerSemester *int `json:"minCreditPerCredits"`
+}
+
+// ActivityUpdate holds the extracurricular activity fields that can be changed
+// Dates are set with SetActivitySchedule and the status with SetActivityStatus
+type ActivityUpdate struct {
+	ActivityName *string `json:"name"`
+	Description  *string `json:"description"`
+	Location     *string `json:"location"`
+	MaxCount     *int    `json:"maxCount"`
+	FacultyID    *string `json:"facultyID"`
+}
+
+// UpdateCourse changes the name, credits, faculty, description, academic year, semester or seats of a course
+func (s *StudentRecordContract) UpdateCourse(ctx contractapi.TransactionContextInterface, courseID string, expectedVersion int, updatesJSON string) error {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can update courses")
+	}
+
+	var update CourseUpdate
+	if err := decodeUpdate(updatesJSON, &update); err != nil {
+		return err
+	}
+
+	var course Course
+	exists, err := readEntity(ctx, courseObjectType, courseID, &course)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("Course with ID %s does not exist", courseID)
+	}
+	if err := checkVersion(entityCourse, courseID, course.Version, expectedVersion); err != nil {
+		return err
+	}
+
+	// Validate the new values
+	if update.CourseName != nil && *update.CourseName == "" {
+		return fmt.Errorf("Course name is required")
+	}
+	if update.Credits != nil && *update.Credits != course.Credits {
+		if *update.Credits <= 0 {
+			return fmt.Errorf("Credits must be positive")
+		}
+		// Results already recorded were graded with the old credits
+		taken, err := s.hasStudentDependents(ctx, entityCourse, courseID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return fmt.Errorf("Credits of course %s cannot change after students have taken it", courseID)
+		}
+	}
+	if update.FacultyID != nil && *update.FacultyID != course.FacultyID {
+		var faculty Faculty
+		facultyExists, err := readEntity(ctx, facultyObjectType, *update.FacultyID, &faculty)
+		if err != nil {
+			return err
+		}
+		if !facultyExists {
+			return fmt.Errorf("Faculty ID %s is not valid", *update.FacultyID)
+		}
+		if faculty.Archived {
+			return fmt.Errorf("Faculty %s is archived", *update.FacultyID)
+		}
+		if !inDepartment(faculty, course.DepartmentID) {
+			return fmt.Errorf("Faculty with ID %s is not associated with department %s", *update.FacultyID, course.DepartmentID)
+		}
+	}
+	if update.AcademicYear != nil && *update.AcademicYear <= 0 {
+		return fmt.Errorf("Academic year %d is not valid", *update.AcademicYear)
+	}
+	if update.Semester != nil && *update.Semester <= 0 {
+		return fmt.Errorf("Semester %d is not valid", *update.Semester)
+	}
+	if update.MaxSeats != nil && *update.MaxSeats < course.SeatsFilled {
+		return fmt.Errorf("Max seats %d is below the %d seats already filled in course %s", *update.MaxSeats, course.SeatsFilled, courseID)
+	}
+
+	changes := make([]string, 0)
+	changeString(&changes, "name", update.CourseName, &course.CourseName)
+	changeInt(&changes, "credits", update.Credits, &course.Credits)
+	changeString(&changes, "faculty", update.FacultyID, &course.FacultyID)
+	changeString(&changes, "description", update.Description, &course.Description)
+	changeInt(&changes, "academic year", update.AcademicYear, &course.AcademicYear)
+	changeInt(&changes, "semester", update.Semester, &course.Semester)
+	changeInt(&changes, "max seats", update.MaxSeats, &course.MaxSeats)
+	if len(changes) == 0 {
+		return fmt.Errorf("No changes given for course %s", courseID)
+	}
+	course.Version++
+
+	err = putCatalogState(ctx, courseObjectType, courseID, course)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Updated course %s to version %d: %s", courseID, course.Version, strings.Join(changes, ", "))
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateFaculty changes the name or department of a faculty
+// A faculty cannot leave a department whose courses they teach or which they head
+func (s *StudentRecordContract) UpdateFaculty(ctx contractapi.TransactionContextInterface, facultyID string, expectedVersion int, updatesJSON string) error {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can update faculty")
+	}
+
+	var update FacultyUpdate
+	if err := decodeUpdate(updatesJSON, &update); err != nil {
+		return err
+	}
+
+	var faculty Faculty
+	exists, err := readEntity(ctx, facultyObjectType, facultyID, &faculty)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("Faculty with ID %s does not exist", facultyID)
+	}
+	if err := checkVersion(entityFaculty, facultyID, faculty.Version, expectedVersion); err != nil {
+		return err
+	}
+
+	// Validate the new values
+	if update.FacultyName != nil && *update.FacultyName == "" {
+		return fmt.Errorf("Faculty name is required")
+	}
+	if update.DepartmentID != nil && *update.DepartmentID != faculty.DepartmentID {
+		var department Department
+		departmentExists, err := readEntity(ctx, departmentObjectType, *update.DepartmentID, &department)
+		if err != nil {
+			return err
+		}
+		if !departmentExists {
+			return fmt.Errorf("Department ID %s is not valid", *update.DepartmentID)
+		}
+		if department.Archived {
+			return fmt.Errorf("Department %s is archived", *update.DepartmentID)
+		}
+		if contains(faculty.JointDepartments, *update.DepartmentID) {
+			return fmt.Errorf("Faculty %s is jointly appointed to department %s, remove the joint appointment first", facultyID, *update.DepartmentID)
+		}
+		movedFaculty := faculty
+		movedFaculty.DepartmentID = *update.DepartmentID
+		if err := ensureAppointmentsCoverDuties(ctx, movedFaculty); err != nil {
+			return err
+		}
+	}
+
+	changes := make([]string, 0)
+	changeString(&changes, "name", update.FacultyName, &faculty.FacultyName)
+	changeString(&changes, "department", update.DepartmentID, &faculty.DepartmentID)
+	if len(changes) == 0 {
+		return fmt.Errorf("No changes given for faculty %s", facultyID)
+	}
+	faculty.Version++
+
+	err = putCatalogState(ctx, facultyObjectType, facultyID, faculty)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Updated faculty %s to version %d: %s", facultyID, faculty.Version, strings.Join(changes, ", "))
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateDepartment renames a department
+func (s *StudentRecordContract) UpdateDepartment(ctx contractapi.TransactionContextInterface, departmentID string, expectedVersion int, updatesJSON string) error {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can update departments")
+	}
+
+	var update DepartmentUpdate
+	if err := decodeUpdate(updatesJSON, &update); err != nil {
+		return err
+	}
+
+	var department Department
+	exists, err := readEntity(ctx, departmentObjectType, departmentID, &department)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("Department with ID %s does not exist", departmentID)
+	}
+	if err := checkVersion(entityDepartment, departmentID, department.Version, expectedVersion); err != nil {
+		return err
+	}
+
+	// Validate the new values
+	if update.DepartmentName != nil && *update.DepartmentName == "" {
+		return fmt.Errorf("Department name is required")
+	}
+
+	changes := make([]string, 0)
+	changeString(&changes, "name", update.DepartmentName, &department.DepartmentName)
+	if len(changes) == 0 {
+		return fmt.Errorf("No changes given for department %s", departmentID)
+	}
+	department.Version++
+
+	err = putCatalogState(ctx, departmentObjectType, departmentID, department)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Updated department %s to version %d: %s", departmentID, department.Version, strings.Join(changes, ", "))
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateProgram changes the semester and credit limits of a program
+func (s *StudentRecordContract) UpdateProgram(ctx contractapi.TransactionContextInterface, programName string, expectedVersion int, updatesJSON string) error {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can update programs")
+	}
+
+	var update ProgramUpdate
+	if err := decodeUpdate(updatesJSON, &update); err != nil {
+		return err
+	}
+
+	program, exists, err := readProgram(ctx, programName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("Program %s does not exist", programName)
+	}
+	if err := checkVersion(entityProgram, programName, program.Version, expectedVersion); err != nil {
+		return err
+	}
+
+	changes := make([]string, 0)
+	changeInt(&changes, "max semesters", update.MaxSemesters, &program.MaxSemesters)
+	changeInt(&changes, "required credits", update.RequiredCredits, &program.RequiredCredits)
+	changeInt(&changes, "max credits per semester", update.MaxCreditPerSemester, &program.MaxCreditPerSemester)
+	changeInt(&changes, "min credits per semester", update.MinCreditPerSemester, &program.MinCreditPerSemester)
+	if len(changes) == 0 {
+		return fmt.Errorf("No changes given for program %s", programName)
+	}
+
+	// Validate the limits together, since they depend on each other
+	if program.MaxSemesters <= 0 || program.RequiredCredits <= 0 || program.MaxCreditPerSemester <= 0 || program.MinCreditPerSemester < 0 {
+		return fmt.Errorf("Semester and credit limits of program %s must be positive", programName)
+	}
+	if program.MinCreditPerSemester > program.MaxCreditPerSemester {
+		return fmt.Errorf("Minimum credits per semester (%d) exceed the maximum (%d)", program.MinCreditPerSemester, program.MaxCreditPerSemester)
+	}
+	program.Version++
+
+	err = putCatalogState(ctx, programObjectType, programName, program)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Updated program %s to version %d: %s", programName, program.Version, strings.Join(changes, ", "))
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateExtracurricularActivity changes the name, description, location, participant limit or faculty in charge of an activity
+func (s *StudentRecordContract) UpdateExtracurricularActivity(ctx contractapi.TransactionContextInterface, activityID string, expectedVersion int, updatesJSON string) error {
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) && !s.isFaculty(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin or faculty can update activities")
+	}
+
+	var update ActivityUpdate
+	if err := decodeUpdate(updatesJSON, &update); err != nil {
+		return err
+	}
+
+	activity, err := s.GetExtracurricularActivity(ctx, activityID)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(entityActivity, activityID, activity.Version, expectedVersion); err != nil {
+		return err
+	}
+
+	// Validate the new values
+	if update.ActivityName != nil && *update.ActivityName == "" {
+		return fmt.Errorf("Activity name is required")
+	}
+	if update.MaxCount != nil && *update.MaxCount < activity.ParticipantCount {
+		return fmt.Errorf("Max count %d is below the %d students already registered for extracurricular activity %s", *update.MaxCount, activity.ParticipantCount, activityID)
+	}
+	if update.FacultyID != nil && *update.FacultyID != activity.FacultyID {
+		var faculty Faculty
+		facultyExists, err := readEntity(ctx, facultyObjectType, *update.FacultyID, &faculty)
+		if err != nil {
+			return err
+		}
+		if !facultyExists {
+			return fmt.Errorf("Faculty ID %s is not valid", *update.FacultyID)
+		}
+		if faculty.Archived {
+			return fmt.Errorf("Faculty %s is archived", *update.FacultyID)
+		}
+	}
+
+	changes := make([]string, 0)
+	changeString(&changes, "name", update.ActivityName, &activity.ActivityName)
+	changeString(&changes, "description", update.Description, &activity.Description)
+	changeString(&changes, "location", update.Location, &activity.Location)
+	changeInt(&changes, "max count", update.MaxCount, &activity.MaxCount)
+	changeString(&changes, "faculty", update.FacultyID, &activity.FacultyID)
+	if len(changes) == 0 {
+		return fmt.Errorf("No changes given for extracurricular activity %s", activityID)
+	}
+	activity.Version++
+
+	err = s.putExtracurricularActivity(ctx, activity)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Updated extracurricular activity %s to version %d: %s", activityID, activity.Version, strings.Join(changes, ", "))
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// decodeUpdate parses the fields of an update, refusing fields that cannot be changed
+func decodeUpdate(updatesJSON string, update interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(updatesJSON)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(update); err != nil {
+		return fmt.Errorf("Invalid update %s: %v", updatesJSON, err)
+	}
+	return nil
+}
+
+// checkVersion refuses an update made against an older version of a record
+func checkVersion(entityType string, entityID string, version int, expectedVersion int) error {
+	if version != expectedVersion {
+		return fmt.Errorf("%s %s was modified since it was read: expected version %d, current version %d", entityLabels[entityType], entityID, expectedVersion, version)
+	}
+	return nil
+}
+
+// changeString sets a text field to its new value and notes the change
+func changeString(changes *[]string, field string, value *string, target *string) {
+	if value == nil || *value == *target {
+		return
+	}
+	*changes = append(*changes, fmt.Sprintf("%s %q -> %q", field, *target, *value))
+	*target = *value
+}
+
+// changeInt sets a number field to its new value and notes the change
+func changeInt(changes *[]string, field string, value *int, target *int) {
+	if value == nil || *value == *target {
+		return
+	}
+	*changes = append(*changes, fmt.Sprintf("%s %d -> %d", field, *target, *value))
+	*target = *value
+}
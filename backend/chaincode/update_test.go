@@ -0,0 +1,100 @@
+package main
+
+import "testing"
+
+// lastLedgerUpdate returns the entry of the latest ledger update
+func (c *testContract) lastLedgerUpdate() string {
+	c.t.Helper()
+	var updates []LedgerUpdate
+	c.query(&updates, "GetAllLedgerUpdates")
+	if len(updates) == 0 {
+		c.t.Fatal("no ledger update is recorded")
+	}
+	return updates[len(updates)-1].Entry
+}
+
+func TestUpdateCourse(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddFaculty", "F2", "Ravi Kumar", "CSE")
+
+	c.mustFail("Invalid update", "UpdateCourse", "CS101", "0", `{"seatsFilled":3}`)
+	c.mustFail("No changes given for course CS101", "UpdateCourse", "CS101", "0", `{"name":"Programming"}`)
+	c.mustFail("Course name is required", "UpdateCourse", "CS101", "0", `{"name":""}`)
+	c.mustFail("Faculty ID F9 is not valid", "UpdateCourse", "CS101", "0", `{"facultyID":"F9"}`)
+	c.mustFail("does not exist", "UpdateCourse", "CS999", "0", `{"name":"Compilers"}`)
+	c.mustInvoke("UpdateCourse", "CS101", "0", `{"name":"Introduction to Programming","credits":36,"facultyID":"F2"}`)
+	if entry := c.lastLedgerUpdate(); entry != `Updated course CS101 to version 1: name "Programming" -> "Introduction to Programming", credits 37 -> 36, faculty "F1" -> "F2"` {
+		t.Fatalf("ledger update is %q", entry)
+	}
+
+	// An update against the version read before is refused
+	c.mustFail("was modified since it was read: expected version 0, current version 1", "UpdateCourse", "CS101", "0", `{"name":"Programming"}`)
+
+	var course Course
+	c.query(&course, "GetCourse", "CS101")
+	if course.Version != 1 || course.CourseName != "Introduction to Programming" || course.Credits != 36 || course.FacultyID != "F2" || course.Description != "" {
+		t.Fatalf("updated course is %+v", course)
+	}
+
+	// Credits and seats are fixed by the students who took the course
+	c.mustInvoke("AddCoursesToCurrentSemester", "S1", `["CS101"]`)
+	c.mustFail("cannot change after students have taken it", "UpdateCourse", "CS101", "1", `{"credits":37}`)
+	c.mustFail("Max seats 0 is below the 1 seats already filled", "UpdateCourse", "CS101", "1", `{"maxSeats":0}`)
+}
+
+func TestUpdateFacultyAndDepartment(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddDepartment", "EE", "Electrical")
+	c.mustInvoke("AddFaculty", "F2", "Ravi Kumar", "CSE")
+
+	c.mustFail("Department ID ME is not valid", "UpdateFaculty", "F2", "0", `{"department":"ME"}`)
+	c.mustInvoke("SetJointAppointments", "F2", `["EE"]`)
+	c.mustFail("remove the joint appointment first", "UpdateFaculty", "F2", "1", `{"department":"EE"}`)
+	c.mustInvoke("UpdateFaculty", "F2", "1", `{"facultyName":"Ravi Kumar Rao"}`)
+	var faculty Faculty
+	c.query(&faculty, "GetFaculty", "F2")
+	if faculty.FacultyName != "Ravi Kumar Rao" || faculty.Version != 2 || faculty.DepartmentID != "CSE" {
+		t.Fatalf("updated faculty is %+v", faculty)
+	}
+
+	c.mustFail("Department name is required", "UpdateDepartment", "EE", "0", `{"departmentName":""}`)
+	c.mustInvoke("UpdateDepartment", "EE", "0", `{"departmentName":"Electrical Engineering"}`)
+	var department Department
+	c.query(&department, "GetDepartment", "EE")
+	if department.DepartmentName != "Electrical Engineering" || department.Version != 1 {
+		t.Fatalf("updated department is %+v", department)
+	}
+}
+
+func TestUpdateProgram(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	c.mustFail("Minimum credits per semester (1000) exceed the maximum", "UpdateProgram", "BTECH", "0", `{"minCreditPerCredits":1000}`)
+	c.mustFail("must be positive", "UpdateProgram", "BTECH", "0", `{"maxSemesters":0}`)
+	c.mustFail("Program PHD does not exist", "UpdateProgram", "PHD", "0", `{"requiredCredits":100}`)
+	c.mustInvoke("UpdateProgram", "BTECH", "0", `{"requiredCredits":161}`)
+
+	var program Program
+	c.query(&program, "GetProgram", "BTECH")
+	if program.RequiredCredits != 161 || program.MaxSemesters != 8 || program.Version != 1 {
+		t.Fatalf("updated program is %+v", program)
+	}
+}
+
+func TestUpdateExtracurricularActivity(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+	c.mustInvoke("AddFaculty", "F2", "Ravi Kumar", "CSE")
+	c.mustInvoke("AddExtracurricularActivity", "A1", "Chess", "Inter-hostel chess", "Hall 1", "01012030", "2", "F1")
+	c.mustInvoke("AddExtracurricularActivityForStudent", "S1", "A1")
+
+	c.mustFail("Invalid update", "UpdateExtracurricularActivity", "A1", "0", `{"startDate":"02012030"}`)
+	c.mustFail("Max count 0 is below the 1 students already registered", "UpdateExtracurricularActivity", "A1", "0", `{"maxCount":0}`)
+	c.mustInvoke("UpdateExtracurricularActivity", "A1", "0", `{"location":"Hall 2","facultyID":"F2"}`)
+	if activity := c.activity("A1"); activity.Location != "Hall 2" || activity.FacultyID != "F2" || activity.ActivityName != "Chess" || activity.Version != 1 {
+		t.Fatalf("updated activity is %+v", activity)
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// VerificationRecord is written when a credential is issued so that third parties can check it later
+type VerificationRecord struct {
+	CredentialID     string            `json:"credentialID"`   // DEGREE-<studentID>, CERTIFICATE-<studentID>-<activityID> or the transcript ID
+	CredentialType   string            `json:"credentialType"` // degree, certificate, transcript or verifiableCredential
+	StudentID        string            `json:"studentID"`
+	StudentName      string            `json:"studentName"`
+	Details          map[string]string `json:"details"`        // Public details of the credential, such as the program and division of a degree
+	DocumentHashes   []string          `json:"documentHashes"` // Hex encoded SHA-256 hashes of the documents issued for the credential
+	IssuerMSPID      string            `json:"issuerMSPID"`
+	IssuedAt         string            `json:"issuedAt"`
+	TxID             string            `json:"txID"` // ID of the transaction that issued the credential
+	Revoked          bool              `json:"revoked"`
+	RevokedAt        string            `json:"revokedAt"`
+	RevocationReason string            `json:"revocationReason"`
+	SchemaVersion    int               `json:"schemaVersion"`
+}
+
+// VerificationResult is the answer to a verification request
+type VerificationResult struct {
+	Verified     bool               `json:"verified"`
+	CredentialID string             `json:"credentialID"`
+	DocumentHash string             `json:"documentHash"`
+	Message      string             `json:"message"`
+	Record       VerificationRecord `json:"record"` // Empty if no credential matches
+}
+
+// Credential types with verification records
+const (
+	credentialTypeDegree      = "degree"
+	credentialTypeCertificate = "certificate"
+	credentialTypeTranscript  = "transcript"
+	credentialTypeVC          = "verifiableCredential"
+)
+
+// RecordTranscriptIssuance records the hashes of a transcript exported for a student so that it can be verified later
+func (s *StudentRecordContract) RecordTranscriptIssuance(ctx contractapi.TransactionContextInterface, transcriptID string, studentID string, transcriptHash string, fileHash string) error {
+	// Check if the caller is authorized (admin)
+	caller := ctx.GetClientIdentity()
+	if !s.isAdmin(ctx, caller) {
+		return fmt.Errorf("Unauthorized: only admin can record a transcript issuance")
+	}
+
+	if !strings.HasPrefix(transcriptID, fmt.Sprintf("TRANSCRIPT-%s-", studentID)) {
+		return fmt.Errorf("Transcript ID %s does not belong to student %s", transcriptID, studentID)
+	}
+
+	// Check if a transcript with the same ID was already issued
+	existingJSON, err := getEntityState(ctx, verificationObjectType, transcriptID)
+	if err != nil {
+		return err
+	}
+	if existingJSON != nil {
+		return fmt.Errorf("Transcript %s has already been issued", transcriptID)
+	}
+
+	// Fetch the student's existing enrollment
+	enrollment, err := s.GetEnrollment(ctx, studentID)
+	if err != nil {
+		return err
+	}
+
+	hashes := []string{transcriptHash}
+	if fileHash != "" {
+		hashes = append(hashes, fileHash)
+	}
+
+	record := VerificationRecord{
+		CredentialID:   transcriptID,
+		CredentialType: credentialTypeTranscript,
+		StudentID:      studentID,
+		StudentName:    enrollment.Name,
+		Details: map[string]string{
+			"programType": enrollment.ProgramType,
+			"department":  enrollment.DepartmentID,
+			"semester":    enrollment.CurrentSemester,
+		},
+		DocumentHashes: hashes,
+	}
+	err = s.putVerificationRecord(ctx, record)
+	if err != nil {
+		return err
+	}
+
+	// Record the ledger update
+	entry := fmt.Sprintf("Issued transcript %s for student %s", transcriptID, studentID)
+	err = s.recordLedgerUpdate(ctx, entry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// VerifyCredential checks that a credential was issued and, if a document hash is given, that the document matches it
+func (s *StudentRecordContract) VerifyCredential(ctx contractapi.TransactionContextInterface, credentialID string, documentHash string) (*VerificationResult, error) {
+	result := newVerificationResult()
+	result.CredentialID = credentialID
+
+	if documentHash != "" {
+		normalizedHash, err := normalizeDocumentHash(documentHash)
+		if err != nil {
+			return nil, err
+		}
+		result.DocumentHash = normalizedHash
+	}
+
+	record, err := s.getVerificationRecord(ctx, credentialID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		result.Message = fmt.Sprintf("No credential with ID %s has been issued", credentialID)
+		return &result, nil
+	}
+	result.Record = *record
+
+	if result.DocumentHash != "" && !contains(record.DocumentHashes, result.DocumentHash) {
+		result.Message = fmt.Sprintf("Document does not match credential %s", credentialID)
+		return &result, nil
+	}
+
+	if record.Revoked {
+		result.Message = fmt.Sprintf("Credential %s was revoked on %s: %s", credentialID, record.RevokedAt, record.RevocationReason)
+		return &result, nil
+	}
+
+	// A verifiable credential is only valid while the credential it was issued for is valid
+	if sourceCredentialID, exists := record.Details["sourceCredentialID"]; exists {
+		source, err := s.getVerificationRecord(ctx, sourceCredentialID)
+		if err != nil {
+			return nil, err
+		}
+		if source == nil || source.Revoked {
+			result.Message = fmt.Sprintf("Credential %s was issued for %s, which is no longer valid", credentialID, sourceCredentialID)
+			return &result, nil
+		}
+	}
+
+	result.Verified = true
+	result.Message = fmt.Sprintf("Credential %s was issued by %s on %s", credentialID, record.IssuerMSPID, record.IssuedAt)
+
+	return &result, nil
+}
+
+// VerifyDocumentHash finds the credential issued with a document hash
+func (s *StudentRecordContract) VerifyDocumentHash(ctx contractapi.TransactionContextInterface, documentHash string) (*VerificationResult, error) {
+	normalizedHash, err := normalizeDocumentHash(documentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	credentialID, err := getEntityState(ctx, verificationHashObjectType, normalizedHash)
+	if err != nil {
+		return nil, err
+	}
+	if credentialID == nil {
+		result := newVerificationResult()
+		result.DocumentHash = normalizedHash
+		result.Message = "No credential has been issued with this document"
+		return &result, nil
+	}
+
+	return s.VerifyCredential(ctx, string(credentialID), normalizedHash)
+}
+
+// newVerificationResult returns an unverified result with an empty record
+func newVerificationResult() VerificationResult {
+	return VerificationResult{
+		Record: VerificationRecord{Details: map[string]string{}, DocumentHashes: []string{}},
+	}
+}
+
+// putVerificationRecord stores a verification record, stamped with the issuing organization and transaction, and indexes its document hashes
+func (s *StudentRecordContract) putVerificationRecord(ctx contractapi.TransactionContextInterface, record VerificationRecord) error {
+	// Normalize the document hashes
+	hashes := []string{}
+	for _, hash := range record.DocumentHashes {
+		normalizedHash, err := normalizeDocumentHash(hash)
+		if err != nil {
+			return err
+		}
+		if !contains(hashes, normalizedHash) {
+			hashes = append(hashes, normalizedHash)
+		}
+	}
+	record.DocumentHashes = hashes
+	record.SchemaVersion = schemaVersion(verificationObjectType)
+
+	// A revoked credential stays revoked, it cannot be issued again under the same ID
+	existingRecord, err := s.getVerificationRecord(ctx, record.CredentialID)
+	if err != nil {
+		return err
+	}
+	if existingRecord != nil && existingRecord.Revoked {
+		return fmt.Errorf("Credential %s has been revoked and cannot be issued again", record.CredentialID)
+	}
+
+	if record.Details == nil {
+		record.Details = map[string]string{}
+	}
+
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return err
+	}
+	issuedAt, err := txTimestamp(ctx)
+	if err != nil {
+		return err
+	}
+	record.IssuerMSPID = mspID
+	record.IssuedAt = issuedAt
+	record.TxID = ctx.GetStub().GetTxID()
+
+	return s.storeVerificationRecord(ctx, record)
+}
+
+// storeVerificationRecord writes a verification record to the ledger and indexes its document hashes
+func (s *StudentRecordContract) storeVerificationRecord(ctx contractapi.TransactionContextInterface, record VerificationRecord) error {
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	err = putEntityState(ctx, verificationObjectType, record.CredentialID, recordJSON)
+	if err != nil {
+		return err
+	}
+
+	// Index the document hashes so that a document can be verified without knowing its credential ID
+	for _, hash := range record.DocumentHashes {
+		err = putEntityState(ctx, verificationHashObjectType, hash, []byte(record.CredentialID))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getVerificationRecord retrieves the verification record of a credential, or nil if it was never issued
+func (s *StudentRecordContract) getVerificationRecord(ctx contractapi.TransactionContextInterface, credentialID string) (*VerificationRecord, error) {
+	recordJSON, err := getEntityState(ctx, verificationObjectType, credentialID)
+	if err != nil {
+		return nil, err
+	}
+	if recordJSON == nil {
+		return nil, nil
+	}
+
+	var record VerificationRecord
+	err = json.Unmarshal(recordJSON, &record)
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// normalizeDocumentHash checks that a hash is a hex encoded SHA-256 digest and returns it in lower case
+func normalizeDocumentHash(hash string) (string, error) {
+	normalizedHash := strings.ToLower(strings.TrimSpace(hash))
+	decoded, err := hex.DecodeString(normalizedHash)
+	if err != nil || len(decoded) != 32 {
+		return "", fmt.Errorf("Document hash %s is not a hex encoded SHA-256 digest", hash)
+	}
+
+	return normalizedHash, nil
+}
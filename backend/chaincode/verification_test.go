@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	testTranscriptHash = "ab0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcd"
+	testFileHash       = "cd0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcd"
+)
+
+func (c *testContract) verify(credentialID string, documentHash string) VerificationResult {
+	c.t.Helper()
+	var result VerificationResult
+	c.query(&result, "VerifyCredential", credentialID, documentHash)
+	return result
+}
+
+func TestVerifyDegree(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	if result := c.verify("DEGREE-S1", ""); result.Verified {
+		t.Fatalf("degree verifies before it is conferred: %+v", result)
+	}
+
+	c.graduate()
+	c.restart()
+	result := c.verify("DEGREE-S1", "")
+	if !result.Verified || result.Record.CredentialType != credentialTypeDegree || result.Record.StudentName != "Asha" {
+		t.Fatalf("conferred degree does not verify: %+v", result)
+	}
+	if result.Record.IssuerMSPID != "Org1MSP" || result.Record.TxID == "" {
+		t.Fatalf("degree record is not stamped with its issuer and transaction: %+v", result.Record)
+	}
+	if result.Record.Details["division"] != c.enrollment("S1").Graduation.Division {
+		t.Fatalf("degree details are %v", result.Record.Details)
+	}
+}
+
+func TestRecordTranscriptIssuance(t *testing.T) {
+	c := newTestContract(t)
+	c.addCatalog()
+
+	c.mustFail("does not belong to student", "RecordTranscriptIssuance", "TRANSCRIPT-S2-1", "S1", testTranscriptHash, "")
+	c.mustFail("not a hex encoded SHA-256 digest", "RecordTranscriptIssuance", "TRANSCRIPT-S1-1", "S1", "zz", "")
+	c.mustInvoke("RecordTranscriptIssuance", "TRANSCRIPT-S1-1", "S1", strings.ToUpper(testTranscriptHash), testFileHash)
+	c.mustFail("already been issued", "RecordTranscriptIssuance", "TRANSCRIPT-S1-1", "S1", testTranscriptHash, "")
+
+	// Either hash of the transcript verifies, in any case
+	for _, hash := range []string{testTranscriptHash, strings.ToUpper(testFileHash)} {
+		var result VerificationResult
+		c.query(&result, "VerifyDocumentHash", hash)
+		if !result.Verified || result.CredentialID != "TRANSCRIPT-S1-1" || result.DocumentHash != strings.ToLower(hash) {
+			t.Fatalf("VerifyDocumentHash(%s) returned %+v", hash, result)
+		}
+	}
+
+	var unknown VerificationResult
+	c.query(&unknown, "VerifyDocumentHash", "ee"+testTranscriptHash[2:])
+	if unknown.Verified || unknown.CredentialID != "" {
+		t.Fatalf("unknown document verifies: %+v", unknown)
+	}
+	if result := c.verify("TRANSCRIPT-S1-1", "ee"+testTranscriptHash[2:]); result.Verified {
+		t.Fatalf("transcript verifies against another document: %+v", result)
+	}
+	c.mustFail("not a hex encoded SHA-256 digest", "VerifyCredential", "TRANSCRIPT-S1-1", "abc")
+}
+
+func TestNormalizeDocumentHash(t *testing.T) {
+	hash, err := normalizeDocumentHash(" " + strings.ToUpper(testTranscriptHash) + "\n")
+	if err != nil || hash != testTranscriptHash {
+		t.Fatalf("normalizeDocumentHash returned %q, %v", hash, err)
+	}
+	if _, err := normalizeDocumentHash(testTranscriptHash[:62]); err == nil {
+		t.Fatal("normalizeDocumentHash accepts a short digest")
+	}
+}
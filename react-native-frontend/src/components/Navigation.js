@@ -33,6 +33,7 @@ import ManageEntitiesScreen from './admin/ManageEntitiesScreen';
 
 import ViewCoursesFacultyScreen from './faculty/ViewCoursesFacultyScreen';
 import ViewEnrolledStudentsScreen from './faculty/ViewEnrolledStudentScreen';
+import ReviewCoursePlansScreen from './faculty/ReviewCoursePlansScreen';
 
 
 import ExistingStudentsScreen from './admin/students/ExistingStudentsScreen';
@@ -110,6 +111,7 @@ const MainStack = () => (
         <Stack.Screen name="ViewCoursesFaculty" component={ViewCoursesFacultyScreen} options={{ headerShown: false }}/>
 
         <Stack.Screen name="ViewEnrolledStudents" component={ViewEnrolledStudentsScreen} options={{ headerShown: false }}/>
+        <Stack.Screen name="ReviewCoursePlans" component={ReviewCoursePlansScreen} options={{ headerShown: false }}/>
 
         <Stack.Screen name="ExistingStudents" component={ExistingStudentsScreen} options={{ headerShown: false }}/>
         <Stack.Screen name="ManageStudents" component={ManageStudentsScreen} options={{ headerShown: false }}/>
@@ -1,12 +1,11 @@
 
 
 import React, { useState } from 'react';
-import { View, Button, Text, Alert, Image, StyleSheet, TouchableOpacity, ActivityIndicator } from 'react-native';
+import { View, Button, Text, Alert, Image, StyleSheet, TouchableOpacity, ActivityIndicator, TextInput } from 'react-native';
 import * as DocumentPicker from 'expo-document-picker';
 import * as FileSystem from 'expo-file-system';
 import * as Sharing from 'expo-sharing'
 import { ImageZoomViewer } from 'react-native-image-zoom-viewer';
-import { S3 } from 'aws-sdk';
 const axios = require('axios');
 import { useNavigation } from '@react-navigation/native';
 const AddCertificatesScreen = () => {
@@ -14,7 +13,10 @@ const AddCertificatesScreen = () => {
     const [uploadEnabled, setUploadEnabled] = useState(false);
     const [file, setFile] = useState(null);
     const [isLoading, setIsLoading] = useState(false);
+    const [studentID, setStudentID] = useState('');
+    const [activityID, setActivityID] = useState('');
     const navigation = useNavigation();
+    const baseURL = 'https://measured-wasp-terminally.ngrok-free.app';
     const pickDocument = async () => {
         try {
             const result = await DocumentPicker.getDocumentAsync({
@@ -42,28 +44,31 @@ const AddCertificatesScreen = () => {
             Alert.alert('Please select a file');
             return;
         }
+        if (!studentID || !activityID) {
+            Alert.alert('Please enter the roll number and activity ID');
+            return;
+        }
+
+        // The API stores the file and records its key and hash on the ledger
+        const filename = fileUri.split('/').pop(); // Extract filename from URI
+        const formData = new FormData();
+        formData.append("channelid", "mychannel");
+        formData.append("chaincodeid", "basic");
+        formData.append("args", studentID);
+        formData.append("args", activityID);
+        formData.append("file", {
+            uri: fileUri,
+            name: filename,
+            type: 'application/octet-stream',
+        });
 
         try {
-            const s3 = new S3({
-                signatureVersion: 'v4',
-                accessKeyId: '12CECFEFEB7400A9E352',
-                Bucket: 'ipfs-campblock',
-                secretAccessKey: 'Njvwh69KuDMIf9b43va1iRcPDJEz4RfYUhHursx9',
-                endpoint: 'https://s3.filebase.com',
+            const res = await axios.post(`${baseURL}/UploadCertificate`, formData, {
+                headers: {
+                    "Content-Type": "multipart/form-data"
+                }
             });
-
-            const response = await fetch(fileUri);
-            const blob = await response.blob(); // Convert image data to Blob
-            const filename = fileUri.split('/').pop(); // Extract filename from URI
-
-            const params = {
-                Bucket: 'ipfs-campblock',
-                Key: filename,
-                Body: blob,
-            };
-            // const response = await s3.putObject(params);
-            const res = await s3.putObject(params).promise();
-            console.log('upload response:', res);
+            console.log('upload response:', res.data);
 
             Alert.alert('Upload Successful', 'File uploaded successfully', [
                 // { text: 'OK', onPress: () => setFileUri(null) }
@@ -75,23 +80,21 @@ const AddCertificatesScreen = () => {
     };
     const handleView = async () => {
         setIsLoading(true);
-        const s3 = new S3({
-            signatureVersion: 'v4',
-            accessKeyId: '12CECFEFEB7400A9E352',
-            secretAccessKey: 'Njvwh69KuDMIf9b43va1iRcPDJEz4RfYUhHursx9',
-            Bucket: 'ipfs-campblock',
-            region: '', // e.g., 'us-east-1'
-            endpoint: 'https://s3.filebase.com', // or your S3 endpoint
-        });
+        const formData = new URLSearchParams();
+        formData.append("channelid", "mychannel");
+        formData.append("chaincodeid", "basic");
+        formData.append("args", studentID);
+        formData.append("args", activityID);
 
         try {
-            const url = s3.getSignedUrl('getObject', {
-                Bucket: 'ipfs-campblock',
-                Key: fileUri.split('/').pop(),
-                Expires: 300,
-            })
-            setFile(url);
-            console.log('download url: ', url);
+            // The API returns a short-lived signed URL for the certificate file
+            const res = await axios.post(`${baseURL}/CertificateDownloadURL`, formData, {
+                headers: {
+                    "Content-Type": "application/x-www-form-urlencoded"
+                }
+            });
+            setFile(res.data.url);
+            console.log('download url: ', res.data.url);
         }
         catch (error) {
             console.error('Error downloading image:', error);
@@ -108,6 +111,8 @@ const AddCertificatesScreen = () => {
             ) : (
                 <>
             <View style={styles.buttonContainer}>
+                <TextInput style={styles.input} placeholder="Roll No" value={studentID} onChangeText={setStudentID} autoCapitalize="characters" />
+                <TextInput style={styles.input} placeholder="Activity ID" value={activityID} onChangeText={setActivityID} autoCapitalize="characters" />
                 <TouchableOpacity style={styles.Button1} onPress={pickDocument} >
                     <Text style={styles.ButtonText1}>Pick File </Text>
                 </TouchableOpacity>
@@ -139,6 +144,15 @@ const styles = StyleSheet.create({
         marginTop: 20,
         resizeMode: 'contain',
     },
+    input: {
+        width: '80%',
+        borderWidth: 1,
+        borderColor: '#7E57C2',
+        borderRadius: 5,
+        paddingVertical: 8,
+        paddingHorizontal: 10,
+        marginTop: 10,
+    },
     buttonContainer: {
         // flexDirection: 'row',
         justifyContent: 'center',
@@ -2,7 +2,6 @@ import React, { useState, useEffect } from 'react';
 import { View, Text, StyleSheet, Alert, FlatList, TouchableOpacity, ActivityIndicator, TextInput, Image, Dimensions, Modal } from 'react-native';
 import { Button, Card, Title } from 'react-native-paper';
 import * as DocumentPicker from 'expo-document-picker';
-import { S3 } from 'aws-sdk';
 import axios from 'axios';
 import Icon from 'react-native-vector-icons/MaterialCommunityIcons'; // Import icon library
 import { useNavigation } from '@react-navigation/native';
@@ -116,74 +115,40 @@ const ViewRegisteredStudentsScreen = ({ route }) => {
         };
 
         const handleAddCertificate = async (studentID, activityID) => {
-            const baseURL = 'https://measured-wasp-terminally.ngrok-free.app/AddCertificateForStudent';
+            const baseURL = 'https://measured-wasp-terminally.ngrok-free.app/UploadCertificate';
             console.log("activityID inside handleAddCerti: ", activityID);
 
-            //upload to aws and get the key
             if (!fileUri) {
                 Alert.alert('Please select a file');
                 return;
             }
 
+            // The API stores the file and records its key and hash on the ledger
+            const filename = fileUri.split('/').pop(); // Extract filename from URI
+            const formData = new FormData();
+            formData.append("args", studentID);
+            formData.append("args", activityID);
+            formData.append("file", {
+                uri: fileUri,
+                name: filename,
+                type: 'application/octet-stream',
+            });
+
             try {
-                const s3 = new S3({
-                    signatureVersion: 'v4',
-                    accessKeyId: '12CECFEFEB7400A9E352',
-                    Bucket: 'ipfs-campblock',
-                    secretAccessKey: 'Njvwh69KuDMIf9b43va1iRcPDJEz4RfYUhHursx9',
-                    endpoint: 'https://s3.filebase.com',
+                const response = await axios.post(baseURL, formData, {
+                    headers: {
+                        "Content-Type": "multipart/form-data"
+                    }
                 });
 
-                const response = await fetch(fileUri);
-                const blob = await response.blob(); // Convert image data to Blob
-                const filename = fileUri.split('/').pop(); // Extract filename from URI
-
-                const params = {
-                    Bucket: 'ipfs-campblock',
-                    Key: filename,
-                    Body: blob,
-                };
-                // const response = await s3.putObject(params);
-                const res = await s3.putObject(params).promise();
-                console.log('upload response:', res);
-
+                console.log('certificate added successfully:', response.data);
                 Alert.alert('Upload Successful', 'File uploaded successfully', [
                     { text: 'OK', onPress: () => setFileUri(null) }
                 ]);
-
-                const key = filename;
-                // const certificateToAdd = [
-                //     {
-                //         activityID, key,
-                //     }
-                // ];
-
-                const formData = new URLSearchParams();
-                formData.append("args", studentID);
-                formData.append("args", activityID);
-                formData.append("args", key);
-                // formData.append("args", JSON.stringify(certificateToAdd));
-
-                try {
-
-                    const response = await axios.post(baseURL, formData, {
-                        headers: {
-                            "Content-Type": "application/x-www-form-urlencoded"
-                        }
-                    });
-
-                    console.log('certificate added successfully:', response.data);
-                    // Optionally show an alert or update UI upon successful addition of grade
-                    // Alert.alert('Certificate added successfully.');
-
-                } catch (error) {
-                    // console.error('Error adding certificate:', error);
-                    Alert.alert('Error', 'Failed to add certificate.');
-                }
-                } catch (error) {
-                    // console.error('Error uploading file:', error);
-                    Alert.alert('Upload Failed', 'An error occurred while uploading the file');
-                }
+            } catch (error) {
+                // console.error('Error uploading file:', error);
+                Alert.alert('Upload Failed', 'An error occurred while uploading the file');
+            }
         };
 
         return (
@@ -27,6 +27,14 @@ const FacultyDashboardScreen = ({ navigation }) => {
                     >
                         Courses
                     </Button>
+                    <Button
+                        mode="contained"
+                        style={[styles.button, { backgroundColor: '#FFA726' }]}
+                        onPress={() => navigation.navigate('ReviewCoursePlans')}
+                        labelStyle={styles.buttonText}
+                    >
+                        Course Plans
+                    </Button>
                     <Button
                         mode="contained"
                         // style={styles.button}
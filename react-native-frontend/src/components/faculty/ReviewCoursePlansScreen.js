@@ -0,0 +1,231 @@
+import React, { useState, useEffect, useContext } from 'react';
+import { View, Text, StyleSheet, FlatList, TouchableOpacity, TextInput, ActivityIndicator, Alert } from 'react-native';
+import { useNavigation } from '@react-navigation/native';
+import UserContext from '../UserContext';
+import axios from 'axios';
+import Icon from 'react-native-vector-icons/MaterialCommunityIcons'; // Import icon library
+import { Button } from 'react-native-paper';
+
+// Course plans of the faculty's advisees waiting for approval
+const ReviewCoursePlansScreen = () => {
+    const [plans, setPlans] = useState([]);
+    const [isLoading, setIsLoading] = useState(true);
+    const [expandedStudentID, setExpandedStudentID] = useState(null);
+    const [remarks, setRemarks] = useState('');
+    const { userData } = useContext(UserContext);
+
+    const navigation = useNavigation();
+    useEffect(() => {
+        fetchPendingCoursePlans();
+    }, []);
+
+    const fetchPendingCoursePlans = async () => {
+        try {
+            const baseURL = 'https://measured-wasp-terminally.ngrok-free.app';
+            const chaincodeid = 'basic';
+            const channelid = 'mychannel';
+            const functionName = 'GetPendingCoursePlans';
+            const facultyID = userData.facultyID;
+            const apiURL = `${baseURL}/GetPendingCoursePlans?chaincodeid=${chaincodeid}&channelid=${channelid}&function=${functionName}&args=${facultyID}`;
+
+            const response = await axios.get(apiURL);
+            console.log('GetPendingCoursePlans response:', response.data);
+            setPlans(response.data || []);
+            setIsLoading(false);
+        } catch (error) {
+            // console.error('Error fetching course plans:', error);
+            setIsLoading(false);
+        }
+    };
+
+    const handleReview = async (studentID, decision) => {
+        if (decision === 'rejected' && remarks.trim() === '') {
+            Alert.alert('Error', 'Enter remarks to reject a course plan.');
+            return;
+        }
+
+        const baseURL = 'https://measured-wasp-terminally.ngrok-free.app/ReviewCoursePlan'; // Update with your API URL
+        const formData = new URLSearchParams();
+        formData.append("args", studentID);
+        formData.append("args", decision);
+        formData.append("args", remarks.trim());
+
+        try {
+            const response = await axios.post(baseURL, formData, {
+                headers: {
+                    "Content-Type": "application/x-www-form-urlencoded"
+                }
+            });
+            console.log('ReviewCoursePlan response:', response.data);
+            Alert.alert(`Course plan of ${studentID} ${decision}`);
+            setRemarks('');
+            setExpandedStudentID(null);
+            fetchPendingCoursePlans();
+        } catch (error) {
+            Alert.alert('Error', 'Review failed: a course may have no seats left or the student may have exceeded the credit limit.');
+        }
+    };
+
+    const renderPlanItem = ({ item }) => {
+        const { studentID, name, plan } = item;
+        const isExpanded = expandedStudentID === studentID;
+
+        // Render the expanded section with the plan and the review actions
+        const renderExpandedSection = () => (
+            <View style={styles.additionalDetails}>
+                <Text>Semester: {plan.semester}</Text>
+                <Text>Courses: {plan.courses.join(', ')}</Text>
+                <Text>Credits: {plan.credits}</Text>
+                <Text>Submitted: {plan.submittedAt}</Text>
+                <TextInput
+                    style={styles.remarksInput}
+                    placeholder="Remarks (required to reject)"
+                    value={remarks}
+                    onChangeText={(text) => setRemarks(text)}
+                />
+                <View style={styles.reviewButtons}>
+                    <TouchableOpacity style={styles.approveButton} onPress={() => handleReview(studentID, 'approved')}>
+                        <Text style={styles.reviewButtonText}>Approve</Text>
+                    </TouchableOpacity>
+                    <TouchableOpacity style={styles.rejectButton} onPress={() => handleReview(studentID, 'rejected')}>
+                        <Text style={styles.reviewButtonText}>Reject</Text>
+                    </TouchableOpacity>
+                </View>
+            </View>
+        );
+
+        return (
+            <TouchableOpacity
+                style={styles.planItem}
+                onPress={() => {
+                    setExpandedStudentID(isExpanded ? null : studentID);
+                    setRemarks('');
+                }}
+            >
+                <Text style={styles.studentName}>{name}</Text>
+                <Text style={styles.studentID}>Roll No: {studentID}</Text>
+                {isExpanded && renderExpandedSection()}
+            </TouchableOpacity>
+        );
+    };
+
+    if (isLoading) {
+        return (
+            <View style={styles.container}>
+                <ActivityIndicator size="large" color="#7E57C2" />
+            </View>
+        );
+    }
+
+    return (
+        <View style={styles.container}>
+            {plans.length === 0 && <Text style={styles.emptyText}>No course plans are waiting for your approval.</Text>}
+            <FlatList
+                data={plans}
+                keyExtractor={(item) => item.studentID}
+                renderItem={renderPlanItem}
+            />
+            <View style={styles.bottomBar}>
+                <Button
+                    style={styles.bottomBarButton}
+                    onPress={() => navigation.navigate('FacultyDashboard')}
+                    icon={() => (
+                        <>
+                            <Icon name="arrow-left" size={40} color="#7E57C2" />
+                        </>
+                    )}
+                />
+            </View>
+        </View>
+    );
+};
+
+const styles = StyleSheet.create({
+    container: {
+        flex: 1,
+        backgroundColor: '#fff',
+        paddingHorizontal: 20,
+        paddingTop: 40,
+    },
+    emptyText: {
+        color: '#7E57C2',
+        fontSize: 16,
+        textAlign: 'center',
+        marginBottom: 20,
+    },
+    planItem: {
+        backgroundColor: '#7E57C2',
+        padding: 15,
+        marginBottom: 15,
+        borderRadius: 10,
+    },
+    studentName: {
+        color: '#FFFFFF',
+        fontSize: 18,
+        fontWeight: 'bold',
+        marginBottom: 5,
+    },
+    studentID: {
+        color: '#FFFFFF',
+        fontSize: 16,
+    },
+    additionalDetails: {
+        backgroundColor: '#FFFFFF',
+        padding: 10,
+        marginTop: 10,
+        borderRadius: 5,
+    },
+    remarksInput: {
+        height: 40,
+        borderColor: '#7E57C2',
+        borderWidth: 1,
+        borderRadius: 5,
+        paddingHorizontal: 10,
+        marginTop: 10,
+    },
+    reviewButtons: {
+        flexDirection: 'row',
+        justifyContent: 'space-between',
+        marginTop: 10,
+    },
+    approveButton: {
+        flex: 1,
+        backgroundColor: '#4CAF50',
+        padding: 10,
+        borderRadius: 10,
+        marginRight: 5,
+    },
+    rejectButton: {
+        flex: 1,
+        backgroundColor: '#E53935',
+        padding: 10,
+        borderRadius: 10,
+        marginLeft: 5,
+    },
+    reviewButtonText: {
+        color: 'white',
+        fontWeight: 'bold',
+        textAlign: 'center',
+    },
+    bottomBar: {
+        // position: 'absolute',
+        bottom: 0,
+        flexDirection: 'row',
+        width: '100%',
+        height: 56,
+        justifyContent: 'space-around',
+        alignItems: 'center',
+        paddingHorizontal: 20,
+        backgroundColor: '#FFF', // White background color
+        borderTopWidth: 1,
+        borderTopColor: '#E0E0E0', // Light gray border color
+    },
+    bottomBarButton: {
+        backgroundColor: 'transparent', // Make the button background transparent
+        borderWidth: 1,
+        marginTop: 5,
+        marginBottom: 5,
+    },
+});
+
+export default ReviewCoursePlansScreen;
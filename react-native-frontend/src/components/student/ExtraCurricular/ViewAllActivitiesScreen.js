@@ -1,5 +1,5 @@
 import React, { useState, useEffect, useContext } from 'react';
-import { View, Text, StyleSheet, FlatList, TouchableOpacity, TextInput, Alert } from 'react-native';
+import { View, Text, StyleSheet, FlatList, TouchableOpacity, TextInput, Alert, ScrollView } from 'react-native';
 import UserContext from '../../UserContext';
 import axios from 'axios';
 import { Button, Card, Title } from 'react-native-paper';
@@ -15,22 +15,33 @@ const ViewAllActivitiesScreen = () => {
     const { userData } = useContext(UserContext); // Access userData from context
     const [error, setError] = useState('');
     const navigation = useNavigation();
+    const [clubs, setClubs] = useState([]);
+    const [selectedClub, setSelectedClub] = useState(''); // Empty filter matches every club
+    const [selectedCategory, setSelectedCategory] = useState('');
+    const [term, setTerm] = useState('');
+
+    const categories = ['sports', 'cultural', 'technical', 'NSS'];
 
     useEffect(() => {
-        fetchCourses();
+        fetchClubs();
     }, []);
 
-    const baseURL = 'https://measured-wasp-terminally.ngrok-free.app/GetAllExtracurricularActivities';
+    useEffect(() => {
+        fetchCourses();
+    }, [selectedClub, selectedCategory]);
+
     const chaincodeid = 'basic';
     const channelid = 'mychannel';
-    const functionName = 'GetAllExtracurricularActivities';
-    const apiURL = `${baseURL}?chaincodeid=${chaincodeid}&channelid=${channelid}&function=${functionName}`;
 
     const fetchCourses = async () => {
+        const baseURL = 'https://measured-wasp-terminally.ngrok-free.app/FilterExtracurricularActivities';
+        const functionName = 'FilterExtracurricularActivities';
+        const args = [selectedClub, selectedCategory, term.trim()].map((arg) => `&args=${encodeURIComponent(arg)}`).join('');
+        const apiURL = `${baseURL}?chaincodeid=${chaincodeid}&channelid=${channelid}&function=${functionName}${args}`;
         try {
             const response = await axios.get(apiURL);
-            console.log('GetAllExtracurricularActivities response:', response.data);
-            setActivities(response.data);
+            console.log('FilterExtracurricularActivities response:', response.data);
+            setActivities(Array.isArray(response.data) ? response.data : []);
             setIsLoading(false);
         } catch (error) {
             // console.error('Error fetching Activities:', error);
@@ -38,6 +49,27 @@ const ViewAllActivitiesScreen = () => {
         }
     };
 
+    const fetchClubs = async () => {
+        const baseURL = 'https://measured-wasp-terminally.ngrok-free.app/GetAllClubs';
+        const apiURL = `${baseURL}?chaincodeid=${chaincodeid}&channelid=${channelid}&function=GetAllClubs`;
+        try {
+            const response = await axios.get(apiURL);
+            setClubs(Array.isArray(response.data) ? response.data : []);
+        } catch (error) {
+            // The activities can still be filtered by category and term
+        }
+    };
+
+    const renderFilterChip = (label, selected, onPress) => (
+        <TouchableOpacity
+            key={label}
+            style={[styles.filterChip, selected && styles.filterChipSelected]}
+            onPress={onPress}
+        >
+            <Text style={[styles.filterChipText, selected && styles.filterChipTextSelected]}>{label}</Text>
+        </TouchableOpacity>
+    );
+
 
     const toggleExpand = (activityName) => {
         if (expandedActivityName === activityName) {
@@ -58,7 +90,7 @@ const ViewAllActivitiesScreen = () => {
         setSortOrder(sortOrder === 'asc' ? 'desc' : 'asc');
     };
     const renderActivityItem = ({ item }) => {
-        const { activityID, name, description, location, date, maxCount, facultyID } = item;
+        const { activityID, name, description, location, date, maxCount, facultyID, category, clubID, term } = item;
 
 
         // Extract day, month, and year from the date string
@@ -93,6 +125,9 @@ const ViewAllActivitiesScreen = () => {
                         <Text>Location: {location}</Text>
                         <Text>Date: {formattedDate}</Text>
                         <Text>Description: {description}</Text>
+                        {category ? <Text>Category: {category}</Text> : null}
+                        {clubID ? <Text>Club: {clubs.find((club) => club.clubID === clubID)?.name || clubID}</Text> : null}
+                        {term ? <Text>Term: {term}</Text> : null}
                     </View>
                 )}
                 {error ? <Text style={styles.errorText}>{error}</Text> : null}
@@ -156,6 +191,27 @@ const ViewAllActivitiesScreen = () => {
                     <Text style={styles.sortButtonText}>Sort by Activity ID ({sortOrder.toUpperCase()})</Text>
                 </TouchableOpacity>
             </View>
+            <ScrollView horizontal showsHorizontalScrollIndicator={false} style={styles.filterRow}>
+                {renderFilterChip('All Categories', selectedCategory === '', () => setSelectedCategory(''))}
+                {categories.map((category) =>
+                    renderFilterChip(category, selectedCategory === category, () => setSelectedCategory(category))
+                )}
+            </ScrollView>
+            {clubs.length > 0 && (
+                <ScrollView horizontal showsHorizontalScrollIndicator={false} style={styles.filterRow}>
+                    {renderFilterChip('All Clubs', selectedClub === '', () => setSelectedClub(''))}
+                    {clubs.map((club) =>
+                        renderFilterChip(club.name, selectedClub === club.clubID, () => setSelectedClub(club.clubID))
+                    )}
+                </ScrollView>
+            )}
+            <TextInput
+                style={styles.termInput}
+                placeholder="Filter by term..."
+                value={term}
+                onChangeText={(text) => setTerm(text)}
+                onSubmitEditing={fetchCourses}
+            />
             <FlatList
                 data={activities}
                 keyExtractor={(item) => item.activityID}
@@ -210,6 +266,36 @@ const styles = StyleSheet.create({
         fontWeight: 'bold',
         fontSize: 16,
     },
+    filterRow: {
+        flexGrow: 0,
+        marginBottom: 10,
+    },
+    filterChip: {
+        borderColor: '#4CAF50',
+        borderWidth: 1,
+        borderRadius: 15,
+        paddingVertical: 5,
+        paddingHorizontal: 12,
+        marginRight: 8,
+    },
+    filterChipSelected: {
+        backgroundColor: '#4CAF50',
+    },
+    filterChipText: {
+        color: '#4CAF50',
+    },
+    filterChipTextSelected: {
+        color: '#FFFFFF',
+        fontWeight: 'bold',
+    },
+    termInput: {
+        height: 40,
+        borderColor: '#4CAF50',
+        borderWidth: 1,
+        borderRadius: 5,
+        paddingHorizontal: 10,
+        marginBottom: 15,
+    },
     activityItem: {
         backgroundColor: '#4CAF50',
         padding: 15,
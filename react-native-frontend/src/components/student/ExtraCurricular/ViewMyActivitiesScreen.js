@@ -4,7 +4,6 @@ import { Button, Card, Title } from 'react-native-paper';
 import { useNavigation } from '@react-navigation/native';
 import axios from 'axios';
 import UserContext from '../../UserContext';
-import { S3 } from 'aws-sdk';
 import Icon from 'react-native-vector-icons/MaterialCommunityIcons'; // Import icon library
 
 const ViewMyActivitiesScreen = () => {
@@ -88,6 +87,10 @@ const ViewMyActivitiesScreen = () => {
                         <Text>Date: {activityInfo?.date}</Text>
                         <Text>Max Count: {activityInfo?.maxCount}</Text>
                         <Text>Faculty ID: {activityInfo?.facultyID}</Text>
+                        <Text>Status: {activityInfo?.status}</Text>
+                        {activityInfo?.status === 'cancelled' && (
+                            <Text>Cancelled: {activityInfo?.cancellationReason}</Text>
+                        )}
                     </View>
                 )}
                 {/* {file && <Image source={{ uri: file }} style={{ width: 200, height: 200 }} />} */}
@@ -124,59 +127,58 @@ const ViewMyActivitiesScreen = () => {
                         <Text style={styles.viewEnrolledButtonText}>View Certificate</Text>
                     </TouchableOpacity>
                 )}
+                {isExpanded && (
+                    <TouchableOpacity
+                        style={styles.viewEnrolledButton}
+                        onPress={() => withdrawFromActivity(activityID)}
+                    >
+                        <Text style={styles.viewEnrolledButtonText}>Withdraw</Text>
+                    </TouchableOpacity>
+                )}
             </TouchableOpacity>
         );
     };
 
     const viewCertificate = async (activityID) => {
-        const baseURL = 'https://measured-wasp-terminally.ngrok-free.app/GetCertificateForActivityAndStudent'; // Update with your API URL
+        const baseURL = 'https://measured-wasp-terminally.ngrok-free.app/CertificateDownloadURL'; // Update with your API URL
 
         const studentID = userData.rollNo;
         const formData = new URLSearchParams();
+        formData.append("channelid", "mychannel");
+        formData.append("chaincodeid", "basic");
         formData.append("args", studentID);
         formData.append("args", activityID);
+        setIsLoading(true);
         try {
+            // The API returns a short-lived signed URL for the certificate file
             const response = await axios.post(baseURL, formData, {
                 headers: {
                     "Content-Type": "application/x-www-form-urlencoded"
                 }
             });
-            console.log('GetCertificateForActivityAndStudent response:', response.data);
-            const key = response.data.key;
-            console.log('key:', key);
-            // Handle success (if needed)
-            setIsLoading(true);
-            const s3 = new S3({
-                signatureVersion: 'v4',
-                accessKeyId: '12CECFEFEB7400A9E352',
-                secretAccessKey: 'Njvwh69KuDMIf9b43va1iRcPDJEz4RfYUhHursx9',
-                Bucket: 'ipfs-campblock',
-                region: '', // e.g., 'us-east-1'
-                endpoint: 'https://s3.filebase.com', // or your S3 endpoint
-            });
-
-            try {
-                const url = s3.getSignedUrl('getObject', {
-                    Bucket: 'ipfs-campblock',
-                    Key: key,
-                    Expires: 300,
-                })
-                setFile(url);
-                console.log('download url: ', url);
-            }
-            catch (error) {
-                // console.error('Error downloading image:', error);
-                Alert('Error!', 'Not Found.')
-            } finally {
-                setIsLoading(false);
-            }
-
-
-
-
+            console.log('CertificateDownloadURL response:', response.data);
+            setFile(response.data.url);
         } catch (error) {
             Alert.alert('Not Found!', 'Failed to get certificate.');
-            // throw new Error('adding course failed');
+        } finally {
+            setIsLoading(false);
+        }
+    };
+
+    const withdrawFromActivity = async (activityID) => {
+        const formData = new URLSearchParams();
+        formData.append("args", userData.rollNo);
+        formData.append("args", activityID);
+        try {
+            const response = await axios.post(`${baseURL}/WithdrawFromExtracurricularActivity`, formData, {
+                headers: {
+                    "Content-Type": "application/x-www-form-urlencoded"
+                }
+            });
+            console.log('WithdrawFromExtracurricularActivity response:', response.data);
+            Alert.alert(`Withdrawn from Activity ID: ${activityID}`);
+        } catch (error) {
+            Alert.alert('Failed', 'Cannot withdraw after attendance is marked or the activity has completed.');
         }
     };
 
@@ -15,9 +15,12 @@ const StudentAddCourseScreen = () => {
     const [sortOrder, setSortOrder] = useState('asc'); // State to track sorting order
     const [searchQuery, setSearchQuery] = useState('');
     const [expandedCourseName, setExpandedCourseName] = useState(null); // Track expanded program
+    const [planCourses, setPlanCourses] = useState([]); // Courses picked for the course plan
+    const [latestPlan, setLatestPlan] = useState(null); // Most recent course plan sent to the advisor
     const { userData } = useContext(UserContext); // Access userData from context
     useEffect(() => {
         fetchCourses();
+        fetchCoursePlans();
     }, []);
 
     const baseURL = 'https://measured-wasp-terminally.ngrok-free.app/GetCoursesByDepartment';
@@ -41,6 +44,53 @@ const StudentAddCourseScreen = () => {
     };
 
 
+    const fetchCoursePlans = async () => {
+        const plansURL = `https://measured-wasp-terminally.ngrok-free.app/GetCoursePlans?chaincodeid=${chaincodeid}&channelid=${channelid}&function=GetCoursePlans&args=${userData.rollNo}`;
+        try {
+            const response = await axios.get(plansURL);
+            console.log('GetCoursePlans response:', response.data);
+            const plans = response.data || [];
+            setLatestPlan(plans.length > 0 ? plans[plans.length - 1] : null);
+        } catch (error) {
+            console.error('Error fetching course plans:', error);
+        }
+    };
+
+    // Courses are registered once the advisor approves the plan, so adding only picks them here
+    const togglePlanCourse = (courseID) => {
+        if (planCourses.includes(courseID)) {
+            setPlanCourses(planCourses.filter((id) => id !== courseID));
+        } else {
+            setPlanCourses([...planCourses, courseID]);
+        }
+    };
+
+    const handleSubmitPlan = async () => {
+        if (planCourses.length === 0) {
+            Alert.alert('Error', 'Add at least one course to the plan.');
+            return;
+        }
+
+        const baseURL = 'https://measured-wasp-terminally.ngrok-free.app/SubmitCoursePlan'; // Update with your API URL
+        const formData = new URLSearchParams();
+        formData.append("args", userData.rollNo);
+        formData.append("args", JSON.stringify(planCourses));
+
+        try {
+            const response = await axios.post(baseURL, formData, {
+                headers: {
+                    "Content-Type": "application/x-www-form-urlencoded"
+                }
+            });
+            console.log('SubmitCoursePlan response:', response.data);
+            Alert.alert(`Course plan with ${planCourses.join(', ')} sent to your advisor for approval`);
+            setPlanCourses([]);
+            fetchCoursePlans();
+        } catch (error) {
+            Alert.alert('Error', 'Course plan not submitted: check that you have an advisor, the courses have seats and you have credits left.');
+        }
+    };
+
     const toggleExpand = (courseName) => {
         if (expandedCourseName === courseName) {
             // Collapse the currently expanded Course
@@ -64,37 +114,7 @@ const StudentAddCourseScreen = () => {
         const { courseID, name, credits, department, facultyID, description, academicYear, semester } = item;
 
         const isExpanded = expandedCourseName === name;
-
-
-        const handleAdd = async (courseID) => {
-
-            const baseURL = 'https://measured-wasp-terminally.ngrok-free.app/AddCoursesToCurrentSemester'; // Update with your API URL
-            const rollNo = userData.rollNo;
-            
-            const coursesToAdd = [
-                courseID,
-            ];
-            const formData = new URLSearchParams();
-            formData.append("args", rollNo);
-            formData.append("args", JSON.stringify(coursesToAdd));
-
-            try {
-                const response = await axios.post(baseURL, formData, {
-                    headers: {
-                        "Content-Type": "application/x-www-form-urlencoded"
-                    }
-                });
-                console.log('AddCoursesToCurrentSemester response:', response.data);
-                Alert.alert(`Added Course ID: ${courseID} into the current semester`);
-                // Handle success (if needed)
-            } catch (error) {
-                // console.error('Error adding course:', error);
-                Alert.alert('Error', 'Either already added or No credits left.');
-                // throw new Error('adding course failed');
-            }
-
-        };
-
+        const inPlan = planCourses.includes(courseID);
 
 
         // Filter courses based on search query
@@ -130,9 +150,9 @@ const StudentAddCourseScreen = () => {
                 {isExpanded && (
                     <TouchableOpacity
                         style={styles.addButton}
-                        onPress={() => handleAdd(courseID)}
+                        onPress={() => togglePlanCourse(courseID)}
                     >
-                        <Text style={styles.addButtonText}>Add Course </Text>
+                        <Text style={[styles.addButtonText, inPlan && styles.removeButtonText]}>{inPlan ? 'Remove from Plan' : 'Add to Plan'}</Text>
                     </TouchableOpacity>
 
                 )}
@@ -164,11 +184,20 @@ const StudentAddCourseScreen = () => {
                     <Text style={styles.sortButtonText}>Sort by Course ID ({sortOrder.toUpperCase()})</Text>
                 </TouchableOpacity>
             </View>
+            {latestPlan && (
+                <View style={styles.planStatus}>
+                    <Text style={styles.planStatusText}>Last plan: {latestPlan.courses.join(', ')} ({latestPlan.credits} credits)</Text>
+                    <Text style={styles.planStatusText}>Status: {latestPlan.status}{latestPlan.remarks ? ` - ${latestPlan.remarks}` : ''}</Text>
+                </View>
+            )}
             <FlatList
                 data={courses}
                 keyExtractor={(item) => item.courseID}
                 renderItem={renderCourseItem}
             />
+            <TouchableOpacity style={styles.submitPlanButton} onPress={handleSubmitPlan}>
+                <Text style={styles.submitPlanButtonText}>Submit Course Plan ({planCourses.length})</Text>
+            </TouchableOpacity>
             <View style={styles.bottomBar}>
                 <Button
                     style={styles.bottomBarButton}
@@ -250,6 +279,31 @@ const styles = StyleSheet.create({
         fontWeight: 'bold',
         textAlign: 'center',
     },
+    removeButtonText: {
+        color: 'red',
+    },
+    planStatus: {
+        backgroundColor: '#EDE7F6',
+        padding: 10,
+        borderRadius: 5,
+        marginBottom: 15,
+    },
+    planStatusText: {
+        color: '#4A148C',
+        fontSize: 14,
+    },
+    submitPlanButton: {
+        backgroundColor: '#7E57C2',
+        padding: 12,
+        borderRadius: 10,
+        marginVertical: 10,
+    },
+    submitPlanButtonText: {
+        color: '#FFFFFF',
+        fontWeight: 'bold',
+        fontSize: 16,
+        textAlign: 'center',
+    },
     bottomBar: {
         // position: 'absolute',
         bottom: 0,
@@ -0,0 +1,272 @@
+// Command import bulk-imports faculty, courses and students from CSV files through the Fabric Gateway.
+//
+//	go run ./cmd/import -type courses -file courses.csv
+//
+// Every row is validated before anything is submitted. Valid rows are submitted in batches, each batch as one
+// ImportRecords transaction. If the chaincode rejects a batch, its rows are submitted one at a time so that only the
+// rows at fault are left out. Rows that are not valid or are rejected are listed in a CSV report. The progress is saved
+// after every batch, so an interrupted import resumes where it stopped when it is run again.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+
+	"rest-api-go/web"
+)
+
+func main() {
+	kindName := flag.String("type", "", "kind of records in the file: faculty, courses or students")
+	file := flag.String("file", "", "CSV file to import, with a header row")
+	batchSize := flag.Int("batch", 50, "number of records submitted in one transaction")
+	progressPath := flag.String("progress", "", "file the progress is saved to (default <file>.progress)")
+	reportPath := flag.String("report", "", "file the rows that were not imported are listed in (default <file>.report.csv)")
+	dryRun := flag.Bool("dry-run", false, "validate the file and report the rows that are not valid, without importing")
+	restart := flag.Bool("restart", false, "import the file from the start, ignoring the saved progress")
+	cryptoPath := flag.String("crypto", "", "crypto material of the organization, such as test-network/organizations/peerOrganizations/org1.example.com")
+	user := flag.String("user", "User1@org1.example.com", "user the transactions are submitted as, the chaincode only accepts imports from admins")
+	mspID := flag.String("msp", "Org1MSP", "MSP ID of the organization")
+	peerEndpoint := flag.String("peer", "localhost:7051", "endpoint of the Gateway peer")
+	gatewayPeer := flag.String("gateway-peer", "peer0.org1.example.com", "host name of the Gateway peer, as in its TLS certificate")
+	channel := flag.String("channel", "mychannel", "channel the chaincode is deployed on")
+	chaincode := flag.String("chaincode", "basic", "name of the chaincode")
+	flag.Parse()
+
+	kind, exists := recordKinds[*kindName]
+	if !exists || *file == "" {
+		kindNames := make([]string, 0, len(recordKinds))
+		for name := range recordKinds {
+			kindNames = append(kindNames, name)
+		}
+		sort.Strings(kindNames)
+		fmt.Fprintf(os.Stderr, "usage: import -type %s -file <file.csv>\n", strings.Join(kindNames, "|"))
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	if *batchSize <= 0 {
+		log.Fatalf("batch size %d is not valid", *batchSize)
+	}
+	if *progressPath == "" {
+		*progressPath = *file + ".progress"
+	}
+	if *reportPath == "" {
+		*reportPath = *file + ".report.csv"
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatalf("failed to read %s: %v", *file, err)
+	}
+	records, err := readRecords(kind, data)
+	if err != nil {
+		log.Fatalf("failed to parse %s: %v", *file, err)
+	}
+
+	if *dryRun {
+		validate(records, *reportPath)
+		return
+	}
+	if *cryptoPath == "" {
+		log.Fatalf("-crypto is required to import, give the directory of the crypto material of the organization")
+	}
+
+	// Resume from the saved progress, unless the file changed since
+	current := &progress{File: *file, Checksum: checksum(data)}
+	saved, err := loadProgress(*progressPath)
+	if err != nil {
+		log.Fatalf("failed to read the progress in %s: %v", *progressPath, err)
+	}
+	resume := saved != nil && !*restart
+	if resume {
+		if saved.Checksum != current.Checksum {
+			log.Fatalf("%s changed since the progress in %s was saved, run with -restart to import it from the start", *file, *progressPath)
+		}
+		current = saved
+		if current.Processed >= len(records) {
+			fmt.Printf("%s was already imported: %d imported, %d rejected, %d not valid\n", *file, current.Imported, current.Rejected, current.Invalid)
+			return
+		}
+		fmt.Printf("Resuming %s after %d of %d rows\n", *file, current.Processed, len(records))
+	}
+
+	rejectedReport, err := openReport(*reportPath, resume)
+	if err != nil {
+		log.Fatalf("failed to open the report %s: %v", *reportPath, err)
+	}
+	defer rejectedReport.close()
+
+	orgSetup, err := web.Connect(web.OrgSetup{
+		OrgName:      strings.TrimSuffix(*mspID, "MSP"),
+		MSPID:        *mspID,
+		CertPath:     *cryptoPath + "/users/" + *user + "/msp/signcerts/" + *user + "-cert.pem",
+		KeyPath:      *cryptoPath + "/users/" + *user + "/msp/keystore/",
+		TLSCertPath:  *cryptoPath + "/peers/" + *gatewayPeer + "/tls/ca.crt",
+		PeerEndpoint: *peerEndpoint,
+		GatewayPeer:  *gatewayPeer,
+	})
+	if err != nil {
+		log.Fatalf("failed to connect to the gateway: %v", err)
+	}
+	defer orgSetup.Gateway.Close()
+	contract := orgSetup.Gateway.GetNetwork(*channel).GetContract(*chaincode)
+
+	imp := importer{
+		kind:         kind,
+		records:      records,
+		contract:     contract,
+		progress:     current,
+		progressPath: *progressPath,
+		report:       rejectedReport,
+	}
+	for imp.progress.Processed < len(records) {
+		if err := imp.importBatch(*batchSize); err != nil {
+			log.Fatalf("import stopped at line %d: %v\nrun the same command again to resume", records[imp.progress.Processed].line, err)
+		}
+	}
+
+	fmt.Printf("Imported %d of %d rows of %s, %d rejected, %d not valid\n", current.Imported, len(records), *file, current.Rejected, current.Invalid)
+	if current.Rejected+current.Invalid > 0 {
+		fmt.Printf("The rows that were not imported are listed in %s\n", *reportPath)
+	}
+}
+
+// importer submits the records of a file in batches and saves the progress after each
+type importer struct {
+	kind         recordKind
+	records      []record
+	contract     *client.Contract
+	progress     *progress
+	progressPath string
+	report       *report
+}
+
+// importBatch submits the next batch of valid records and reports the rows before and among them that are not valid
+// The returned error means the import cannot go on, records the chaincode rejects are reported instead
+func (imp *importer) importBatch(batchSize int) error {
+	start := imp.progress.Processed
+	end := start
+	batch := []record{}
+	for end < len(imp.records) && len(batch) < batchSize {
+		if imp.records[end].problem == "" {
+			batch = append(batch, imp.records[end])
+		}
+		end++
+	}
+	firstLine, lastLine := imp.records[start].line, imp.records[end-1].line
+
+	if len(batch) > 0 {
+		reason, err := imp.submit(batch)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			// Find the records at fault by submitting them one at a time
+			fmt.Printf("Lines %d-%d: batch rejected (%s), importing its rows one at a time\n", firstLine, lastLine, reason)
+			return imp.importOneByOne(end)
+		}
+	}
+
+	for _, row := range imp.records[start:end] {
+		if row.problem != "" {
+			if err := imp.reportRow(row, "invalid", row.problem); err != nil {
+				return err
+			}
+		}
+	}
+	imp.progress.Imported += len(batch)
+	imp.progress.Processed = end
+	fmt.Printf("Lines %d-%d: imported %d records\n", firstLine, lastLine, len(batch))
+	return imp.progress.save(imp.progressPath)
+}
+
+// importOneByOne submits the records up to end one at a time, saving the progress after each
+func (imp *importer) importOneByOne(end int) error {
+	for index := imp.progress.Processed; index < end; index++ {
+		row := imp.records[index]
+		if row.problem != "" {
+			if err := imp.reportRow(row, "invalid", row.problem); err != nil {
+				return err
+			}
+		} else {
+			reason, err := imp.submit([]record{row})
+			if err != nil {
+				return err
+			}
+			if reason != "" {
+				fmt.Printf("Line %d: %s rejected: %s\n", row.line, row.id, reason)
+				if err := imp.reportRow(row, "rejected", reason); err != nil {
+					return err
+				}
+			} else {
+				imp.progress.Imported++
+			}
+		}
+
+		imp.progress.Processed = index + 1
+		if err := imp.progress.save(imp.progressPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// submit submits the records in one ImportRecords transaction
+// Returns the reason if the chaincode rejected them, and an error if the transaction failed for another reason
+func (imp *importer) submit(batch []record) (string, error) {
+	var records importRecords
+	for _, row := range batch {
+		imp.kind.add(&records, row.value)
+	}
+	recordsJSON, err := json.Marshal(records)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = imp.contract.SubmitTransaction("ImportRecords", string(recordsJSON))
+	if err != nil {
+		if reason, rejected := rejection(err); rejected {
+			return reason, nil
+		}
+		return "", err
+	}
+	return "", nil
+}
+
+// reportRow lists a row that was not imported in the report and counts it
+func (imp *importer) reportRow(row record, result string, reason string) error {
+	if result == "invalid" {
+		imp.progress.Invalid++
+	} else {
+		imp.progress.Rejected++
+	}
+	return imp.report.add(row, result, reason)
+}
+
+// validate reports the rows that are not valid without connecting to the network
+func validate(records []record, reportPath string) {
+	validationReport, err := openReport(reportPath, false)
+	if err != nil {
+		log.Fatalf("failed to open the report %s: %v", reportPath, err)
+	}
+	defer validationReport.close()
+
+	invalid := 0
+	for _, row := range records {
+		if row.problem == "" {
+			continue
+		}
+		fmt.Printf("Line %d: %s\n", row.line, row.problem)
+		if err := validationReport.add(row, "invalid", row.problem); err != nil {
+			log.Fatalf("failed to write the report %s: %v", reportPath, err)
+		}
+		invalid++
+	}
+	fmt.Printf("%d of %d rows are valid, %d are not\n", len(records)-invalid, len(records), invalid)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"strconv"
+
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+	"github.com/hyperledger/fabric-protos-go-apiv2/gateway"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// progress records how far the import of a file got, so that an interrupted import resumes where it stopped
+type progress struct {
+	File      string `json:"file"`
+	Checksum  string `json:"checksum"`  // SHA-256 of the file, an import does not resume into a file that changed
+	Processed int    `json:"processed"` // Data rows imported, rejected or reported as not valid, in file order
+	Imported  int    `json:"imported"`
+	Rejected  int    `json:"rejected"`
+	Invalid   int    `json:"invalid"`
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadProgress reads the progress saved at the path, nil if there is none
+func loadProgress(path string) (*progress, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved progress
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// save writes the progress to a temporary file and renames it, so that an interruption never leaves it half written
+func (p *progress) save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// report lists the rows that were not imported and why
+type report struct {
+	file   *os.File
+	writer *csv.Writer
+}
+
+// openReport opens the report at the path, appending to it when an import resumes
+func openReport(path string, resume bool) (*report, error) {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if resume {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+	file, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &report{file: file, writer: csv.NewWriter(file)}
+	if info, err := file.Stat(); err == nil && info.Size() == 0 {
+		r.writer.Write([]string{"line", "id", "result", "reason"})
+	}
+	return r, nil
+}
+
+// add reports a row that was not imported, result is invalid or rejected
+func (r *report) add(row record, result string, reason string) error {
+	r.writer.Write([]string{strconv.Itoa(row.line), row.id, result, reason})
+	r.writer.Flush()
+	return r.writer.Error()
+}
+
+func (r *report) close() error {
+	r.writer.Flush()
+	return r.file.Close()
+}
+
+// rejection returns the reason the chaincode gave for rejecting a transaction
+// Returns false if the transaction failed for another reason, such as the peer being unreachable, in which case the
+// records were not looked at and the import should stop rather than report them
+func rejection(err error) (string, bool) {
+	var endorseErr *client.EndorseError
+	if !errors.As(err, &endorseErr) {
+		return "", false
+	}
+
+	grpcStatus := status.Convert(err)
+	if grpcStatus.Code() == codes.Unavailable || grpcStatus.Code() == codes.DeadlineExceeded {
+		return "", false
+	}
+	for _, detail := range grpcStatus.Details() {
+		if errorDetail, ok := detail.(*gateway.ErrorDetail); ok {
+			return errorDetail.Message, true
+		}
+	}
+	return grpcStatus.Message(), true
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// importRecords is the batch of records submitted to ImportRecords, in the JSON form the chaincode reads
+type importRecords struct {
+	Faculty  []faculty `json:"faculty,omitempty"`
+	Courses  []course  `json:"courses,omitempty"`
+	Students []student `json:"students,omitempty"`
+}
+
+type faculty struct {
+	FacultyID    string `json:"facultyID"`
+	FacultyName  string `json:"facultyName"`
+	DepartmentID string `json:"department"`
+}
+
+type course struct {
+	CourseID     string `json:"courseID"`
+	CourseName   string `json:"name"`
+	Credits      int    `json:"credits"`
+	DepartmentID string `json:"department"`
+	FacultyID    string `json:"facultyID"`
+	Description  string `json:"description"`
+	AcademicYear int    `json:"academicYear"`
+	Semester     int    `json:"semester"`
+	MaxSeats     int    `json:"maxSeats"`
+}
+
+type student struct {
+	StudentID    string `json:"studentID"`
+	StudentName  string `json:"studentName"`
+	ProgramType  string `json:"programType"`
+	DepartmentID string `json:"department"`
+}
+
+// recordKind describes the CSV file of one kind of record and how its rows become records
+type recordKind struct {
+	columns []string // Columns the header must have, other columns are ignored
+	parse   func(row csvRow) (id string, value interface{}, err error)
+	add     func(records *importRecords, value interface{})
+}
+
+var recordKinds = map[string]recordKind{
+	"faculty": {
+		columns: []string{"facultyID", "facultyName", "departmentID"},
+		parse: func(row csvRow) (string, interface{}, error) {
+			value := faculty{
+				FacultyID:    row.get("facultyID"),
+				FacultyName:  row.get("facultyName"),
+				DepartmentID: row.get("departmentID"),
+			}
+			return value.FacultyID, value, row.required("facultyID", "facultyName", "departmentID")
+		},
+		add: func(records *importRecords, value interface{}) {
+			records.Faculty = append(records.Faculty, value.(faculty))
+		},
+	},
+	"courses": {
+		columns: []string{"courseID", "courseName", "credits", "departmentID", "facultyID", "description", "academicYear", "semester", "maxSeats"},
+		parse: func(row csvRow) (string, interface{}, error) {
+			value := course{
+				CourseID:     row.get("courseID"),
+				CourseName:   row.get("courseName"),
+				DepartmentID: row.get("departmentID"),
+				FacultyID:    row.get("facultyID"),
+				Description:  row.get("description"),
+			}
+			if err := row.required("courseID", "courseName", "departmentID", "facultyID"); err != nil {
+				return value.CourseID, value, err
+			}
+			var err error
+			if value.Credits, err = row.positiveInt("credits"); err != nil {
+				return value.CourseID, value, err
+			}
+			if value.AcademicYear, err = row.positiveInt("academicYear"); err != nil {
+				return value.CourseID, value, err
+			}
+			if value.Semester, err = row.positiveInt("semester"); err != nil {
+				return value.CourseID, value, err
+			}
+			if value.MaxSeats, err = row.positiveInt("maxSeats"); err != nil {
+				return value.CourseID, value, err
+			}
+			return value.CourseID, value, nil
+		},
+		add: func(records *importRecords, value interface{}) {
+			records.Courses = append(records.Courses, value.(course))
+		},
+	},
+	"students": {
+		columns: []string{"studentID", "studentName", "programType", "departmentID"},
+		parse: func(row csvRow) (string, interface{}, error) {
+			value := student{
+				StudentID:    row.get("studentID"),
+				StudentName:  row.get("studentName"),
+				ProgramType:  row.get("programType"),
+				DepartmentID: row.get("departmentID"),
+			}
+			return value.StudentID, value, row.required("studentID", "studentName", "programType", "departmentID")
+		},
+		add: func(records *importRecords, value interface{}) {
+			records.Students = append(records.Students, value.(student))
+		},
+	},
+}
+
+// record is a data row of the CSV file, with the reason it cannot be imported if it is not valid
+type record struct {
+	line    int
+	id      string
+	value   interface{}
+	problem string
+}
+
+// csvRow maps the columns of the header to the fields of a row
+type csvRow map[string]string
+
+func (row csvRow) get(column string) string {
+	return strings.TrimSpace(row[column])
+}
+
+// required returns an error naming the first of the columns that is empty
+func (row csvRow) required(columns ...string) error {
+	for _, column := range columns {
+		if row.get(column) == "" {
+			return fmt.Errorf("%s is empty", column)
+		}
+	}
+	return nil
+}
+
+func (row csvRow) positiveInt(column string) (int, error) {
+	value, err := strconv.Atoi(row.get(column))
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("%s %q is not a positive number", column, row.get(column))
+	}
+	return value, nil
+}
+
+// readRecords parses the rows of a CSV file of the given kind
+// Rows that are not valid, or repeat the ID of an earlier row, are returned with the problem set so that they are reported
+// rather than submitted. An error is returned only if the file itself cannot be read
+func readRecords(kind recordKind, data []byte) ([]record, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the header: %w", err)
+	}
+	for index := range header {
+		header[index] = strings.TrimSpace(header[index])
+	}
+	for _, column := range kind.columns {
+		if !contains(header, column) {
+			return nil, fmt.Errorf("the header has no %s column, expected %s", column, strings.Join(kind.columns, ","))
+		}
+	}
+
+	records := []record{}
+	lineOfID := make(map[string]int)
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, err
+		}
+		if err != nil {
+			records = append(records, record{line: line, problem: fmt.Sprintf("the row has %d fields, the header has %d", len(fields), len(header))})
+			continue
+		}
+
+		row := make(csvRow)
+		for index, column := range header {
+			row[column] = fields[index]
+		}
+		id, value, err := kind.parse(row)
+		current := record{line: line, id: id, value: value}
+		if err != nil {
+			current.problem = err.Error()
+		} else if firstLine, exists := lineOfID[id]; exists {
+			current.problem = fmt.Sprintf("%s is already listed on line %d", id, firstLine)
+		} else {
+			lineOfID[id] = line
+		}
+		records = append(records, current)
+	}
+
+	return records, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
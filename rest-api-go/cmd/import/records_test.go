@@ -0,0 +1,73 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadRecords(t *testing.T) {
+	data := "courseID, courseName,credits,departmentID,facultyID,description,academicYear,semester,maxSeats,notes\n" +
+		"CS101,Programming,4,CSE,F1,,2024,1,60,first\n" +
+		"CS102,Data Structures,four,CSE,F1,,2024,1,60,\n" +
+		"CS103,,4,CSE,F1,,2024,1,60,\n" +
+		"CS101,Programming,4,CSE,F1,,2024,1,60,\n" +
+		"CS104,Algorithms\n"
+	records, err := readRecords(recordKinds["courses"], []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		line    int
+		id      string
+		problem string
+	}{
+		{2, "CS101", ""},
+		{3, "CS102", `credits "four" is not a positive number`},
+		{4, "CS103", "courseName is empty"},
+		{5, "CS101", "CS101 is already listed on line 2"},
+		{6, "", "the row has 2 fields, the header has 10"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("read %d records, want %d: %+v", len(records), len(want), records)
+	}
+	for index, record := range records {
+		if record.line != want[index].line || record.id != want[index].id || record.problem != want[index].problem {
+			t.Errorf("record %d is %+v, want %+v", index, record, want[index])
+		}
+	}
+	if course := records[0].value.(course); course.Credits != 4 || course.MaxSeats != 60 || course.CourseName != "Programming" {
+		t.Errorf("course is %+v", course)
+	}
+}
+
+func TestReadRecordsNeedsTheColumns(t *testing.T) {
+	_, err := readRecords(recordKinds["students"], []byte("studentID,studentName,departmentID\nS1,Asha,CSE\n"))
+	if err == nil || !strings.Contains(err.Error(), "the header has no programType column") {
+		t.Fatalf("readRecords without a column returned %v", err)
+	}
+	if _, err := readRecords(recordKinds["students"], nil); err == nil {
+		t.Fatal("readRecords of an empty file succeeded")
+	}
+}
+
+func TestProgress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "students.progress.json")
+	saved, err := loadProgress(path)
+	if err != nil || saved != nil {
+		t.Fatalf("progress before the import is %+v, %v", saved, err)
+	}
+
+	want := progress{File: "students.csv", Checksum: checksum([]byte("studentID\n")), Processed: 3, Imported: 2, Invalid: 1}
+	if err := want.save(path); err != nil {
+		t.Fatal(err)
+	}
+	saved, err = loadProgress(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *saved != want {
+		t.Fatalf("loaded progress %+v, want %+v", *saved, want)
+	}
+}
@@ -2,6 +2,9 @@ package web
 
 import (
 	"context"
+	"crypto"
+	"crypto/x509"
+	"errors"
 	"log"
 	"net/http"
 
@@ -22,10 +25,26 @@ type OrgSetup struct {
 	PeerEndpoint string
 	GatewayPeer  string
 	Gateway      client.Gateway
+	Certificate  *x509.Certificate  // Organization's certificate, included with signed documents
+	PrivateKey   crypto.PrivateKey  // Organization's key, used to sign documents such as transcripts
+	Storage      CertificateStorage // Storage of certificate files
+	URLSigner    *URLSigner         // Signs short-lived certificate download URLs
+	CACertPath   string             // Directory of the certificates of the organization's CAs
+	CallerCAs    *x509.CertPool     // CAs that must issue the enrollment certificates of callers and the certificates of signed documents
 }
 
 var DOMAIN_NAME string = "measured-wasp-terminally.ngrok-free.app"
 
+// requestChaincode returns the channel and chaincode named by the channelid and chaincodeid parameters of a request.
+func requestChaincode(r *http.Request) (string, string, error) {
+	channelID := r.FormValue("channelid")       // channel name -> mychannel
+	chainCodeName := r.FormValue("chaincodeid") // chaincode name -> basic
+	if channelID == "" || chainCodeName == "" {
+		return "", "", errors.New("channelid and chaincodeid are required")
+	}
+	return channelID, chainCodeName, nil
+}
+
 func run(ctx context.Context, mux *http.ServeMux) error {
 	listener, err := ngrok.Listen(ctx,
 		config.HTTPEndpoint(
@@ -104,6 +123,133 @@ func Serve(setups OrgSetup) {
 	mux.HandleFunc("/GetExtracurricularActivity", setups.GetExtracurricularActivity)
 
 	mux.HandleFunc("/GetCertificateForActivityAndStudent", setups.GetCertificateForActivityAndStudent)
+	mux.HandleFunc("/VerifyCertificateFile", setups.VerifyCertificateFile)
+	mux.HandleFunc("/UploadCertificate", setups.UploadCertificate)
+	mux.HandleFunc("/CertificateDownloadURL", setups.CertificateDownloadURL)
+	mux.HandleFunc("/certificates/download", setups.DownloadCertificate)
+
+	//graduation
+	mux.HandleFunc("/SetProgramGraduationRequirements", setups.SetProgramGraduationRequirements)
+	mux.HandleFunc("/RunDegreeAudit", setups.RunDegreeAudit)
+	mux.HandleFunc("/ConferDegree", setups.ConferDegree)
+
+	//curriculum
+	mux.HandleFunc("/SetCurriculum", setups.SetCurriculum)
+	mux.HandleFunc("/GetCurriculum", setups.GetCurriculum)
+	mux.HandleFunc("/GetCurriculumProgress", setups.GetCurriculumProgress)
+
+	//transcript
+	mux.HandleFunc("/GetTranscript", setups.GetTranscript)
+	mux.HandleFunc("/IssueTranscript", setups.IssueTranscript)
+
+	//verification
+	mux.HandleFunc("/verify", setups.Verify)
+
+	//verifiable credentials
+	mux.HandleFunc("/IssueVerifiableCredential", setups.IssueVerifiableCredential)
+	mux.HandleFunc("/credentials/status/revocation", setups.RevocationStatusList)
+	mux.HandleFunc("/.well-known/did.json", setups.DIDDocument)
+	mux.HandleFunc("/RevokeCredential", setups.RevokeCredential)
+	mux.HandleFunc("/GetCredentialStatusList", setups.GetCredentialStatusList)
+
+	//certificate review
+	mux.HandleFunc("/ReviewCertificate", setups.ReviewCertificate)
+	mux.HandleFunc("/GetCertificateSubmission", setups.GetCertificateSubmission)
+	mux.HandleFunc("/GetApprovedCertificates", setups.GetApprovedCertificates)
+	mux.HandleFunc("/GetPendingCertificatesForFaculty", setups.GetPendingCertificatesForFaculty)
+
+	//attendance
+	mux.HandleFunc("/MarkActivityAttendance", setups.MarkActivityAttendance)
+	mux.HandleFunc("/GetActivityParticipants", setups.GetActivityParticipants)
+	mux.HandleFunc("/GetStudentActivityProfile", setups.GetStudentActivityProfile)
+
+	//rating
+	mux.HandleFunc("/SetRatingWeights", setups.SetRatingWeights)
+	mux.HandleFunc("/GetRatingWeights", setups.GetRatingWeights)
+	mux.HandleFunc("/GetStudentRating", setups.GetStudentRating)
+
+	//activity lifecycle
+	mux.HandleFunc("/SetActivitySchedule", setups.SetActivitySchedule)
+	mux.HandleFunc("/SetActivityStatus", setups.SetActivityStatus)
+	mux.HandleFunc("/CancelExtracurricularActivity", setups.CancelExtracurricularActivity)
+	mux.HandleFunc("/WithdrawFromExtracurricularActivity", setups.WithdrawFromExtracurricularActivity)
+	mux.HandleFunc("/ActivityNotifications", setups.ActivityNotifications)
+
+	//activity rosters
+	mux.HandleFunc("/RebuildActivityRosters", setups.RebuildActivityRosters)
+	mux.HandleFunc("/GetActivityRoster", setups.GetActivityRoster)
+
+	//clubs
+	mux.HandleFunc("/AddClub", setups.AddClub)
+	mux.HandleFunc("/UpdateClubMembers", setups.UpdateClubMembers)
+	mux.HandleFunc("/SetActivityClassification", setups.SetActivityClassification)
+	mux.HandleFunc("/GetClub", setups.GetClub)
+	mux.HandleFunc("/GetAllClubs", setups.GetAllClubs)
+	mux.HandleFunc("/FilterExtracurricularActivities", setups.FilterExtracurricularActivities)
+	mux.HandleFunc("/GetActivitiesByClub", setups.GetActivitiesByClub)
+	mux.HandleFunc("/GetActivitiesByCategory", setups.GetActivitiesByCategory)
+	mux.HandleFunc("/GetActivitiesByTerm", setups.GetActivitiesByTerm)
+
+	//student profiles
+	mux.HandleFunc("/SetStudentProfile", setups.SetStudentProfile)
+	mux.HandleFunc("/GetStudentProfile", setups.GetStudentProfile)
+	mux.HandleFunc("/VerifyStudentProfile", setups.VerifyStudentProfile)
+
+	//private results
+	mux.HandleFunc("/AddPrivateResult", setups.AddPrivateResult)
+	mux.HandleFunc("/UpdatePrivateGrade", setups.UpdatePrivateGrade)
+	mux.HandleFunc("/EnablePrivateResults", setups.EnablePrivateResults)
+	mux.HandleFunc("/VerifyPrivateResult", setups.VerifyPrivateResult)
+
+	//student status
+	mux.HandleFunc("/ChangeStudentStatus", setups.ChangeStudentStatus)
+	mux.HandleFunc("/GetStudentStatus", setups.GetStudentStatus)
+	mux.HandleFunc("/GetStudentsByStatus", setups.GetStudentsByStatus)
+
+	//transfers
+	mux.HandleFunc("/TransferStudent", setups.TransferStudent)
+	mux.HandleFunc("/GetTransferHistory", setups.GetTransferHistory)
+
+	//dependencies
+	mux.HandleFunc("/ArchiveEntity", setups.ArchiveEntity)
+	mux.HandleFunc("/GetDependents", setups.GetDependents)
+
+	//updates
+	mux.HandleFunc("/UpdateCourse", setups.UpdateCourse)
+	mux.HandleFunc("/UpdateFaculty", setups.UpdateFaculty)
+	mux.HandleFunc("/UpdateDepartment", setups.UpdateDepartment)
+	mux.HandleFunc("/UpdateProgram", setups.UpdateProgram)
+	mux.HandleFunc("/UpdateExtracurricularActivity", setups.UpdateExtracurricularActivity)
+
+	//appointments
+	mux.HandleFunc("/SetFacultyDesignations", setups.SetFacultyDesignations)
+	mux.HandleFunc("/SetJointAppointments", setups.SetJointAppointments)
+	mux.HandleFunc("/SetDepartmentHead", setups.SetDepartmentHead)
+	mux.HandleFunc("/SetCourseCoInstructors", setups.SetCourseCoInstructors)
+	mux.HandleFunc("/GetDepartmentFaculty", setups.GetDepartmentFaculty)
+	mux.HandleFunc("/GetFacultyByDesignation", setups.GetFacultyByDesignation)
+
+	//advisors
+	mux.HandleFunc("/AssignAdvisor", setups.AssignAdvisor)
+	mux.HandleFunc("/SubmitCoursePlan", setups.SubmitCoursePlan)
+	mux.HandleFunc("/ReviewCoursePlan", setups.ReviewCoursePlan)
+	mux.HandleFunc("/GetAdvisees", setups.GetAdvisees)
+	mux.HandleFunc("/GetCoursePlans", setups.GetCoursePlans)
+	mux.HandleFunc("/GetPendingCoursePlans", setups.GetPendingCoursePlans)
+
+	//credits
+	mux.HandleFunc("/RecomputeEnrollment", setups.RecomputeEnrollment)
+	mux.HandleFunc("/GetCreditSummary", setups.GetCreditSummary)
+
+	//migration
+	mux.HandleFunc("/MigrateLedgerKeys", setups.MigrateLedgerKeys)
+
+	//schema
+	mux.HandleFunc("/MigrateSchema", setups.MigrateSchema)
+	mux.HandleFunc("/GetSchemaStatus", setups.GetSchemaStatus)
+
+	//import
+	mux.HandleFunc("/ImportRecords", setups.ImportRecords)
 
 	// fmt.Println("Listening (http://localhost:3000/)...")
 	// if err := http.ListenAndServe(":3000", nil); err != nil {
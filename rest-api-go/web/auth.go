@@ -0,0 +1,138 @@
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers a caller authenticates a request with, using its Fabric enrollment certificate and key
+const (
+	certificateHeader = "X-Fabric-Certificate" // Base64 of the PEM enrollment certificate
+	signatureHeader   = "X-Fabric-Signature"   // Base64 of the ECDSA signature of the request
+	timestampHeader   = "X-Fabric-Timestamp"   // Unix time the request was signed at
+)
+
+// maxRequestAge is how long a signed request is accepted for after it was signed, and how far the caller's clock may be ahead
+const maxRequestAge = 5 * time.Minute
+
+// Object identifier of the extension Fabric CA writes the attributes of an enrollment certificate in
+var fabricAttributesOID = asn1.ObjectIdentifier{1, 2, 3, 4, 5, 6, 7, 8, 1}
+
+// callerIdentity is the identity of an authenticated API caller.
+type callerIdentity struct {
+	Certificate *x509.Certificate
+	Attributes  map[string]string // Attributes of the enrollment certificate, such as studentID and facultyID
+}
+
+// isAdmin reports whether the caller's enrollment certificate has the admin role.
+func (caller *callerIdentity) isAdmin() bool {
+	return caller.Attributes["role"] == "admin"
+}
+
+// loadCallerCAs reads the certificates of the CAs that issue the enrollment certificates API callers sign requests with.
+func loadCallerCAs(dirPath string) (*x509.CertPool, error) {
+	files, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate directory: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		certificate, err := loadCertificate(path.Join(dirPath, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		pool.AddCert(certificate)
+	}
+
+	return pool, nil
+}
+
+// authenticateCaller checks the enrollment certificate and signature a request is sent with.
+// The caller signs the SHA-256 of the request path, the timestamp and the args, each on a line of its own.
+func (setup OrgSetup) authenticateCaller(r *http.Request, args []string) (*callerIdentity, error) {
+	if setup.CallerCAs == nil {
+		return nil, errors.New("caller authentication is not configured")
+	}
+
+	certificatePEM, err := base64.StdEncoding.DecodeString(r.Header.Get(certificateHeader))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s header", certificateHeader)
+	}
+	block, _ := pem.Decode(certificatePEM)
+	if block == nil {
+		return nil, fmt.Errorf("%s header has no certificate", certificateHeader)
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate: %w", err)
+	}
+	_, err = certificate.Verify(x509.VerifyOptions{Roots: setup.CallerCAs, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+	if err != nil {
+		return nil, fmt.Errorf("certificate is not issued by the organization: %w", err)
+	}
+
+	timestampValue := r.Header.Get(timestampHeader)
+	timestamp, err := strconv.ParseInt(timestampValue, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s header", timestampHeader)
+	}
+	age := time.Since(time.Unix(timestamp, 0))
+	if age > maxRequestAge || age < -maxRequestAge {
+		return nil, errors.New("request signature has expired")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(r.Header.Get(signatureHeader))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s header", signatureHeader)
+	}
+	publicKey, ok := certificate.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("certificate does not have an ECDSA key")
+	}
+	digest := sha256.Sum256([]byte(r.URL.Path + "\n" + timestampValue + "\n" + strings.Join(args, "\n")))
+	if !ecdsa.VerifyASN1(publicKey, digest[:], signature) {
+		return nil, errors.New("invalid request signature")
+	}
+
+	attributes, err := certificateAttributes(certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &callerIdentity{Certificate: certificate, Attributes: attributes}, nil
+}
+
+// certificateAttributes reads the attributes Fabric CA wrote in an enrollment certificate.
+func certificateAttributes(certificate *x509.Certificate) (map[string]string, error) {
+	for _, extension := range certificate.Extensions {
+		if !extension.Id.Equal(fabricAttributesOID) {
+			continue
+		}
+		var attributes struct {
+			Attrs map[string]string `json:"attrs"`
+		}
+		if err := json.Unmarshal(extension.Value, &attributes); err != nil {
+			return nil, fmt.Errorf("invalid certificate attributes: %w", err)
+		}
+		return attributes.Attrs, nil
+	}
+
+	return map[string]string{}, nil
+}
@@ -0,0 +1,171 @@
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCA issues enrollment certificates for API callers.
+type testCA struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+}
+
+// testCaller is an enrolled API caller.
+type testCaller struct {
+	certificatePEM []byte
+	key            *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca.org1.example.com"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{certificate: certificate, key: key}
+}
+
+// enroll issues an enrollment certificate with Fabric CA attributes.
+func (ca *testCA) enroll(t *testing.T, attributes map[string]string) *testCaller {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attributesJSON, err := json.Marshal(map[string]interface{}{"attrs": attributes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		Subject:         pkix.Name{CommonName: "user1"},
+		NotBefore:       time.Now().Add(-time.Hour),
+		NotAfter:        time.Now().Add(time.Hour),
+		ExtraExtensions: []pkix.Extension{{Id: fabricAttributesOID, Value: attributesJSON}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCaller{certificatePEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), key: key}
+}
+
+// setup returns an organization setup that authenticates the callers enrolled by the CA.
+func (ca *testCA) setup() OrgSetup {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.certificate)
+	return OrgSetup{CallerCAs: pool}
+}
+
+// sign signs a request the way API callers do.
+func (caller *testCaller) sign(t *testing.T, path string, timestamp string, args []string) string {
+	t.Helper()
+	digest := sha256.Sum256([]byte(path + "\n" + timestamp + "\n" + strings.Join(args, "\n")))
+	signature, err := ecdsa.SignASN1(rand.Reader, caller.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(signature)
+}
+
+func TestAuthenticateCaller(t *testing.T) {
+	ca := newTestCA(t)
+	setup := ca.setup()
+	caller := ca.enroll(t, map[string]string{"studentID": "S1"})
+	args := []string{"S1", "A1"}
+
+	request := httptest.NewRequest(http.MethodGet, "/certificates/url?args=S1&args=A1", nil)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set(certificateHeader, base64.StdEncoding.EncodeToString(caller.certificatePEM))
+	request.Header.Set(timestampHeader, timestamp)
+	request.Header.Set(signatureHeader, caller.sign(t, "/certificates/url", timestamp, args))
+
+	identity, err := setup.authenticateCaller(request, args)
+	if err != nil {
+		t.Fatalf("signed request is not authenticated: %v", err)
+	}
+	if identity.Attributes["studentID"] != "S1" {
+		t.Fatalf("caller attributes are %v", identity.Attributes)
+	}
+
+	// The signature covers the args
+	if _, err := setup.authenticateCaller(request, []string{"S2", "A1"}); err == nil {
+		t.Error("request is authenticated for other args")
+	}
+
+	// The signature covers the timestamp, which must be recent
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	request.Header.Set(timestampHeader, old)
+	if _, err := setup.authenticateCaller(request, args); err == nil {
+		t.Error("request is authenticated with another timestamp")
+	}
+	request.Header.Set(signatureHeader, caller.sign(t, "/certificates/url", old, args))
+	if _, err := setup.authenticateCaller(request, args); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("old request is authenticated with %v", err)
+	}
+
+	// Callers enrolled by another CA are not accepted
+	stranger := newTestCA(t).enroll(t, map[string]string{"studentID": "S1"})
+	request.Header.Set(certificateHeader, base64.StdEncoding.EncodeToString(stranger.certificatePEM))
+	request.Header.Set(timestampHeader, timestamp)
+	request.Header.Set(signatureHeader, stranger.sign(t, "/certificates/url", timestamp, args))
+	if _, err := setup.authenticateCaller(request, args); err == nil {
+		t.Error("caller enrolled by another CA is authenticated")
+	}
+
+	if _, err := (OrgSetup{}).authenticateCaller(request, args); err == nil {
+		t.Error("request is authenticated without caller CAs")
+	}
+}
+
+func TestCertificateAttributes(t *testing.T) {
+	ca := newTestCA(t)
+	caller := ca.enroll(t, map[string]string{"facultyID": "F1", "role": "faculty"})
+	block, _ := pem.Decode(caller.certificatePEM)
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attributes, err := certificateAttributes(certificate)
+	if err != nil || attributes["facultyID"] != "F1" || attributes["role"] != "faculty" {
+		t.Fatalf("certificateAttributes returned %v, %v", attributes, err)
+	}
+
+	// A certificate without attributes has none
+	attributes, err = certificateAttributes(ca.certificate)
+	if err != nil || len(attributes) != 0 {
+		t.Fatalf("certificateAttributes of the CA certificate returned %v, %v", attributes, err)
+	}
+}
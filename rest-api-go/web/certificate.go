@@ -0,0 +1,407 @@
+package web
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+)
+
+// maxCertificateFile is the largest certificate file that is hashed or verified
+const maxCertificateFile = 20 << 20
+
+// certificateClient calls the certificate storage services over HTTP
+var certificateClient = &http.Client{Timeout: 30 * time.Second}
+
+// ledgerCertificate mirrors the chaincode's Certificate.
+type ledgerCertificate struct {
+	ActivityID      string `json:"activityID"`
+	Key             string `json:"key"`
+	SHA256          string `json:"sha256"`
+	Size            int    `json:"size"`
+	MimeType        string `json:"mimeType"`
+	IssuerFacultyID string `json:"issuerFacultyID"`
+	UploadedAt      string `json:"uploadedAt"`
+	Status          string `json:"status"`
+	ReviewedBy      string `json:"reviewedBy"`
+	ReviewedAt      string `json:"reviewedAt"`
+}
+
+// CertificateFileCheck is the result of checking a certificate file against the digest recorded on the ledger.
+type CertificateFileCheck struct {
+	StudentID       string `json:"studentID"`
+	ActivityID      string `json:"activityID"`
+	Key             string `json:"key"`
+	Source          string `json:"source"` // upload or storage
+	ExpectedSHA256  string `json:"expectedSHA256"`
+	ActualSHA256    string `json:"actualSHA256"`
+	ExpectedSize    int    `json:"expectedSize"`
+	ActualSize      int    `json:"actualSize"`
+	MimeType        string `json:"mimeType"`
+	IssuerFacultyID string `json:"issuerFacultyID"`
+	UploadedAt      string `json:"uploadedAt"`
+	Matches         bool   `json:"matches"`
+}
+
+// certificateFileArgs completes the arguments of AddCertificateForStudent with the digest, size and MIME type of the file.
+// Accepts studentID, activityID, key and an optional facultyID, in which case the file is read from the certificate storage,
+// or all seven chaincode arguments when the caller has computed the file details itself.
+func (setup OrgSetup) certificateFileArgs(ctx context.Context, argsArray []string) ([]string, error) {
+	switch len(argsArray) {
+	case 7:
+		return argsArray, nil
+	case 3, 4:
+		data, err := setup.Storage.Get(ctx, argsArray[2])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read certificate %s: %w", argsArray[2], err)
+		}
+		facultyID := ""
+		if len(argsArray) == 4 {
+			facultyID = argsArray[3]
+		}
+		return append(argsArray[:3:3], certificateFileDetails(data, "", facultyID)...), nil
+	default:
+		return nil, errors.New("Invalid number of arguments")
+	}
+}
+
+// certificateFileDetails returns the digest, size, MIME type and issuing faculty arguments of AddCertificateForStudent.
+func certificateFileDetails(data []byte, mimeType string, facultyID string) []string {
+	if mimeType == "" || mimeType == "application/octet-stream" {
+		mimeType = http.DetectContentType(data)
+	}
+	hash := sha256.Sum256(data)
+	return []string{hex.EncodeToString(hash[:]), strconv.Itoa(len(data)), mimeType, facultyID}
+}
+
+// readCertificateFile reads a certificate file, rejecting files over the size limit.
+func readCertificateFile(reader io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, maxCertificateFile+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxCertificateFile {
+		return nil, fmt.Errorf("certificate file is larger than %d bytes", maxCertificateFile)
+	}
+
+	return data, nil
+}
+
+// VerifyCertificateFile checks a certificate file against the digest recorded on the ledger.
+// args=<studentID>&args=<activityID>; the file is taken from the multipart field file if present,
+// otherwise it is read again from the certificate storage using the recorded key.
+func (setup OrgSetup) VerifyCertificateFile(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received VerifyCertificateFile request")
+	if err := r.ParseMultipartForm(maxCertificateFile); err != nil && err != http.ErrNotMultipart {
+		http.Error(w, fmt.Sprintf("ParseForm() err: %s", err), http.StatusBadRequest)
+		return
+	}
+	channelID, chainCodeName, err := requestChaincode(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	argsArray := r.Form["args"]
+	if len(argsArray) != 2 {
+		http.Error(w, "Invalid number of arguments", http.StatusBadRequest)
+		return
+	}
+
+	contract := setup.Gateway.GetNetwork(channelID).GetContract(chainCodeName)
+	certificateJSON, err := contract.EvaluateTransaction("GetCertificateForActivityAndStudent", argsArray[0], argsArray[1])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusNotFound)
+		return
+	}
+	var certificate ledgerCertificate
+	if err := json.Unmarshal(certificateJSON, &certificate); err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	var data []byte
+	source := "storage"
+	file, _, err := r.FormFile("file")
+	if err == nil {
+		defer file.Close()
+		source = "upload"
+		data, err = readCertificateFile(file)
+	} else {
+		data, err = setup.Storage.Get(r.Context(), certificate.Key)
+	}
+	if err != nil && source == "upload" {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusBadGateway)
+		return
+	}
+
+	hash := sha256.Sum256(data)
+	check := CertificateFileCheck{
+		StudentID:       argsArray[0],
+		ActivityID:      certificate.ActivityID,
+		Key:             certificate.Key,
+		Source:          source,
+		ExpectedSHA256:  certificate.SHA256,
+		ActualSHA256:    hex.EncodeToString(hash[:]),
+		ExpectedSize:    certificate.Size,
+		ActualSize:      len(data),
+		MimeType:        certificate.MimeType,
+		IssuerFacultyID: certificate.IssuerFacultyID,
+		UploadedAt:      certificate.UploadedAt,
+	}
+	check.Matches = certificate.SHA256 != "" && check.ActualSHA256 == check.ExpectedSHA256 && check.ActualSize == check.ExpectedSize
+
+	checkJSON, err := json.Marshal(check)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(checkJSON)
+}
+
+// CertificateUpload is the response of a certificate upload.
+type CertificateUpload struct {
+	Key         string    `json:"key"`
+	SHA256      string    `json:"sha256"`
+	Size        int       `json:"size"`
+	MimeType    string    `json:"mimeType"`
+	DownloadURL string    `json:"downloadURL"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// UploadCertificate stores a certificate file and records its key and digest with AddCertificateForStudent.
+// Multipart form with args=<studentID>, args=<activityID>, an optional args=<facultyID> and the file in the file field.
+// The request must be signed by the student, an admin or the faculty in charge of the activity.
+func (setup OrgSetup) UploadCertificate(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received UploadCertificate request")
+	r.Body = http.MaxBytesReader(w, r.Body, maxCertificateFile+1<<20)
+	if err := r.ParseMultipartForm(maxCertificateFile); err != nil {
+		http.Error(w, fmt.Sprintf("ParseForm() err: %s", err), http.StatusBadRequest)
+		return
+	}
+	channelID, chainCodeName, err := requestChaincode(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	argsArray := r.Form["args"]
+	if len(argsArray) != 2 && len(argsArray) != 3 {
+		http.Error(w, "Invalid number of arguments", http.StatusBadRequest)
+		return
+	}
+	caller, err := setup.authenticateCaller(r, argsArray)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusUnauthorized)
+		return
+	}
+
+	contract := setup.Gateway.GetNetwork(channelID).GetContract(chainCodeName)
+	allowed, err := canSubmitCertificate(contract, caller, argsArray[0], argsArray[1])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusNotFound)
+		return
+	}
+	if !allowed {
+		http.Error(w, "Error: only the student, an admin and the faculty in charge of the activity can upload the certificate", http.StatusForbidden)
+		return
+	}
+	facultyID := ""
+	if len(argsArray) == 3 {
+		facultyID = argsArray[2]
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: file upload is required: %s", err), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	data, err := readCertificateFile(file)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusBadRequest)
+		return
+	}
+	if len(data) == 0 {
+		http.Error(w, "Error: certificate file is empty", http.StatusBadRequest)
+		return
+	}
+
+	details := certificateFileDetails(data, header.Header.Get("Content-Type"), facultyID)
+	key, err := certificateKey(argsArray[0], argsArray[1], header.Filename)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+	key, err = setup.Storage.Put(r.Context(), key, data, details[2])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: failed to store certificate: %s", err), http.StatusBadGateway)
+		return
+	}
+
+	_, err = contract.SubmitTransaction("AddCertificateForStudent", append([]string{argsArray[0], argsArray[1], key}, details...)...)
+	if err != nil {
+		// The ledger has no record of the file, so it is not left in the storage. The request may have been
+		// cancelled, so the file is deleted with a context of its own
+		if deleteErr := setup.Storage.Delete(context.WithoutCancel(r.Context()), key); deleteErr != nil {
+			log.Printf("Failed to delete certificate %s that was not recorded: %v", key, deleteErr)
+		}
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	downloadURL, expiresAt := setup.URLSigner.Sign(key)
+	upload := CertificateUpload{
+		Key:         key,
+		SHA256:      details[0],
+		Size:        len(data),
+		MimeType:    details[2],
+		DownloadURL: downloadURL,
+		ExpiresAt:   expiresAt,
+	}
+	uploadJSON, err := json.Marshal(upload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(uploadJSON)
+}
+
+// CertificateDownloadURL issues a short-lived URL to download the certificate of a student for an activity.
+// args=<studentID>&args=<activityID>; certificates awaiting review can be downloaded too, so that faculty can review them.
+// The request must be signed by the student, the faculty in charge of the activity or the faculty who issued the certificate.
+func (setup OrgSetup) CertificateDownloadURL(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received CertificateDownloadURL request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	channelID, chainCodeName, err := requestChaincode(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	argsArray := r.Form["args"]
+	if len(argsArray) != 2 {
+		http.Error(w, "Invalid number of arguments", http.StatusBadRequest)
+		return
+	}
+	caller, err := setup.authenticateCaller(r, argsArray)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusUnauthorized)
+		return
+	}
+
+	contract := setup.Gateway.GetNetwork(channelID).GetContract(chainCodeName)
+	certificateJSON, err := contract.EvaluateTransaction("GetCertificateSubmission", argsArray[0], argsArray[1])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusNotFound)
+		return
+	}
+	var certificate ledgerCertificate
+	if err := json.Unmarshal(certificateJSON, &certificate); err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+	allowed, err := canDownloadCertificate(contract, caller, argsArray[0], certificate)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		http.Error(w, "Error: only the student and the faculty in charge of the activity can download the certificate", http.StatusForbidden)
+		return
+	}
+
+	downloadURL, expiresAt := setup.URLSigner.Sign(certificate.Key)
+	responseJSON, err := json.Marshal(map[string]interface{}{
+		"url":       downloadURL,
+		"expiresAt": expiresAt,
+		"mimeType":  certificate.MimeType,
+		"sha256":    certificate.SHA256,
+		"status":    certificate.Status,
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(responseJSON)
+}
+
+// canDownloadCertificate reports whether the caller is the student, the faculty in charge of the activity or the
+// faculty who issued the certificate.
+func canDownloadCertificate(contract *client.Contract, caller *callerIdentity, studentID string, certificate ledgerCertificate) (bool, error) {
+	if caller.Attributes["studentID"] != "" && caller.Attributes["studentID"] == studentID {
+		return true, nil
+	}
+	facultyID := caller.Attributes["facultyID"]
+	if facultyID == "" {
+		return false, nil
+	}
+	if facultyID == certificate.IssuerFacultyID {
+		return true, nil
+	}
+
+	return isActivityFaculty(contract, facultyID, certificate.ActivityID)
+}
+
+// canSubmitCertificate reports whether the caller is the student, an admin or the faculty in charge of the activity.
+func canSubmitCertificate(contract *client.Contract, caller *callerIdentity, studentID string, activityID string) (bool, error) {
+	if caller.isAdmin() || (caller.Attributes["studentID"] != "" && caller.Attributes["studentID"] == studentID) {
+		return true, nil
+	}
+	facultyID := caller.Attributes["facultyID"]
+	if facultyID == "" {
+		return false, nil
+	}
+
+	return isActivityFaculty(contract, facultyID, activityID)
+}
+
+// isActivityFaculty reports whether a faculty is in charge of an extracurricular activity.
+func isActivityFaculty(contract *client.Contract, facultyID string, activityID string) (bool, error) {
+	activityJSON, err := contract.EvaluateTransaction("GetExtracurricularActivity", activityID)
+	if err != nil {
+		return false, err
+	}
+	var activity ledgerActivity
+	if err := json.Unmarshal(activityJSON, &activity); err != nil {
+		return false, err
+	}
+	return facultyID == activity.FacultyID, nil
+}
+
+// DownloadCertificate serves a certificate file for a signed URL issued by the API.
+func (setup OrgSetup) DownloadCertificate(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received DownloadCertificate request")
+	query := r.URL.Query()
+	key := query.Get("key")
+	if err := setup.URLSigner.Verify(key, query.Get("expires"), query.Get("signature")); err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusForbidden)
+		return
+	}
+
+	data, err := setup.Storage.Get(r.Context(), key)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", http.DetectContentType(data))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Write(data)
+}
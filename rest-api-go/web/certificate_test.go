@@ -0,0 +1,105 @@
+package web
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCertificateFileDetails(t *testing.T) {
+	data := []byte("%PDF-1.7\ncertificate")
+	hash := sha256.Sum256(data)
+
+	details := certificateFileDetails(data, "", "F1")
+	want := []string{hex.EncodeToString(hash[:]), strconv.Itoa(len(data)), "application/pdf", "F1"}
+	if strings.Join(details, " ") != strings.Join(want, " ") {
+		t.Fatalf("certificateFileDetails returned %v, want %v", details, want)
+	}
+
+	// A MIME type given by the uploader is kept, unless it is the generic binary type
+	if details := certificateFileDetails(data, "image/png", ""); details[2] != "image/png" {
+		t.Errorf("given MIME type is replaced with %s", details[2])
+	}
+	if details := certificateFileDetails(data, "application/octet-stream", ""); details[2] != "application/pdf" {
+		t.Errorf("generic MIME type is not detected, got %s", details[2])
+	}
+}
+
+func TestReadCertificateFile(t *testing.T) {
+	data, err := readCertificateFile(bytes.NewReader([]byte("certificate")))
+	if err != nil || string(data) != "certificate" {
+		t.Fatalf("readCertificateFile returned %q, %v", data, err)
+	}
+
+	if _, err := readCertificateFile(bytes.NewReader(make([]byte, maxCertificateFile))); err != nil {
+		t.Fatalf("file of the maximum size is rejected: %v", err)
+	}
+	if _, err := readCertificateFile(bytes.NewReader(make([]byte, maxCertificateFile+1))); err == nil {
+		t.Fatal("file over the maximum size is accepted")
+	}
+}
+
+func TestVerifyCertificateFileNeedsTheChaincode(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	OrgSetup{}.VerifyCertificateFile(recorder, httptest.NewRequest(http.MethodGet, "/VerifyCertificateFile?args=S1&args=A1", nil))
+	if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), "channelid and chaincodeid are required") {
+		t.Fatalf("VerifyCertificateFile without a chaincode returned %d %s", recorder.Code, recorder.Body)
+	}
+}
+
+func certificateUploadRequest(t *testing.T, fields map[string][]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, values := range fields {
+		for _, value := range values {
+			writer.WriteField(name, value)
+		}
+	}
+	part, err := writer.CreateFormFile("file", "certificate.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte("%PDF-1.7\ncertificate"))
+	writer.Close()
+
+	request := httptest.NewRequest(http.MethodPost, "/UploadCertificate", &body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	return request
+}
+
+func TestUploadCertificateIsAuthenticated(t *testing.T) {
+	setup := newTestCA(t).setup()
+
+	recorder := httptest.NewRecorder()
+	setup.UploadCertificate(recorder, certificateUploadRequest(t, map[string][]string{"args": {"S1", "A1"}}))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("upload without a chaincode returned %d %q", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	setup.UploadCertificate(recorder, certificateUploadRequest(t, map[string][]string{"channelid": {"mychannel"}, "chaincodeid": {"basic"}, "args": {"S1", "A1"}}))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned upload returned %d %q", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestCanSubmitCertificate(t *testing.T) {
+	// The student and admins are allowed without reading the activity from the ledger
+	for _, attributes := range []map[string]string{{"studentID": "S1"}, {"role": "admin"}} {
+		allowed, err := canSubmitCertificate(nil, &callerIdentity{Attributes: attributes}, "S1", "A1")
+		if err != nil || !allowed {
+			t.Errorf("caller with %v is refused: %v", attributes, err)
+		}
+	}
+	allowed, err := canSubmitCertificate(nil, &callerIdentity{Attributes: map[string]string{"studentID": "S2"}}, "S1", "A1")
+	if err != nil || allowed {
+		t.Errorf("another student is allowed: %v", err)
+	}
+}
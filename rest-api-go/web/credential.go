@@ -0,0 +1,551 @@
+package web
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+)
+
+// JSON-LD contexts used by the issued credentials
+const (
+	credentialsContext = "https://www.w3.org/2018/credentials/v1"
+	statusListContext  = "https://w3id.org/vc/status-list/2021/v1"
+	jws2020Context     = "https://w3id.org/security/suites/jws-2020/v1"
+	didContext         = "https://www.w3.org/ns/did/v1"
+)
+
+// credentialJWTType is the typ header of the issued credentials, which use the JWT encoding of the W3C Verifiable
+// Credentials Data Model 1.1 and are signed with ES256 by the key in the organization's DID document
+const credentialJWTType = "JWT"
+
+// statusListLength is the minimum number of entries in a status list, as recommended by the StatusList2021 specification
+const statusListLength = 131072
+
+// CredentialStatusEntry points to the entry of a credential in the revocation status list.
+type CredentialStatusEntry struct {
+	ID                   string `json:"id"`
+	Type                 string `json:"type"`
+	StatusPurpose        string `json:"statusPurpose"`
+	StatusListIndex      string `json:"statusListIndex"`
+	StatusListCredential string `json:"statusListCredential"`
+}
+
+// credentialClaims are the registered JWT claims of a credential, with the credential itself in the vc claim.
+type credentialClaims struct {
+	Issuer     string               `json:"iss"`
+	Subject    string               `json:"sub,omitempty"`
+	ID         string               `json:"jti"`
+	NotBefore  int64                `json:"nbf"`
+	IssuedAt   int64                `json:"iat"`
+	Credential VerifiableCredential `json:"vc"`
+}
+
+// CredentialIssuer identifies the organization issuing a credential.
+type CredentialIssuer struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// VerifiableCredential is a W3C Verifiable Credential issued by the organization.
+type VerifiableCredential struct {
+	Context           []string               `json:"@context"`
+	ID                string                 `json:"id"`
+	Type              []string               `json:"type"`
+	Issuer            CredentialIssuer       `json:"issuer"`
+	IssuanceDate      string                 `json:"issuanceDate"`
+	CredentialSubject map[string]interface{} `json:"credentialSubject"`
+	CredentialStatus  *CredentialStatusEntry `json:"credentialStatus,omitempty"`
+}
+
+// ledgerVerificationRecord mirrors the parts of the chaincode's VerificationRecord needed to issue a credential.
+type ledgerVerificationRecord struct {
+	CredentialID string            `json:"credentialID"`
+	StudentID    string            `json:"studentID"`
+	StudentName  string            `json:"studentName"`
+	Details      map[string]string `json:"details"`
+}
+
+// ledgerVerificationResult mirrors the chaincode's VerificationResult.
+type ledgerVerificationResult struct {
+	Verified bool                     `json:"verified"`
+	Message  string                   `json:"message"`
+	Record   ledgerVerificationRecord `json:"record"`
+}
+
+// ledgerStatusList mirrors the chaincode's CredentialStatusList.
+type ledgerStatusList struct {
+	ListID    string `json:"listID"`
+	NextIndex int    `json:"nextIndex"`
+	Revoked   []int  `json:"revoked"`
+}
+
+// issuerDID is the did:web identifier of the organization, resolved from the DID document served by this API.
+func issuerDID() string {
+	return "did:web:" + DOMAIN_NAME
+}
+
+// issuerKeyID is the verification method that signs the credentials.
+func issuerKeyID() string {
+	return issuerDID() + "#org-key"
+}
+
+// statusListURL is the URL of the revocation status list credential of the ledger on a channel and chaincode.
+func statusListURL(channelID string, chainCodeName string) string {
+	query := url.Values{"channelid": {channelID}, "chaincodeid": {chainCodeName}}
+	return fmt.Sprintf("https://%s/credentials/status/revocation?%s", DOMAIN_NAME, query.Encode())
+}
+
+// IssueVerifiableCredential issues a W3C Verifiable Credential, encoded as a JWT, for a conferred degree (args=<studentID>)
+// or for an extracurricular certificate (args=<studentID>&args=<activityID>) and anchors its hash on the ledger.
+// The request must be signed by the student or an admin.
+func (setup OrgSetup) IssueVerifiableCredential(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received IssueVerifiableCredential request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	channelID, chainCodeName, err := requestChaincode(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	argsArray := r.Form["args"]
+	if len(argsArray) != 1 && len(argsArray) != 2 {
+		http.Error(w, "Invalid number of arguments", http.StatusBadRequest)
+		return
+	}
+	caller, err := setup.authenticateCaller(r, argsArray)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusUnauthorized)
+		return
+	}
+	if !caller.isAdmin() && caller.Attributes["studentID"] != argsArray[0] {
+		http.Error(w, "Error: only the student or an admin can issue a verifiable credential", http.StatusForbidden)
+		return
+	}
+
+	contract := setup.Gateway.GetNetwork(channelID).GetContract(chainCodeName)
+
+	// Build the claims from the credential the verifiable credential is issued for
+	var sourceCredentialID string
+	var credentialType string
+	var subject map[string]interface{}
+	if len(argsArray) == 1 {
+		sourceCredentialID = fmt.Sprintf("DEGREE-%s", argsArray[0])
+		credentialType = "UniversityDegreeCredential"
+		subject, err = degreeClaims(contract, sourceCredentialID)
+	} else {
+		sourceCredentialID = fmt.Sprintf("CERTIFICATE-%s-%s", argsArray[0], argsArray[1])
+		credentialType = "ExtracurricularCertificateCredential"
+		subject, err = certificateClaims(contract, sourceCredentialID)
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	credentialID, err := newCredentialID()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Sign with the next free index of the status list, the ledger only assigns it when the credential is recorded
+	statusListJSON, err := contract.EvaluateTransaction("GetCredentialStatusList")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+	var statusList ledgerStatusList
+	if err := json.Unmarshal(statusListJSON, &statusList); err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+	statusListIndex := strconv.Itoa(statusList.NextIndex)
+	statusListCredential := statusListURL(channelID, chainCodeName)
+
+	now := time.Now().UTC()
+	credential := VerifiableCredential{
+		Context:           []string{credentialsContext, statusListContext},
+		ID:                credentialID,
+		Type:              []string{"VerifiableCredential", credentialType},
+		Issuer:            CredentialIssuer{ID: issuerDID(), Name: setup.OrgName},
+		IssuanceDate:      now.Format(time.RFC3339),
+		CredentialSubject: subject,
+		CredentialStatus: &CredentialStatusEntry{
+			ID:                   fmt.Sprintf("%s#%s", statusListCredential, statusListIndex),
+			Type:                 "StatusList2021Entry",
+			StatusPurpose:        "revocation",
+			StatusListIndex:      statusListIndex,
+			StatusListCredential: statusListCredential,
+		},
+	}
+
+	credentialJWT, credentialHash, err := setup.signCredential(credential, now)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Record the credential, this fails if the source is no longer valid or another credential took the index first
+	_, err = contract.SubmitTransaction("IssueVerifiableCredential", credentialID, sourceCredentialID, statusListIndex, credentialHash)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/jwt")
+	w.Write(credentialJWT)
+}
+
+// RevocationStatusList serves the StatusList2021 credential, encoded as a JWT, that verifiers use to check revocation.
+// The channelid and chaincodeid query parameters are part of the status list URL in each credential.
+func (setup OrgSetup) RevocationStatusList(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received RevocationStatusList request")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	channelID, chainCodeName, err := requestChaincode(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	contract := setup.Gateway.GetNetwork(channelID).GetContract(chainCodeName)
+	statusListJSON, err := contract.EvaluateTransaction("GetCredentialStatusList")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+	var statusList ledgerStatusList
+	if err := json.Unmarshal(statusListJSON, &statusList); err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	encodedList, err := encodeStatusList(statusList)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now().UTC()
+	statusListCredential := statusListURL(channelID, chainCodeName)
+	credential := VerifiableCredential{
+		Context:      []string{credentialsContext, statusListContext},
+		ID:           statusListCredential,
+		Type:         []string{"VerifiableCredential", "StatusList2021Credential"},
+		Issuer:       CredentialIssuer{ID: issuerDID(), Name: setup.OrgName},
+		IssuanceDate: now.Format(time.RFC3339),
+		CredentialSubject: map[string]interface{}{
+			"id":            statusListCredential + "#list",
+			"type":          "StatusList2021",
+			"statusPurpose": "revocation",
+			"encodedList":   encodedList,
+		},
+	}
+
+	credentialJWT, _, err := setup.signCredential(credential, now)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/jwt")
+	w.Write(credentialJWT)
+}
+
+// DIDDocument serves the did:web document of the organization with the public key that signs the credentials.
+func (setup OrgSetup) DIDDocument(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received DIDDocument request")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	publicKey, err := setup.issuerPublicKey()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	document := map[string]interface{}{
+		"@context": []string{didContext, jws2020Context},
+		"id":       issuerDID(),
+		"verificationMethod": []map[string]interface{}{{
+			"id":         issuerKeyID(),
+			"type":       "JsonWebKey2020",
+			"controller": issuerDID(),
+			"publicKeyJwk": map[string]interface{}{
+				"kty": "EC",
+				"crv": "P-256",
+				"x":   base64.RawURLEncoding.EncodeToString(padScalar(publicKey.X)),
+				"y":   base64.RawURLEncoding.EncodeToString(padScalar(publicKey.Y)),
+				// The organization's certificate, which chains up to its Fabric CA
+				"x5c": []string{base64.StdEncoding.EncodeToString(setup.Certificate.Raw)},
+			},
+		}},
+		"assertionMethod": []string{issuerKeyID()},
+	}
+
+	documentJSON, err := json.Marshal(document)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/did+json")
+	w.Write(documentJSON)
+}
+
+// degreeClaims builds the credential subject of a degree from its verification record.
+func degreeClaims(contract *client.Contract, sourceCredentialID string) (map[string]interface{}, error) {
+	record, err := validCredentialRecord(contract, sourceCredentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"id":        "urn:student:" + record.StudentID,
+		"studentID": record.StudentID,
+		"name":      record.StudentName,
+		"degree": map[string]interface{}{
+			"type":           "Degree",
+			"name":           record.Details["programType"],
+			"department":     record.Details["department"],
+			"graduationDate": record.Details["graduationDate"],
+			"cgpa":           record.Details["cgpa"],
+			"division":       record.Details["division"],
+		},
+	}, nil
+}
+
+// certificateClaims builds the credential subject of an extracurricular certificate from its verification record and the activity.
+func certificateClaims(contract *client.Contract, sourceCredentialID string) (map[string]interface{}, error) {
+	record, err := validCredentialRecord(contract, sourceCredentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	activityJSON, err := contract.EvaluateTransaction("GetExtracurricularActivity", record.Details["activityID"])
+	if err != nil {
+		return nil, err
+	}
+	var activity map[string]interface{}
+	if err := json.Unmarshal(activityJSON, &activity); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"id":        "urn:student:" + record.StudentID,
+		"studentID": record.StudentID,
+		"name":      record.StudentName,
+		"activity": map[string]interface{}{
+			"id":          record.Details["activityID"],
+			"name":        activity["name"],
+			"description": activity["description"],
+			"location":    activity["location"],
+			"date":        activity["date"],
+		},
+		"certificateKey": record.Details["key"],
+	}, nil
+}
+
+// validCredentialRecord fetches the verification record of a credential and checks that it is still valid.
+func validCredentialRecord(contract *client.Contract, credentialID string) (*ledgerVerificationRecord, error) {
+	resultJSON, err := contract.EvaluateTransaction("VerifyCredential", credentialID, "")
+	if err != nil {
+		return nil, err
+	}
+	var result ledgerVerificationResult
+	if err := json.Unmarshal(resultJSON, &result); err != nil {
+		return nil, err
+	}
+	if !result.Verified {
+		return nil, errors.New(result.Message)
+	}
+
+	return &result.Record, nil
+}
+
+// signCredential encodes a credential as a JWT signed with ES256 and returns the JWT and its hex encoded SHA-256,
+// which is what gets anchored on the ledger.
+func (setup OrgSetup) signCredential(credential VerifiableCredential, issuedAt time.Time) ([]byte, string, error) {
+	subject, _ := credential.CredentialSubject["id"].(string)
+	claims := credentialClaims{
+		Issuer:     credential.Issuer.ID,
+		Subject:    subject,
+		ID:         credential.ID,
+		NotBefore:  issuedAt.Unix(),
+		IssuedAt:   issuedAt.Unix(),
+		Credential: credential,
+	}
+
+	token, err := setup.signJWT(claims)
+	if err != nil {
+		return nil, "", err
+	}
+	hash := sha256.Sum256([]byte(token))
+
+	return []byte(token), hex.EncodeToString(hash[:]), nil
+}
+
+// verifyCredentialJWT checks that a credential JWT was signed with the organization's key and returns the hex encoded
+// SHA-256 of the JWT.
+func (setup OrgSetup) verifyCredentialJWT(token string) (string, error) {
+	hash := sha256.Sum256([]byte(token))
+	credentialHash := hex.EncodeToString(hash[:])
+
+	publicKey, err := setup.issuerPublicKey()
+	if err != nil {
+		return credentialHash, err
+	}
+	payload, err := verifyJWT(publicKey, token)
+	if err != nil {
+		return credentialHash, err
+	}
+
+	var claims credentialClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return credentialHash, fmt.Errorf("invalid credential claims: %w", err)
+	}
+	if claims.Issuer != issuerDID() || claims.Credential.Issuer.ID != issuerDID() || claims.ID != claims.Credential.ID {
+		return credentialHash, errors.New("credential was not issued by the organization")
+	}
+
+	return credentialHash, nil
+}
+
+// signJWT signs claims with ES256 as a compact JWS (RFC 7515) whose key ID is the organization's verification method.
+func (setup OrgSetup) signJWT(claims interface{}) (string, error) {
+	privateKey, ok := setup.PrivateKey.(*ecdsa.PrivateKey)
+	if !ok || privateKey.Curve != elliptic.P256() {
+		return "", errors.New("organization key is not a P-256 ECDSA key")
+	}
+
+	headerJSON, err := json.Marshal(map[string]string{"alg": "ES256", "typ": credentialJWTType, "kid": issuerKeyID()})
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	signature := append(padScalar(r), padScalar(s)...)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// verifyJWT checks an ES256 compact JWS signed with the organization's verification method and returns its payload.
+func verifyJWT(publicKey *ecdsa.PublicKey, token string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("credential is not a JWT")
+	}
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT header: %w", err)
+	}
+	var protected struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(header, &protected); err != nil || protected.Alg != "ES256" {
+		return nil, errors.New("unsupported JWT header")
+	}
+	if protected.Kid != issuerKeyID() {
+		return nil, errors.New("credential was not signed with the organization's key")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(signature) != 64 {
+		return nil, errors.New("invalid JWT signature")
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:])
+	if !ecdsa.Verify(publicKey, digest[:], r, s) {
+		return nil, errors.New("credential signature does not match the credential")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT payload: %w", err)
+	}
+	return payload, nil
+}
+
+// issuerPublicKey returns the organization's P-256 public key.
+func (setup OrgSetup) issuerPublicKey() (*ecdsa.PublicKey, error) {
+	if setup.Certificate == nil {
+		return nil, errors.New("organization certificate is not available")
+	}
+	publicKey, ok := setup.Certificate.PublicKey.(*ecdsa.PublicKey)
+	if !ok || publicKey.Curve != elliptic.P256() {
+		return nil, errors.New("organization certificate does not hold a P-256 ECDSA key")
+	}
+
+	return publicKey, nil
+}
+
+// encodeStatusList encodes the revoked indexes as a GZIP compressed, base64url encoded bitstring.
+func encodeStatusList(statusList ledgerStatusList) (string, error) {
+	length := statusListLength
+	if statusList.NextIndex > length {
+		length = (statusList.NextIndex + 7) / 8 * 8
+	}
+
+	bitstring := make([]byte, length/8)
+	for _, index := range statusList.Revoked {
+		if index < 0 || index >= length {
+			return "", fmt.Errorf("status list index %d is out of range", index)
+		}
+		// The first index is the most significant bit of the first byte
+		bitstring[index/8] |= 0x80 >> (index % 8)
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(bitstring); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(compressed.Bytes()), nil
+}
+
+// newCredentialID generates a random UUID URN for a credential.
+func newCredentialID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]), nil
+}
+
+// padScalar encodes a P-256 coordinate or signature component as 32 big-endian bytes.
+func padScalar(value *big.Int) []byte {
+	return value.FillBytes(make([]byte, 32))
+}
@@ -0,0 +1,143 @@
+package web
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testCredential() VerifiableCredential {
+	return VerifiableCredential{
+		Context:           []string{credentialsContext, statusListContext},
+		ID:                "urn:uuid:1",
+		Type:              []string{"VerifiableCredential"},
+		Issuer:            CredentialIssuer{ID: issuerDID(), Name: "Org1"},
+		IssuanceDate:      "2024-01-01T00:00:00Z",
+		CredentialSubject: map[string]interface{}{"id": "did:example:S1", "name": "Asha"},
+	}
+}
+
+func TestCredentialIsAVerifiableJWT(t *testing.T) {
+	setup := newSigningSetup(t)
+	issuedAt := time.Unix(1704067200, 0)
+	token, hash, err := setup.signCredential(testCredential(), issuedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := strings.Split(string(token), ".")
+	if len(parts) != 3 {
+		t.Fatalf("credential is not a compact JWT: %s", token)
+	}
+	var header map[string]string
+	headerJSON, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	if err := json.Unmarshal(headerJSON, &header); err != nil || header["alg"] != "ES256" || header["typ"] != "JWT" || header["kid"] != issuerKeyID() {
+		t.Fatalf("JWT header is %s", headerJSON)
+	}
+	var claims credentialClaims
+	claimsJSON, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims.Issuer != issuerDID() || claims.Subject != "did:example:S1" || claims.ID != "urn:uuid:1" || claims.NotBefore != issuedAt.Unix() || claims.Credential.CredentialSubject["name"] != "Asha" {
+		t.Fatalf("JWT claims are %+v", claims)
+	}
+
+	verifiedHash, err := setup.verifyCredentialJWT(string(token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verifiedHash != hash {
+		t.Fatalf("verified hash %s, issued hash %s", verifiedHash, hash)
+	}
+}
+
+func TestTamperedCredentialIsRejected(t *testing.T) {
+	setup := newSigningSetup(t)
+	token, _, err := setup.signCredential(testCredential(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(string(token), ".")
+
+	claimsJSON, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	tampered := strings.Replace(string(claimsJSON), "Asha", "Ravi", 1)
+	parts[1] = base64.RawURLEncoding.EncodeToString([]byte(tampered))
+	if _, err := setup.verifyCredentialJWT(strings.Join(parts, ".")); err == nil || !strings.Contains(err.Error(), "signature does not match") {
+		t.Fatalf("tampered credential verified with %v", err)
+	}
+
+	// A credential signed by another organization's key
+	other := newSigningSetup(t)
+	if _, err := other.verifyCredentialJWT(string(token)); err == nil {
+		t.Fatal("credential verified with another organization's key")
+	}
+}
+
+func TestCredentialHandlersNeedTheChaincode(t *testing.T) {
+	setup := newSigningSetup(t)
+
+	recorder := httptest.NewRecorder()
+	setup.RevocationStatusList(recorder, httptest.NewRequest(http.MethodGet, "/credentials/status/revocation", nil))
+	if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), "channelid and chaincodeid are required") {
+		t.Fatalf("status list without a chaincode returned %d %s", recorder.Code, recorder.Body)
+	}
+
+	// The status list URL in a credential names the ledger it is kept on
+	statusList, err := url.Parse(statusListURL("mychannel", "basic"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := httptest.NewRequest(http.MethodGet, statusList.RequestURI(), nil)
+	channelID, chainCodeName, err := requestChaincode(request)
+	if err != nil || channelID != "mychannel" || chainCodeName != "basic" {
+		t.Fatalf("status list URL %s names %q %q: %v", statusList, channelID, chainCodeName, err)
+	}
+}
+
+func credentialRequestFor(t *testing.T, path string, caller *testCaller, args ...string) *http.Request {
+	t.Helper()
+	form := url.Values{"channelid": {"mychannel"}, "chaincodeid": {"basic"}, "args": args}
+	request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if caller != nil {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		request.Header.Set(certificateHeader, base64.StdEncoding.EncodeToString(caller.certificatePEM))
+		request.Header.Set(timestampHeader, timestamp)
+		request.Header.Set(signatureHeader, caller.sign(t, path, timestamp, args))
+	}
+	return request
+}
+
+func TestCredentialRequestsAreAuthorized(t *testing.T) {
+	ca := newTestCA(t)
+	setup := ca.setup()
+	student := ca.enroll(t, map[string]string{"studentID": "S1"})
+	faculty := ca.enroll(t, map[string]string{"facultyID": "F1", "role": "faculty"})
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		request *http.Request
+		want    int
+	}{
+		{"unsigned issue", setup.IssueVerifiableCredential, credentialRequestFor(t, "/IssueVerifiableCredential", nil, "S1"), http.StatusUnauthorized},
+		{"issue for another student", setup.IssueVerifiableCredential, credentialRequestFor(t, "/IssueVerifiableCredential", student, "S2", "A1"), http.StatusForbidden},
+		{"issue by faculty", setup.IssueVerifiableCredential, credentialRequestFor(t, "/IssueVerifiableCredential", faculty, "S1"), http.StatusForbidden},
+		{"unsigned revocation", setup.RevokeCredential, credentialRequestFor(t, "/RevokeCredential", nil, "urn:uuid:1", "Issued in error"), http.StatusUnauthorized},
+		{"revocation by the student", setup.RevokeCredential, credentialRequestFor(t, "/RevokeCredential", student, "urn:uuid:1", "Issued in error"), http.StatusForbidden},
+	}
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		test.handler(recorder, test.request)
+		if recorder.Code != test.want {
+			t.Errorf("%s returned %d %q, want %d", test.name, recorder.Code, recorder.Body.String(), test.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+)
+
+// activityCancellation mirrors the payload of the chaincode's ActivityCancelled event.
+type activityCancellation struct {
+	ActivityID string   `json:"activityID"`
+	Name       string   `json:"name"`
+	Reason     string   `json:"reason"`
+	StudentIDs []string `json:"studentIDs"`
+}
+
+// ActivityNotifications streams the cancellations of the activities a student registered for as server-sent events.
+// args=<studentID> and an optional fromBlock=<block number> to replay the notifications missed since that block.
+func (setup OrgSetup) ActivityNotifications(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received ActivityNotifications request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	channelID, chainCodeName, err := requestChaincode(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	argsArray := r.Form["args"]
+	if len(argsArray) != 1 {
+		http.Error(w, "Invalid number of arguments", http.StatusBadRequest)
+		return
+	}
+	studentID := argsArray[0]
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var options []client.ChaincodeEventsOption
+	if fromBlock := r.FormValue("fromBlock"); fromBlock != "" {
+		blockNumber, err := strconv.ParseUint(fromBlock, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid block number %s", fromBlock), http.StatusBadRequest)
+			return
+		}
+		options = append(options, client.WithStartBlock(blockNumber))
+	}
+
+	// The event stream ends when the client disconnects
+	events, err := setup.Gateway.GetNetwork(channelID).ChaincodeEvents(r.Context(), chainCodeName, options...)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher.Flush()
+
+	for event := range events {
+		if event.EventName != "ActivityCancelled" {
+			continue
+		}
+		var cancellation activityCancellation
+		if err := json.Unmarshal(event.Payload, &cancellation); err != nil {
+			continue
+		}
+		if !containsString(cancellation.StudentIDs, studentID) {
+			continue
+		}
+
+		fmt.Fprintf(w, "event: %s\nid: %d\ndata: %s\n\n", event.EventName, event.BlockNumber, event.Payload)
+		flusher.Flush()
+	}
+}
+
+// containsString reports whether a slice contains a string.
+func containsString(slice []string, item string) bool {
+	for _, element := range slice {
+		if element == item {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"crypto"
 	"crypto/x509"
 	"fmt"
 	"io/ioutil"
@@ -17,6 +18,39 @@ import (
 // Initialize the setup for the organization.
 func Initialize(setup OrgSetup) (*OrgSetup, error) {
 	log.Printf("Initializing connection for %s...\n", setup.OrgName)
+
+	// Keep the organization's certificate and key to sign the documents issued by the API
+	certificate, err := loadCertificate(setup.CertPath)
+	if err != nil {
+		panic(err)
+	}
+	setup.Certificate = certificate
+	setup.PrivateKey = setup.loadPrivateKey()
+
+	// Certificate files are stored and served by the API
+	setup.Storage, err = newCertificateStorage()
+	if err != nil {
+		return nil, err
+	}
+	setup.URLSigner, err = newURLSigner()
+	if err != nil {
+		return nil, err
+	}
+	setup.CallerCAs, err = loadCallerCAs(setup.CACertPath)
+	if err != nil {
+		return nil, err
+	}
+
+	connectedSetup, err := Connect(setup)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("Initialization complete")
+	return connectedSetup, nil
+}
+
+// Connect connects the organization to the Gateway, without the signing keys and storage used by the API endpoints.
+func Connect(setup OrgSetup) (*OrgSetup, error) {
 	clientConnection := setup.newGrpcConnection()
 	id := setup.newIdentity()
 	sign := setup.newSign()
@@ -34,7 +68,6 @@ func Initialize(setup OrgSetup) (*OrgSetup, error) {
 		panic(err)
 	}
 	setup.Gateway = *gateway
-	log.Println("Initialization complete")
 	return &setup, nil
 }
 
@@ -74,6 +107,18 @@ func (setup OrgSetup) newIdentity() *identity.X509Identity {
 
 // newSign creates a function that generates a digital signature from a message digest using a private key.
 func (setup OrgSetup) newSign() identity.Sign {
+	privateKey := setup.loadPrivateKey()
+
+	sign, err := identity.NewPrivateKeySign(privateKey)
+	if err != nil {
+		panic(err)
+	}
+
+	return sign
+}
+
+// loadPrivateKey reads the private key from the first file in the key directory.
+func (setup OrgSetup) loadPrivateKey() crypto.PrivateKey {
 	files, err := ioutil.ReadDir(setup.KeyPath)
 	if err != nil {
 		panic(fmt.Errorf("failed to read private key directory: %w", err))
@@ -89,12 +134,7 @@ func (setup OrgSetup) newSign() identity.Sign {
 		panic(err)
 	}
 
-	sign, err := identity.NewPrivateKeySign(privateKey)
-	if err != nil {
-		panic(err)
-	}
-
-	return sign
+	return privateKey
 }
 
 func loadCertificate(filename string) (*x509.Certificate, error) {
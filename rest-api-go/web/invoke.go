@@ -403,7 +403,12 @@ func (setup *OrgSetup) AddCertificateForStudent(w http.ResponseWriter, r *http.R
 	channelID := "mychannel"
 	function := "AddCertificateForStudent"
 
-	argsArray := r.Form["args"]
+	// Record the digest, size and type of the certificate file along with its key
+	argsArray, err := setup.certificateFileArgs(r.Context(), r.Form["args"])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusBadRequest)
+		return
+	}
 
 	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
 	network := setup.Gateway.GetNetwork(channelID)
@@ -445,3 +450,830 @@ func (setup *OrgSetup) AddExtracurricularActivityForStudent(w http.ResponseWrite
 	fmt.Fprintf(w, "%s", submitResponse)
 
 }
+
+func (setup *OrgSetup) SetProgramGraduationRequirements(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received SetProgramGraduationRequirements request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "SetProgramGraduationRequirements"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) ConferDegree(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received ConferDegree request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "ConferDegree"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) SetCurriculum(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received SetCurriculum request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "SetCurriculum"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) RevokeCredential(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received RevokeCredential request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "RevokeCredential"
+
+	argsArray := r.Form["args"]
+
+	// Only admins can revoke credentials
+	caller, err := setup.authenticateCaller(r, argsArray)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusUnauthorized)
+		return
+	}
+	if !caller.isAdmin() {
+		http.Error(w, "Error: only an admin can revoke a credential", http.StatusForbidden)
+		return
+	}
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) ReviewCertificate(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received ReviewCertificate request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "ReviewCertificate"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) MarkActivityAttendance(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received MarkActivityAttendance request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "MarkActivityAttendance"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) SetRatingWeights(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received SetRatingWeights request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "SetRatingWeights"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) SetActivitySchedule(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received SetActivitySchedule request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "SetActivitySchedule"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) SetActivityStatus(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received SetActivityStatus request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "SetActivityStatus"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) CancelExtracurricularActivity(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received CancelExtracurricularActivity request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "CancelExtracurricularActivity"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) WithdrawFromExtracurricularActivity(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received WithdrawFromExtracurricularActivity request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "WithdrawFromExtracurricularActivity"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) RebuildActivityRosters(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received RebuildActivityRosters request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "RebuildActivityRosters"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) AddClub(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received AddClub request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "AddClub"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) UpdateClubMembers(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received UpdateClubMembers request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "UpdateClubMembers"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) SetActivityClassification(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received SetActivityClassification request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "SetActivityClassification"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) ChangeStudentStatus(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received ChangeStudentStatus request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "ChangeStudentStatus"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) TransferStudent(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received TransferStudent request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "TransferStudent"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) ArchiveEntity(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received ArchiveEntity request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "ArchiveEntity"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) UpdateCourse(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received UpdateCourse request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "UpdateCourse"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) UpdateFaculty(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received UpdateFaculty request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "UpdateFaculty"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) UpdateDepartment(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received UpdateDepartment request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "UpdateDepartment"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) UpdateProgram(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received UpdateProgram request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "UpdateProgram"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) UpdateExtracurricularActivity(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received UpdateExtracurricularActivity request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "UpdateExtracurricularActivity"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) SetFacultyDesignations(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received SetFacultyDesignations request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "SetFacultyDesignations"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) SetJointAppointments(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received SetJointAppointments request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "SetJointAppointments"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) SetDepartmentHead(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received SetDepartmentHead request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "SetDepartmentHead"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) SetCourseCoInstructors(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received SetCourseCoInstructors request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "SetCourseCoInstructors"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) AssignAdvisor(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received AssignAdvisor request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "AssignAdvisor"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) SubmitCoursePlan(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received SubmitCoursePlan request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "SubmitCoursePlan"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) ReviewCoursePlan(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received ReviewCoursePlan request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "ReviewCoursePlan"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) RecomputeEnrollment(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received RecomputeEnrollment request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "RecomputeEnrollment"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) MigrateLedgerKeys(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received MigrateLedgerKeys request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "MigrateLedgerKeys"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) MigrateSchema(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received MigrateSchema request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "MigrateSchema"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
+
+func (setup *OrgSetup) ImportRecords(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received ImportRecords request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "ImportRecords"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+
+	submitResponse, err := contract.SubmitTransaction(function, argsArray...)
+
+	if err != nil {
+		panic(fmt.Errorf("failed to submit transaction: %w", err))
+	}
+	fmt.Fprintf(w, "%s", submitResponse)
+}
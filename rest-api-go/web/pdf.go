@@ -0,0 +1,167 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 page layout in PDF points
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 50.0
+)
+
+// pdfLine is a single line of text on a PDF page.
+type pdfLine struct {
+	Text string
+	Size float64
+	Bold bool
+}
+
+// pdfAttachment is a file embedded in the PDF, such as the machine-readable copy of a document.
+type pdfAttachment struct {
+	Name        string
+	MimeType    string
+	Description string
+	Data        []byte
+}
+
+// pdfDocument builds a minimal text-only PDF using the standard Helvetica fonts, without any external dependency.
+type pdfDocument struct {
+	lines       []pdfLine
+	attachments []pdfAttachment
+}
+
+// AddLine adds a line of text, wrapping it if it does not fit in the page width.
+func (doc *pdfDocument) AddLine(text string, size float64, bold bool) {
+	// Helvetica glyphs are about half as wide as the font size on average
+	maxChars := int((pdfPageWidth - 2*pdfMargin) / (size * 0.5))
+	for len(text) > maxChars {
+		cut := strings.LastIndex(text[:maxChars], " ")
+		if cut <= 0 {
+			cut = maxChars
+		}
+		doc.lines = append(doc.lines, pdfLine{Text: text[:cut], Size: size, Bold: bold})
+		text = strings.TrimLeft(text[cut:], " ")
+	}
+	doc.lines = append(doc.lines, pdfLine{Text: text, Size: size, Bold: bold})
+}
+
+// AddBlankLine adds vertical space between sections.
+func (doc *pdfDocument) AddBlankLine() {
+	doc.lines = append(doc.lines, pdfLine{Size: 6})
+}
+
+// Attach embeds a file in the PDF.
+func (doc *pdfDocument) Attach(attachment pdfAttachment) {
+	doc.attachments = append(doc.attachments, attachment)
+}
+
+// Bytes renders the document, paginating the lines over as many pages as needed.
+func (doc *pdfDocument) Bytes() []byte {
+	// Split the lines into pages
+	var pages [][]pdfLine
+	var current []pdfLine
+	y := pdfPageHeight - pdfMargin
+	for _, line := range doc.lines {
+		height := line.Size * 1.4
+		if y-height < pdfMargin && len(current) > 0 {
+			pages = append(pages, current)
+			current = nil
+			y = pdfPageHeight - pdfMargin
+		}
+		current = append(current, line)
+		y -= height
+	}
+	if len(current) > 0 || len(pages) == 0 {
+		pages = append(pages, current)
+	}
+
+	// Object numbers: 1 catalog, 2 page tree, 3 and 4 fonts, then two objects per attachment and per page
+	var objects []string
+	objects = append(objects, "") // catalog, filled in below
+	objects = append(objects, "") // page tree, filled in below
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	var embeddedNames []string
+	for _, attachment := range doc.attachments {
+		fileObject := len(objects) + 1
+		objects = append(objects, fmt.Sprintf("<< /Type /EmbeddedFile /Subtype /%s /Length %d >>\nstream\n%s\nendstream",
+			strings.ReplaceAll(attachment.MimeType, "/", "#2F"), len(attachment.Data), attachment.Data))
+		specObject := len(objects) + 1
+		objects = append(objects, fmt.Sprintf("<< /Type /Filespec /F %s /UF %s /Desc %s /EF << /F %d 0 R >> >>",
+			pdfString(attachment.Name), pdfString(attachment.Name), pdfString(attachment.Description), fileObject))
+		embeddedNames = append(embeddedNames, fmt.Sprintf("%s %d 0 R", pdfString(attachment.Name), specObject))
+	}
+
+	var kids []string
+	for _, page := range pages {
+		var content bytes.Buffer
+		y := pdfPageHeight - pdfMargin
+		for _, line := range page {
+			y -= line.Size * 1.4
+			if line.Text == "" {
+				continue
+			}
+			font := "F1"
+			if line.Bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&content, "BT /%s %.1f Tf %.1f %.1f Td %s Tj ET\n", font, line.Size, pdfMargin, y, pdfString(line.Text))
+		}
+		contentObject := len(objects) + 1
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+		pageObject := len(objects) + 1
+		objects = append(objects, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, contentObject))
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageObject))
+	}
+
+	catalog := "<< /Type /Catalog /Pages 2 0 R"
+	if len(embeddedNames) > 0 {
+		catalog += fmt.Sprintf(" /Names << /EmbeddedFiles << /Names [%s] >> >>", strings.Join(embeddedNames, " "))
+	}
+	objects[0] = catalog + " >>"
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	// Write the objects followed by the cross-reference table
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.7\n")
+	offsets := make([]int, len(objects))
+	for index, object := range objects {
+		offsets[index] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", index+1, object)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return out.Bytes()
+}
+
+// pdfString encodes text as a PDF literal string in WinAnsi encoding, escaping special characters.
+func pdfString(text string) string {
+	var out strings.Builder
+	out.WriteByte('(')
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		case r >= 32 && r < 127:
+			out.WriteRune(r)
+		case r >= 160 && r < 256:
+			fmt.Fprintf(&out, "\\%03o", r)
+		default:
+			out.WriteByte('?')
+		}
+	}
+	out.WriteByte(')')
+	return out.String()
+}
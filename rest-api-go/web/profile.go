@@ -0,0 +1,68 @@
+package web
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+)
+
+// SetStudentProfile stores the private profile of a student with the SetStudentProfile transaction.
+// args=<studentID> and profile=<profile JSON>; the profile is sent as transient data so that it is not recorded on the ledger.
+func (setup *OrgSetup) SetStudentProfile(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received SetStudentProfile request")
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("ParseForm() err: %s", err), http.StatusBadRequest)
+		return
+	}
+	argsArray := r.Form["args"]
+	if len(argsArray) != 1 {
+		http.Error(w, "Invalid number of arguments", http.StatusBadRequest)
+		return
+	}
+	profile := r.FormValue("profile")
+	if !json.Valid([]byte(profile)) {
+		http.Error(w, "Error: profile must be a JSON object", http.StatusBadRequest)
+		return
+	}
+
+	salt, err := newSalt()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	if err := setup.submitWithTransient(r, "SetStudentProfile", argsArray, map[string][]byte{"profile": []byte(profile), "salt": salt}); err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusBadRequest)
+		return
+	}
+	fmt.Fprintf(w, "Profile of student %s updated", argsArray[0])
+}
+
+// submitWithTransient submits a transaction, on the channel and chaincode named by the request, with values passed as
+// transient data, which are left out of the log.
+func (setup *OrgSetup) submitWithTransient(r *http.Request, function string, argsArray []string, transient map[string][]byte) error {
+	channelID, chainCodeName, err := requestChaincode(r)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	contract := setup.Gateway.GetNetwork(channelID).GetContract(chainCodeName)
+	_, err = contract.Submit(function,
+		client.WithArguments(argsArray...),
+		client.WithTransient(transient),
+	)
+	return err
+}
+
+// newSalt returns a random salt for the private data the chaincode stores with a public digest, so that the data
+// cannot be found by hashing guesses. The chaincode cannot generate it, as every endorsing peer would pick another.
+func newSalt() ([]byte, error) {
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
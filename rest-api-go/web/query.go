@@ -408,3 +408,739 @@ func (setup OrgSetup) GetCertificateForActivityAndStudent(w http.ResponseWriter,
 	}
 	fmt.Fprintf(w, "%s", evaluateResponse)
 }
+
+func (setup OrgSetup) RunDegreeAudit(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received RunDegreeAudit request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "RunDegreeAudit"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetCurriculum(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetCurriculum request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetCurriculum"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetCurriculumProgress(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetCurriculumProgress request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetCurriculumProgress"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetCredentialStatusList(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetCredentialStatusList request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetCredentialStatusList"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetCertificateSubmission(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetCertificateSubmission request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetCertificateSubmission"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetApprovedCertificates(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetApprovedCertificates request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetApprovedCertificates"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetPendingCertificatesForFaculty(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetPendingCertificatesForFaculty request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetPendingCertificatesForFaculty"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetActivityParticipants(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetActivityParticipants request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetActivityParticipants"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetStudentActivityProfile(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetStudentActivityProfile request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetStudentActivityProfile"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetRatingWeights(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetRatingWeights request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetRatingWeights"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetStudentRating(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetStudentRating request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetStudentRating"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetActivityRoster(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetActivityRoster request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetActivityRoster"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetClub(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetClub request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetClub"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetAllClubs(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetAllClubs request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetAllClubs"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) FilterExtracurricularActivities(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received FilterExtracurricularActivities request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "FilterExtracurricularActivities"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetActivitiesByClub(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetActivitiesByClub request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetActivitiesByClub"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetActivitiesByCategory(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetActivitiesByCategory request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetActivitiesByCategory"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetActivitiesByTerm(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetActivitiesByTerm request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetActivitiesByTerm"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetStudentProfile(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetStudentProfile request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetStudentProfile"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) VerifyStudentProfile(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received VerifyStudentProfile request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "VerifyStudentProfile"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) VerifyPrivateResult(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received VerifyPrivateResult request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "VerifyPrivateResult"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetStudentStatus(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetStudentStatus request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetStudentStatus"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetStudentsByStatus(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetStudentsByStatus request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetStudentsByStatus"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetTransferHistory(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetTransferHistory request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetTransferHistory"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetDependents(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetDependents request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetDependents"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetDepartmentFaculty(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetDepartmentFaculty request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetDepartmentFaculty"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetFacultyByDesignation(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetFacultyByDesignation request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetFacultyByDesignation"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetAdvisees(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetAdvisees request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetAdvisees"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetCoursePlans(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetCoursePlans request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetCoursePlans"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetPendingCoursePlans(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetPendingCoursePlans request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetPendingCoursePlans"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetCreditSummary(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetCreditSummary request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetCreditSummary"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
+
+func (setup OrgSetup) GetSchemaStatus(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetSchemaStatus request")
+	if err := r.ParseForm(); err != nil {
+		fmt.Fprintf(w, "ParseForm() err: %s", err)
+		return
+	}
+	chainCodeName := "basic"
+	channelID := "mychannel"
+	function := "GetSchemaStatus"
+
+	argsArray := r.Form["args"]
+
+	fmt.Printf("channel: %s, chaincode: %s, function: %s, args: %s\n", channelID, chainCodeName, function, argsArray)
+	network := setup.Gateway.GetNetwork(channelID)
+	contract := network.GetContract(chainCodeName)
+	evaluateResponse, err := contract.EvaluateTransaction(function, argsArray...)
+	if err != nil {
+		fmt.Fprintf(w, "Error: %s", err)
+		return
+	}
+	fmt.Fprintf(w, "%s", evaluateResponse)
+}
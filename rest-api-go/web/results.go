@@ -0,0 +1,97 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// EnablePrivateResults moves the grades of a student to the private grades collection.
+// args=<studentID>; the grades are stored with a salt, sent as transient data.
+func (setup *OrgSetup) EnablePrivateResults(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received EnablePrivateResults request")
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("ParseForm() err: %s", err), http.StatusBadRequest)
+		return
+	}
+	argsArray := r.Form["args"]
+	if len(argsArray) != 1 {
+		http.Error(w, "Invalid number of arguments", http.StatusBadRequest)
+		return
+	}
+	salt, err := newSalt()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	if err := setup.submitWithTransient(r, "EnablePrivateResults", argsArray, map[string][]byte{"salt": salt}); err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusBadRequest)
+		return
+	}
+	fmt.Fprintf(w, "Results of student %s made private", argsArray[0])
+}
+
+// AddPrivateResult adds the results of the current semester of a student whose results are private.
+// args=<studentID> and results=<JSON list of results>; the grades are sent as transient data, with a salt.
+func (setup *OrgSetup) AddPrivateResult(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received AddPrivateResult request")
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("ParseForm() err: %s", err), http.StatusBadRequest)
+		return
+	}
+	argsArray := r.Form["args"]
+	if len(argsArray) != 1 {
+		http.Error(w, "Invalid number of arguments", http.StatusBadRequest)
+		return
+	}
+	results := r.FormValue("results")
+	if !json.Valid([]byte(results)) {
+		http.Error(w, "Error: results must be a JSON list", http.StatusBadRequest)
+		return
+	}
+
+	salt, err := newSalt()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	if err := setup.submitWithTransient(r, "AddPrivateResultForCurrentSemester", argsArray, map[string][]byte{"results": []byte(results), "salt": salt}); err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusBadRequest)
+		return
+	}
+	fmt.Fprintf(w, "Results of student %s added", argsArray[0])
+}
+
+// UpdatePrivateGrade updates the grade of a course for a student whose results are private.
+// args=<studentID>&args=<courseID> and grade=<grade>; the grade is sent as transient data, with a salt.
+func (setup *OrgSetup) UpdatePrivateGrade(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received UpdatePrivateGrade request")
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("ParseForm() err: %s", err), http.StatusBadRequest)
+		return
+	}
+	argsArray := r.Form["args"]
+	if len(argsArray) != 2 {
+		http.Error(w, "Invalid number of arguments", http.StatusBadRequest)
+		return
+	}
+	grade := r.FormValue("grade")
+	if grade == "" {
+		http.Error(w, "Error: grade is required", http.StatusBadRequest)
+		return
+	}
+
+	salt, err := newSalt()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	if err := setup.submitWithTransient(r, "UpdatePrivateGradeForCourse", argsArray, map[string][]byte{"grade": []byte(grade), "salt": salt}); err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusBadRequest)
+		return
+	}
+	fmt.Fprintf(w, "Grade of course %s for student %s updated", argsArray[1], argsArray[0])
+}
@@ -0,0 +1,145 @@
+package web
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CertificateStorage stores certificate files for the API, so that clients never hold storage credentials.
+type CertificateStorage interface {
+	// Put stores a file and returns the key to retrieve it with. The key may differ from the requested one,
+	// for example IPFS returns the content identifier of the file.
+	Put(ctx context.Context, key string, data []byte, contentType string) (string, error)
+	// Get retrieves a stored file.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes a stored file, used when the certificate it was uploaded for is not recorded on the ledger.
+	Delete(ctx context.Context, key string) error
+}
+
+// newCertificateStorage creates the storage backend selected by CERTIFICATE_STORAGE: local (default), s3 or ipfs.
+func newCertificateStorage() (CertificateStorage, error) {
+	switch backend := os.Getenv("CERTIFICATE_STORAGE"); backend {
+	case "", "local":
+		return newLocalStorage(envOrDefault("CERTIFICATE_STORAGE_DIR", "certificates"))
+	case "s3":
+		return newS3Storage(s3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			Region:    os.Getenv("S3_REGION"),
+			UseSSL:    os.Getenv("S3_USE_SSL") != "false",
+		})
+	case "ipfs":
+		return newIPFSStorage(envOrDefault("IPFS_API_URL", "http://127.0.0.1:5001")), nil
+	default:
+		return nil, fmt.Errorf("unknown certificate storage %s", backend)
+	}
+}
+
+// certificateKey builds the storage key of a certificate file uploaded for a student and activity.
+func certificateKey(studentID string, activityID string, filename string) (string, error) {
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	return path.Join("certificates", safeKeyPart(studentID), safeKeyPart(activityID), hex.EncodeToString(random)+"-"+safeKeyPart(path.Base(filename))), nil
+}
+
+var unsafeKeyCharacters = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// safeKeyPart replaces the characters of a key part that are not safe in file names and URLs.
+func safeKeyPart(part string) string {
+	part = strings.Trim(unsafeKeyCharacters.ReplaceAllString(part, "_"), ".")
+	if part == "" {
+		return "_"
+	}
+	return part
+}
+
+// URLSigner issues and checks short-lived signed URLs for downloading certificate files through the API.
+type URLSigner struct {
+	secret  []byte
+	baseURL string
+	ttl     time.Duration
+}
+
+// newURLSigner creates a signer from CERTIFICATE_URL_SECRET and CERTIFICATE_URL_TTL. Without a secret, a random one is
+// generated, so the URLs issued stop working when the server restarts.
+func newURLSigner() (*URLSigner, error) {
+	secret := []byte(os.Getenv("CERTIFICATE_URL_SECRET"))
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
+
+	ttl := 5 * time.Minute
+	if value := os.Getenv("CERTIFICATE_URL_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CERTIFICATE_URL_TTL: %w", err)
+		}
+		ttl = parsed
+	}
+
+	return &URLSigner{secret: secret, baseURL: "https://" + DOMAIN_NAME, ttl: ttl}, nil
+}
+
+// Sign returns a download URL for a key and the time it expires.
+func (signer *URLSigner) Sign(key string) (string, time.Time) {
+	expires := time.Now().Add(signer.ttl).UTC()
+	expiresValue := strconv.FormatInt(expires.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("key", key)
+	query.Set("expires", expiresValue)
+	query.Set("signature", signer.signature(key, expiresValue))
+
+	return signer.baseURL + "/certificates/download?" + query.Encode(), expires
+}
+
+// Verify checks the signature and expiry of a download URL's parameters.
+func (signer *URLSigner) Verify(key string, expiresValue string, signature string) error {
+	expires, err := strconv.ParseInt(expiresValue, 10, 64)
+	if err != nil {
+		return errors.New("invalid expiry")
+	}
+	if !hmac.Equal([]byte(signature), []byte(signer.signature(key, expiresValue))) {
+		return errors.New("invalid signature")
+	}
+	if time.Now().Unix() > expires {
+		return errors.New("download URL has expired")
+	}
+
+	return nil
+}
+
+// signature computes the HMAC-SHA256 of a key and expiry.
+func (signer *URLSigner) signature(key string, expiresValue string) string {
+	mac := hmac.New(sha256.New, signer.secret)
+	mac.Write([]byte(key + "\n" + expiresValue))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// envOrDefault returns the value of an environment variable, or a default if it is not set.
+func envOrDefault(name string, defaultValue string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return defaultValue
+}
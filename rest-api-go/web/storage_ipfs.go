@@ -0,0 +1,102 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ipfsStorage stores certificate files on an IPFS node through its HTTP RPC API; keys are content identifiers.
+type ipfsStorage struct {
+	apiURL string
+	client *http.Client
+}
+
+func newIPFSStorage(apiURL string) *ipfsStorage {
+	return &ipfsStorage{apiURL: strings.TrimSuffix(apiURL, "/"), client: certificateClient}
+}
+
+func (storage *ipfsStorage) Put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", path.Base(key))
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	// Pin the file so that the node keeps it
+	response, err := storage.call(ctx, "add", url.Values{"pin": {"true"}, "cid-version": {"1"}}, &body, writer.FormDataContentType())
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	var added struct {
+		Hash string `json:"Hash"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&added); err != nil {
+		return "", fmt.Errorf("invalid response from IPFS: %w", err)
+	}
+	if added.Hash == "" {
+		return "", fmt.Errorf("IPFS did not return a content identifier")
+	}
+
+	return added.Hash, nil
+}
+
+func (storage *ipfsStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	response, err := storage.call(ctx, "cat", url.Values{"arg": {key}}, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	return readCertificateFile(response.Body)
+}
+
+// Delete unpins the file, so that the node's garbage collection removes it unless it is pinned elsewhere.
+func (storage *ipfsStorage) Delete(ctx context.Context, key string) error {
+	response, err := storage.call(ctx, "pin/rm", url.Values{"arg": {key}}, nil, "")
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+
+	return nil
+}
+
+// call invokes an IPFS RPC API command, which always uses POST.
+func (storage *ipfsStorage) call(ctx context.Context, command string, query url.Values, body io.Reader, contentType string) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, storage.apiURL+"/api/v0/"+command+"?"+query.Encode(), body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	response, err := storage.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("IPFS %s failed: %w", command, err)
+	}
+	if response.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		response.Body.Close()
+		return nil, fmt.Errorf("IPFS %s failed: %s %s", command, response.Status, strings.TrimSpace(string(message)))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,84 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// localStorage stores certificate files in a directory on the server.
+type localStorage struct {
+	root string
+}
+
+func newLocalStorage(root string) (*localStorage, error) {
+	absoluteRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(absoluteRoot, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create certificate storage directory: %w", err)
+	}
+
+	return &localStorage{root: absoluteRoot}, nil
+}
+
+func (storage *localStorage) Put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	filePath, err := storage.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o750); err != nil {
+		return "", err
+	}
+
+	// Write to a temporary file first so that a partial upload is never visible under the key
+	temporaryFile, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(temporaryFile.Name())
+	if _, err := temporaryFile.Write(data); err != nil {
+		temporaryFile.Close()
+		return "", err
+	}
+	if err := temporaryFile.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(temporaryFile.Name(), filePath); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+func (storage *localStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	filePath, err := storage.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(filePath)
+}
+
+func (storage *localStorage) Delete(ctx context.Context, key string) error {
+	filePath, err := storage.path(key)
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(filePath)
+}
+
+// path maps a key to a file under the storage root, rejecting keys that escape it.
+func (storage *localStorage) path(key string) (string, error) {
+	filePath := filepath.Join(storage.root, filepath.FromSlash(key))
+	if !strings.HasPrefix(filePath, storage.root+string(filepath.Separator)) {
+		return "", errors.New("invalid certificate key")
+	}
+
+	return filePath, nil
+}
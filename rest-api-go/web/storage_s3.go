@@ -0,0 +1,66 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3Config holds the connection details of an S3-compatible store such as MinIO or Filebase.
+type s3Config struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+}
+
+// s3Storage stores certificate files in a bucket of an S3-compatible store.
+type s3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+func newS3Storage(config s3Config) (*s3Storage, error) {
+	if config.Endpoint == "" || config.Bucket == "" {
+		return nil, errors.New("S3_ENDPOINT and S3_BUCKET are required for S3 certificate storage")
+	}
+
+	client, err := minio.New(config.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.AccessKey, config.SecretKey, ""),
+		Secure: config.UseSSL,
+		Region: config.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Storage{client: client, bucket: config.Bucket}, nil
+}
+
+func (storage *s3Storage) Put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	_, err := storage.client.PutObject(ctx, storage.bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+func (storage *s3Storage) Get(ctx context.Context, key string) ([]byte, error) {
+	object, err := storage.client.GetObject(ctx, storage.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	return readCertificateFile(object)
+}
+
+func (storage *s3Storage) Delete(ctx context.Context, key string) error {
+	return storage.client.RemoveObject(ctx, storage.bucket, key, minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,182 @@
+package web
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStorage(t *testing.T) {
+	storage, err := newLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	key, err := storage.Put(ctx, "certificates/S1/A1/file.pdf", []byte("certificate"), "application/pdf")
+	if err != nil || key != "certificates/S1/A1/file.pdf" {
+		t.Fatalf("Put returned %q, %v", key, err)
+	}
+	data, err := storage.Get(ctx, key)
+	if err != nil || string(data) != "certificate" {
+		t.Fatalf("Get returned %q, %v", data, err)
+	}
+	if err := storage.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.Get(ctx, key); !os.IsNotExist(err) {
+		t.Fatalf("Get after Delete returned %v, want a missing file", err)
+	}
+
+	// No temporary upload files are left behind
+	leftovers, _ := filepath.Glob(filepath.Join(storage.root, "certificates", "S1", "A1", ".upload-*"))
+	if len(leftovers) != 0 {
+		t.Fatalf("temporary files are left behind: %v", leftovers)
+	}
+}
+
+func TestLocalStorageRejectsKeysOutsideTheRoot(t *testing.T) {
+	storage, err := newLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"../outside.pdf", "certificates/../../outside.pdf", "", "."} {
+		if _, err := storage.Put(context.Background(), key, []byte("x"), ""); err == nil {
+			t.Errorf("Put accepts key %q", key)
+		}
+		if _, err := storage.Get(context.Background(), key); err == nil {
+			t.Errorf("Get accepts key %q", key)
+		}
+	}
+}
+
+func TestCertificateKey(t *testing.T) {
+	key, err := certificateKey("S1", "../A1", "C:\\scans/my cert.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(key, "/")
+	if len(parts) != 4 || parts[0] != "certificates" || parts[1] != "S1" || parts[2] != "_A1" {
+		t.Fatalf("certificateKey returned %s", key)
+	}
+	if !strings.HasSuffix(parts[3], "-my_cert.pdf") || len(parts[3]) != 16+len("-my_cert.pdf") {
+		t.Fatalf("file part of key %s is not a random prefix and the safe file name", key)
+	}
+
+	other, err := certificateKey("S1", "../A1", "C:\\scans/my cert.pdf")
+	if err != nil || other == key {
+		t.Fatalf("two uploads of the same file get the same key %s", key)
+	}
+}
+
+func TestSafeKeyPart(t *testing.T) {
+	tests := map[string]string{
+		"S1":          "S1",
+		"a b/c":       "a_b_c",
+		"..":          "_",
+		"":            "_",
+		".hidden.pdf": "hidden.pdf",
+	}
+	for part, want := range tests {
+		if got := safeKeyPart(part); got != want {
+			t.Errorf("safeKeyPart(%q) = %q, want %q", part, got, want)
+		}
+	}
+}
+
+func TestURLSigner(t *testing.T) {
+	signer := &URLSigner{secret: []byte("secret"), baseURL: "https://api.example.org", ttl: time.Minute}
+	signedURL, expires := signer.Sign("certificates/S1/A1/file.pdf")
+	if time.Until(expires) > time.Minute || time.Until(expires) < 50*time.Second {
+		t.Fatalf("URL expires at %v, want in a minute", expires)
+	}
+
+	parsed, err := url.Parse(signedURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Host != "api.example.org" || parsed.Path != "/certificates/download" {
+		t.Fatalf("signed URL is %s", signedURL)
+	}
+	query := parsed.Query()
+	if err := signer.Verify(query.Get("key"), query.Get("expires"), query.Get("signature")); err != nil {
+		t.Fatalf("signed URL does not verify: %v", err)
+	}
+
+	if err := signer.Verify("certificates/S2/A1/file.pdf", query.Get("expires"), query.Get("signature")); err == nil {
+		t.Error("signature verifies for another key")
+	}
+	later := strconv.FormatInt(expires.Add(time.Hour).Unix(), 10)
+	if err := signer.Verify(query.Get("key"), later, query.Get("signature")); err == nil {
+		t.Error("signature verifies with an extended expiry")
+	}
+	other := &URLSigner{secret: []byte("other"), baseURL: signer.baseURL, ttl: signer.ttl}
+	if err := other.Verify(query.Get("key"), query.Get("expires"), query.Get("signature")); err == nil {
+		t.Error("signature verifies with another secret")
+	}
+
+	expired := &URLSigner{secret: signer.secret, baseURL: signer.baseURL, ttl: -time.Minute}
+	expiredURL, _ := expired.Sign("certificates/S1/A1/file.pdf")
+	parsed, _ = url.Parse(expiredURL)
+	query = parsed.Query()
+	if err := signer.Verify(query.Get("key"), query.Get("expires"), query.Get("signature")); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("expired URL verifies with %v", err)
+	}
+}
+
+func TestIPFSStorage(t *testing.T) {
+	files := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("IPFS API called with %s", r.Method)
+		}
+		switch r.URL.Path {
+		case "/api/v0/add":
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(file)
+			files["bafy1"] = string(data)
+			w.Write([]byte(`{"Name":"file.pdf","Hash":"bafy1","Size":"11"}`))
+		case "/api/v0/cat":
+			data, exists := files[r.URL.Query().Get("arg")]
+			if !exists {
+				http.Error(w, "not found", http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(data))
+		case "/api/v0/pin/rm":
+			delete(files, r.URL.Query().Get("arg"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	storage := newIPFSStorage(server.URL + "/")
+	ctx := context.Background()
+	key, err := storage.Put(ctx, "certificates/S1/A1/file.pdf", []byte("certificate"), "application/pdf")
+	if err != nil || key != "bafy1" {
+		t.Fatalf("Put returned %q, %v, want the content identifier", key, err)
+	}
+	data, err := storage.Get(ctx, key)
+	if err != nil || string(data) != "certificate" {
+		t.Fatalf("Get returned %q, %v", data, err)
+	}
+	if err := storage.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.Get(ctx, key); err == nil {
+		t.Fatal("Get after Delete succeeds")
+	}
+}
@@ -0,0 +1,589 @@
+package web
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+	"github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"google.golang.org/protobuf/proto"
+)
+
+// ledgerResult mirrors the Result stored by the chaincode.
+type ledgerResult struct {
+	CourseID string `json:"courseID"`
+	Grade    string `json:"grade"`
+	TxID     string `json:"txID"`
+}
+
+// ledgerGraduation mirrors the Graduation stored by the chaincode.
+type ledgerGraduation struct {
+	GraduationDate string  `json:"graduationDate"`
+	CGPA           float64 `json:"cgpa"`
+	Division       string  `json:"division"`
+}
+
+// ledgerEnrollment mirrors the parts of the chaincode's Enrollment needed for a transcript.
+type ledgerEnrollment struct {
+	StudentID        string                    `json:"studentID"`
+	Name             string                    `json:"name"`
+	ProgramType      string                    `json:"programType"`
+	DepartmentID     string                    `json:"department"`
+	CreditsCompleted int                       `json:"creditsCompleted"`
+	CurrentSemester  string                    `json:"currentSemester"`
+	SemesterResults  map[string][]ledgerResult `json:"semesterResults"`
+	Frozen           bool                      `json:"frozen"`
+	Graduation       ledgerGraduation          `json:"graduation"`
+}
+
+// ledgerCourse mirrors the parts of the chaincode's Course needed for a transcript.
+type ledgerCourse struct {
+	CourseID   string `json:"courseID"`
+	CourseName string `json:"name"`
+	Credits    int    `json:"credits"`
+}
+
+// ledgerActivity mirrors the parts of the chaincode's ExtracurricularActivity needed for a transcript.
+type ledgerActivity struct {
+	ActivityID   string `json:"activityID"`
+	ActivityName string `json:"name"`
+	Date         string `json:"date"`
+	FacultyID    string `json:"facultyID"` // Faculty in charge of the activity
+}
+
+// LedgerReference points to the transaction and block that recorded a value on the ledger.
+type LedgerReference struct {
+	TxID        string `json:"txID"`
+	BlockNumber uint64 `json:"blockNumber"`
+}
+
+// TranscriptCourse is a graded course on a transcript.
+type TranscriptCourse struct {
+	CourseID   string          `json:"courseID"`
+	CourseName string          `json:"courseName"`
+	Credits    int             `json:"credits"`
+	Grade      string          `json:"grade"`
+	Ledger     LedgerReference `json:"ledger"`
+}
+
+// TranscriptSemester holds the results of one semester on a transcript.
+type TranscriptSemester struct {
+	Semester      string             `json:"semester"`
+	Courses       []TranscriptCourse `json:"courses"`
+	SGPA          float64            `json:"sgpa"`
+	CreditsTaken  int                `json:"creditsTaken"`
+	CreditsEarned int                `json:"creditsEarned"`
+}
+
+// TranscriptActivity is an extracurricular activity with an approved certificate on a transcript.
+type TranscriptActivity struct {
+	ActivityID        string `json:"activityID"`
+	ActivityName      string `json:"activityName"`
+	Date              string `json:"date"`
+	CertificateSHA256 string `json:"certificateSHA256"`
+	ApprovedBy        string `json:"approvedBy"`
+	ApprovedAt        string `json:"approvedAt"`
+}
+
+// Transcript is the official record of a student's results issued by the organization.
+type Transcript struct {
+	TranscriptID     string               `json:"transcriptID"`
+	StudentID        string               `json:"studentID"`
+	Name             string               `json:"name"`
+	ProgramType      string               `json:"programType"`
+	DepartmentID     string               `json:"department"`
+	CurrentSemester  string               `json:"currentSemester"`
+	Semesters        []TranscriptSemester `json:"semesters"`
+	Activities       []TranscriptActivity `json:"activities"`
+	CGPA             float64              `json:"cgpa"`
+	CreditsCompleted int                  `json:"creditsCompleted"`
+	Graduated        bool                 `json:"graduated"`
+	GraduationDate   string               `json:"graduationDate,omitempty"`
+	Division         string               `json:"division,omitempty"`
+	ChannelID        string               `json:"channelID"`
+	ChaincodeName    string               `json:"chaincodeName"`
+	IssuerOrg        string               `json:"issuerOrg"`
+	IssuerMSPID      string               `json:"issuerMSPID"`
+	IssuedAt         string               `json:"issuedAt"`
+}
+
+// DocumentSignature is the organization's signature over a document, verifiable offline with the included certificate.
+type DocumentSignature struct {
+	Algorithm   string `json:"algorithm"`
+	MSPID       string `json:"mspID"`
+	Certificate string `json:"certificate"` // PEM encoded signing certificate
+	Digest      string `json:"digest"`      // Hex encoded SHA-256 digest of the signed document bytes
+	Value       string `json:"value"`       // Base64 encoded ASN.1 ECDSA signature over the digest
+}
+
+// SignedTranscript is the machine-readable transcript; the signature covers the exact bytes of the transcript field.
+type SignedTranscript struct {
+	Transcript json.RawMessage   `json:"transcript"`
+	Signature  DocumentSignature `json:"signature"`
+}
+
+const signatureAlgorithm = "ECDSA-SHA256"
+
+// GetTranscript returns a student's transcript for viewing. It is not signed or recorded on the ledger; use IssueTranscript
+// for an official copy. Query parameters: channelid, chaincodeid and args=<studentID>.
+// The request must be signed by the student or an admin.
+func (setup OrgSetup) GetTranscript(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received GetTranscript request")
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	channelID, chainCodeName, studentID, ok := setup.transcriptRequest(w, r)
+	if !ok {
+		return
+	}
+
+	network := setup.Gateway.GetNetwork(channelID)
+	transcript, err := setup.buildTranscript(network, channelID, chainCodeName, studentID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+	transcriptJSON, err := json.Marshal(transcript)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(transcriptJSON)
+}
+
+// IssueTranscript issues an official transcript signed with the organization's key and records it on the ledger.
+// POST form with channelid, chaincodeid, args=<studentID> and format=json (default) or format=pdf.
+// The request must be signed by the student or an admin.
+func (setup OrgSetup) IssueTranscript(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received IssueTranscript request")
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	channelID, chainCodeName, studentID, ok := setup.transcriptRequest(w, r)
+	if !ok {
+		return
+	}
+	format := r.FormValue("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "pdf" {
+		http.Error(w, fmt.Sprintf("Unsupported format %s", format), http.StatusBadRequest)
+		return
+	}
+
+	network := setup.Gateway.GetNetwork(channelID)
+	transcript, err := setup.buildTranscript(network, channelID, chainCodeName, studentID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	signed, err := setup.signTranscript(transcript)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+	signedJSON, err := json.Marshal(signed)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	document := signedJSON
+	if format == "pdf" {
+		document = renderTranscriptPDF(transcript, signed.Signature, signedJSON)
+	}
+
+	// Record the transcript and file hashes on the ledger so that the transcript can be verified publicly
+	fileHash := sha256.Sum256(document)
+	contract := network.GetContract(chainCodeName)
+	_, err = contract.SubmitTransaction("RecordTranscriptIssuance", transcript.TranscriptID, transcript.StudentID, signed.Signature.Digest, hex.EncodeToString(fileHash[:]))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	if format == "pdf" {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", transcript.TranscriptID+".pdf"))
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.Write(document)
+}
+
+// transcriptRequest reads the channel, chaincode and student of a transcript request and checks that it is signed by
+// the student or an admin. It writes the error response and returns false if the request is not valid or not allowed.
+func (setup OrgSetup) transcriptRequest(w http.ResponseWriter, r *http.Request) (string, string, string, bool) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, fmt.Sprintf("ParseForm() err: %s", err), http.StatusBadRequest)
+		return "", "", "", false
+	}
+	channelID, chainCodeName, err := requestChaincode(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", "", "", false
+	}
+	argsArray := r.Form["args"]
+	if len(argsArray) != 1 {
+		http.Error(w, "Invalid number of arguments", http.StatusBadRequest)
+		return "", "", "", false
+	}
+
+	caller, err := setup.authenticateCaller(r, argsArray)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusUnauthorized)
+		return "", "", "", false
+	}
+	if !caller.isAdmin() && caller.Attributes["studentID"] != argsArray[0] {
+		http.Error(w, "Error: only the student and admins can get the transcript", http.StatusForbidden)
+		return "", "", "", false
+	}
+
+	return channelID, chainCodeName, argsArray[0], true
+}
+
+// buildTranscript collects a student's results, SGPA, CGPA, credits and ledger references.
+func (setup OrgSetup) buildTranscript(network *client.Network, channelID string, chainCodeName string, studentID string) (*Transcript, error) {
+	contract := network.GetContract(chainCodeName)
+
+	enrollmentJSON, err := contract.EvaluateTransaction("GetEnrollment", studentID)
+	if err != nil {
+		return nil, err
+	}
+	var enrollment ledgerEnrollment
+	if err := json.Unmarshal(enrollmentJSON, &enrollment); err != nil {
+		return nil, err
+	}
+
+	// The enrollment lists private grades as empty, so read the results with their grades
+	resultsJSON, err := contract.EvaluateTransaction("GetResultsForAllSemesters", studentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(resultsJSON, &enrollment.SemesterResults); err != nil {
+		return nil, err
+	}
+
+	// The enrollment leaves the completed credits out too, so read them from the credit summary
+	summaryJSON, err := contract.EvaluateTransaction("GetCreditSummary", studentID)
+	if err != nil {
+		return nil, err
+	}
+	var summary struct {
+		Earned int `json:"earned"`
+	}
+	if err := json.Unmarshal(summaryJSON, &summary); err != nil {
+		return nil, err
+	}
+	enrollment.CreditsCompleted = summary.Earned
+
+	now := time.Now().UTC()
+	transcript := Transcript{
+		TranscriptID:     fmt.Sprintf("TRANSCRIPT-%s-%d", studentID, now.Unix()),
+		StudentID:        enrollment.StudentID,
+		Name:             enrollment.Name,
+		ProgramType:      enrollment.ProgramType,
+		DepartmentID:     enrollment.DepartmentID,
+		CurrentSemester:  enrollment.CurrentSemester,
+		Semesters:        []TranscriptSemester{},
+		Activities:       []TranscriptActivity{},
+		CreditsCompleted: enrollment.CreditsCompleted,
+		Graduated:        enrollment.Frozen,
+		ChannelID:        channelID,
+		ChaincodeName:    chainCodeName,
+		IssuerOrg:        setup.OrgName,
+		IssuerMSPID:      setup.MSPID,
+		IssuedAt:         now.Format(time.RFC3339),
+	}
+	if enrollment.Frozen {
+		transcript.GraduationDate = enrollment.Graduation.GraduationDate
+		transcript.Division = enrollment.Graduation.Division
+	}
+
+	// List the semesters in order
+	semesters := make([]string, 0, len(enrollment.SemesterResults))
+	for semester := range enrollment.SemesterResults {
+		semesters = append(semesters, semester)
+	}
+	sort.Slice(semesters, func(i, j int) bool {
+		return semesterNumber(semesters[i]) < semesterNumber(semesters[j])
+	})
+
+	courses := make(map[string]ledgerCourse)
+	blocks := make(map[string]uint64)
+	for _, semester := range semesters {
+		transcriptSemester := TranscriptSemester{Semester: semester, Courses: []TranscriptCourse{}}
+
+		for _, result := range enrollment.SemesterResults[semester] {
+			course, exists := courses[result.CourseID]
+			if !exists {
+				courseJSON, err := contract.EvaluateTransaction("GetCourse", result.CourseID)
+				if err != nil {
+					return nil, err
+				}
+				if err := json.Unmarshal(courseJSON, &course); err != nil {
+					return nil, err
+				}
+				courses[result.CourseID] = course
+			}
+
+			reference := LedgerReference{TxID: result.TxID}
+			if result.TxID != "" {
+				blockNumber, exists := blocks[result.TxID]
+				if !exists {
+					blockNumber, err = setup.blockNumberForTx(network, channelID, result.TxID)
+					if err != nil {
+						return nil, err
+					}
+					blocks[result.TxID] = blockNumber
+				}
+				reference.BlockNumber = blockNumber
+			}
+
+			transcriptSemester.Courses = append(transcriptSemester.Courses, TranscriptCourse{
+				CourseID:   result.CourseID,
+				CourseName: course.CourseName,
+				Credits:    course.Credits,
+				Grade:      result.Grade,
+				Ledger:     reference,
+			})
+			transcriptSemester.CreditsTaken += course.Credits
+			if result.Grade != "F" {
+				transcriptSemester.CreditsEarned += course.Credits
+			}
+		}
+
+		sgpa, err := evaluateFloat(contract, "CalculateSGPA", studentID, semester)
+		if err != nil {
+			return nil, err
+		}
+		transcriptSemester.SGPA = sgpa
+
+		transcript.Semesters = append(transcript.Semesters, transcriptSemester)
+	}
+
+	cgpa, err := evaluateFloat(contract, "CalculateCGPA", studentID)
+	if err != nil {
+		return nil, err
+	}
+	transcript.CGPA = cgpa
+
+	// Only certificates approved by the faculty in charge of the activity are listed
+	certificatesJSON, err := contract.EvaluateTransaction("GetApprovedCertificates", studentID)
+	if err != nil {
+		return nil, err
+	}
+	var certificates []ledgerCertificate
+	if err := json.Unmarshal(certificatesJSON, &certificates); err != nil {
+		return nil, err
+	}
+	for _, certificate := range certificates {
+		activityJSON, err := contract.EvaluateTransaction("GetExtracurricularActivity", certificate.ActivityID)
+		if err != nil {
+			return nil, err
+		}
+		var activity ledgerActivity
+		if err := json.Unmarshal(activityJSON, &activity); err != nil {
+			return nil, err
+		}
+		transcript.Activities = append(transcript.Activities, TranscriptActivity{
+			ActivityID:        certificate.ActivityID,
+			ActivityName:      activity.ActivityName,
+			Date:              activity.Date,
+			CertificateSHA256: certificate.SHA256,
+			ApprovedBy:        certificate.ReviewedBy,
+			ApprovedAt:        certificate.ReviewedAt,
+		})
+	}
+
+	return &transcript, nil
+}
+
+// blockNumberForTx looks up the number of the block containing a transaction using the query system chaincode.
+func (setup OrgSetup) blockNumberForTx(network *client.Network, channelID string, txID string) (uint64, error) {
+	blockBytes, err := network.GetContract("qscc").EvaluateTransaction("GetBlockByTxID", channelID, txID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block for transaction %s: %w", txID, err)
+	}
+
+	var block common.Block
+	if err := proto.Unmarshal(blockBytes, &block); err != nil {
+		return 0, fmt.Errorf("failed to decode block for transaction %s: %w", txID, err)
+	}
+
+	return block.GetHeader().GetNumber(), nil
+}
+
+// signTranscript signs the canonical JSON bytes of the transcript with the organization's key.
+func (setup OrgSetup) signTranscript(transcript *Transcript) (*SignedTranscript, error) {
+	transcriptJSON, err := json.Marshal(transcript)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := setup.signDocument(transcriptJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedTranscript{Transcript: transcriptJSON, Signature: *signature}, nil
+}
+
+// signDocument signs the SHA-256 digest of a document with the organization's ECDSA key.
+func (setup OrgSetup) signDocument(document []byte) (*DocumentSignature, error) {
+	privateKey, ok := setup.PrivateKey.(crypto.Signer)
+	if !ok || setup.Certificate == nil {
+		return nil, errors.New("organization key is not available for signing")
+	}
+	if _, isECDSA := privateKey.Public().(*ecdsa.PublicKey); !isECDSA {
+		return nil, errors.New("organization key is not an ECDSA key")
+	}
+
+	digest := sha256.Sum256(document)
+	signature, err := privateKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign document: %w", err)
+	}
+
+	return &DocumentSignature{
+		Algorithm:   signatureAlgorithm,
+		MSPID:       setup.MSPID,
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: setup.Certificate.Raw})),
+		Digest:      hex.EncodeToString(digest[:]),
+		Value:       base64.StdEncoding.EncodeToString(signature),
+	}, nil
+}
+
+// verifyDocumentSignature checks a document against its signature using the certificate included in the signature.
+// The certificate must be issued by one of the organization's CAs, otherwise anyone could sign with a certificate of their own.
+func verifyDocumentSignature(document []byte, signature DocumentSignature, organizationCAs *x509.CertPool) error {
+	if signature.Algorithm != signatureAlgorithm {
+		return fmt.Errorf("unsupported signature algorithm %s", signature.Algorithm)
+	}
+	if organizationCAs == nil {
+		return errors.New("organization CA certificates are not configured")
+	}
+
+	block, _ := pem.Decode([]byte(signature.Certificate))
+	if block == nil {
+		return errors.New("signature certificate is not PEM encoded")
+	}
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse signature certificate: %w", err)
+	}
+	_, err = certificate.Verify(x509.VerifyOptions{Roots: organizationCAs, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+	if err != nil {
+		return fmt.Errorf("signature certificate is not issued by the organization: %w", err)
+	}
+	publicKey, ok := certificate.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return errors.New("signature certificate does not hold an ECDSA key")
+	}
+
+	digest := sha256.Sum256(document)
+	if hex.EncodeToString(digest[:]) != signature.Digest {
+		return errors.New("document digest does not match the signed digest")
+	}
+	value, err := base64.StdEncoding.DecodeString(signature.Value)
+	if err != nil {
+		return fmt.Errorf("signature value is not base64 encoded: %w", err)
+	}
+	if !ecdsa.VerifyASN1(publicKey, digest[:], value) {
+		return errors.New("signature does not match the document")
+	}
+
+	return nil
+}
+
+// renderTranscriptPDF lays out the transcript as a PDF, with the signed JSON embedded for offline verification.
+func renderTranscriptPDF(transcript *Transcript, signature DocumentSignature, signedJSON []byte) []byte {
+	doc := &pdfDocument{}
+	doc.AddLine("Official Transcript", 18, true)
+	doc.AddLine(fmt.Sprintf("Issued by %s (%s) on %s", transcript.IssuerOrg, transcript.IssuerMSPID, transcript.IssuedAt), 9, false)
+	doc.AddLine(fmt.Sprintf("Transcript ID: %s", transcript.TranscriptID), 9, false)
+	doc.AddBlankLine()
+	doc.AddLine(fmt.Sprintf("Name: %s", transcript.Name), 11, false)
+	doc.AddLine(fmt.Sprintf("Roll No: %s", transcript.StudentID), 11, false)
+	doc.AddLine(fmt.Sprintf("Program: %s    Department: %s", transcript.ProgramType, transcript.DepartmentID), 11, false)
+	if transcript.Graduated {
+		doc.AddLine(fmt.Sprintf("Graduated on %s with %s", transcript.GraduationDate, transcript.Division), 11, false)
+	} else {
+		doc.AddLine(fmt.Sprintf("Current semester: %s", transcript.CurrentSemester), 11, false)
+	}
+
+	for _, semester := range transcript.Semesters {
+		doc.AddBlankLine()
+		doc.AddLine(semester.Semester, 13, true)
+		for _, course := range semester.Courses {
+			doc.AddLine(fmt.Sprintf("%-10s %-40s %3d credits   Grade %-2s   block %d, tx %s",
+				course.CourseID, course.CourseName, course.Credits, course.Grade, course.Ledger.BlockNumber, course.Ledger.TxID), 8, false)
+		}
+		doc.AddLine(fmt.Sprintf("SGPA %.2f    Credits taken %d    Credits earned %d", semester.SGPA, semester.CreditsTaken, semester.CreditsEarned), 10, true)
+	}
+
+	doc.AddBlankLine()
+	doc.AddLine(fmt.Sprintf("CGPA %.2f    Credits completed %d", transcript.CGPA, transcript.CreditsCompleted), 12, true)
+
+	if len(transcript.Activities) > 0 {
+		doc.AddBlankLine()
+		doc.AddLine("Extracurricular activities", 13, true)
+		for _, activity := range transcript.Activities {
+			doc.AddLine(fmt.Sprintf("%-10s %-40s %s   certificate sha256 %s", activity.ActivityID, activity.ActivityName, activity.Date, activity.CertificateSHA256), 8, false)
+		}
+	}
+
+	doc.AddBlankLine()
+	doc.AddLine("Verification", 11, true)
+	doc.AddLine(fmt.Sprintf("Ledger: channel %s, chaincode %s", transcript.ChannelID, transcript.ChaincodeName), 8, false)
+	doc.AddLine(fmt.Sprintf("Signed by %s using %s", signature.MSPID, signature.Algorithm), 8, false)
+	doc.AddLine(fmt.Sprintf("SHA-256 digest: %s", signature.Digest), 8, false)
+	doc.AddLine(fmt.Sprintf("Signature: %s", signature.Value), 8, false)
+	doc.AddLine("The signed machine-readable transcript is attached to this PDF as transcript.json.", 8, false)
+
+	doc.Attach(pdfAttachment{
+		Name:        "transcript.json",
+		MimeType:    "application/json",
+		Description: "Signed machine-readable transcript",
+		Data:        signedJSON,
+	})
+
+	return doc.Bytes()
+}
+
+// evaluateFloat evaluates a chaincode function that returns a number.
+func evaluateFloat(contract *client.Contract, function string, args ...string) (float64, error) {
+	response, err := contract.EvaluateTransaction(function, args...)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(string(response), 64)
+}
+
+// semesterNumber extracts the semester number from names such as Semester3.
+func semesterNumber(semester string) int {
+	var number int
+	if _, err := fmt.Sscanf(semester, "Semester%d", &number); err != nil {
+		return 0
+	}
+	return number
+}
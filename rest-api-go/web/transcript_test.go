@@ -0,0 +1,248 @@
+package web
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newSigningSetup returns an organization setup with an ECDSA certificate and key issued by the organization's CA.
+func newSigningSetup(t *testing.T) OrgSetup {
+	t.Helper()
+	ca := newTestCA(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Org1 signer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setup := ca.setup()
+	setup.OrgName, setup.MSPID, setup.Certificate, setup.PrivateKey = "Org1", "Org1MSP", certificate, key
+	return setup
+}
+
+func testTranscript() *Transcript {
+	return &Transcript{
+		TranscriptID: "TR-S1",
+		StudentID:    "S1",
+		Name:         "Asha",
+		ProgramType:  "BTECH",
+		DepartmentID: "CSE",
+		Semesters: []TranscriptSemester{{
+			Semester: "Semester1",
+			Courses: []TranscriptCourse{{
+				CourseID: "CS101", CourseName: "Programming", Credits: 4, Grade: "A",
+				Ledger: LedgerReference{TxID: "tx1", BlockNumber: 7},
+			}},
+			SGPA:          9,
+			CreditsTaken:  4,
+			CreditsEarned: 4,
+		}},
+		CGPA:             9,
+		CreditsCompleted: 4,
+		IssuerOrg:        "Org1",
+		IssuerMSPID:      "Org1MSP",
+	}
+}
+
+func TestSignedTranscriptVerifies(t *testing.T) {
+	setup := newSigningSetup(t)
+	signed, err := setup.signTranscript(testTranscript())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed.Signature.MSPID != "Org1MSP" || signed.Signature.Algorithm != signatureAlgorithm {
+		t.Fatalf("signature is %+v", signed.Signature)
+	}
+	if err := verifyDocumentSignature(signed.Transcript, signed.Signature, setup.CallerCAs); err != nil {
+		t.Fatalf("signed transcript does not verify: %v", err)
+	}
+
+	// The signature survives a round trip through the JSON handed to an employer
+	signedJSON, err := json.Marshal(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var received SignedTranscript
+	if err := json.Unmarshal(signedJSON, &received); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyDocumentSignature(received.Transcript, received.Signature, setup.CallerCAs); err != nil {
+		t.Fatalf("received transcript does not verify: %v", err)
+	}
+}
+
+func TestTamperedTranscriptDoesNotVerify(t *testing.T) {
+	setup := newSigningSetup(t)
+	signed, err := setup.signTranscript(testTranscript())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := bytes.Replace(signed.Transcript, []byte(`"grade":"A"`), []byte(`"grade":"S"`), 1)
+	if err := verifyDocumentSignature(tampered, signed.Signature, setup.CallerCAs); err == nil {
+		t.Fatal("tampered transcript verifies")
+	}
+
+	// A digest recomputed for the tampered transcript does not match the signature value
+	other, err := setup.signDocument(tampered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := signed.Signature
+	forged.Digest = other.Digest
+	if err := verifyDocumentSignature(tampered, forged, setup.CallerCAs); err == nil {
+		t.Fatal("tampered transcript with a recomputed digest verifies")
+	}
+
+	// A signature made with another key does not verify against the included certificate
+	stranger := newSigningSetup(t)
+	foreign, err := stranger.signDocument(signed.Transcript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreign.Certificate = signed.Signature.Certificate
+	if err := verifyDocumentSignature(signed.Transcript, *foreign, setup.CallerCAs); err == nil {
+		t.Fatal("signature made with another key verifies")
+	}
+
+	// A document signed with the key of a certificate the organization did not issue does not verify
+	outsider, err := stranger.signDocument(signed.Transcript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyDocumentSignature(signed.Transcript, *outsider, setup.CallerCAs); err == nil || !strings.Contains(err.Error(), "not issued by the organization") {
+		t.Fatalf("signature with a certificate of another CA returned %v", err)
+	}
+	if err := verifyDocumentSignature(signed.Transcript, signed.Signature, nil); err == nil {
+		t.Fatal("signature verifies without the organization's CAs")
+	}
+}
+
+func TestSignDocumentNeedsTheOrganizationKey(t *testing.T) {
+	if _, err := (OrgSetup{MSPID: "Org1MSP"}).signDocument([]byte("{}")); err == nil {
+		t.Fatal("signing without a key succeeds")
+	}
+}
+
+func TestTranscriptPDFEmbedsTheSignedJSON(t *testing.T) {
+	setup := newSigningSetup(t)
+	transcript := testTranscript()
+	signed, err := setup.signTranscript(transcript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedJSON, err := json.Marshal(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdf := renderTranscriptPDF(transcript, signed.Signature, signedJSON)
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.7\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatal("transcript PDF is missing its header or trailer")
+	}
+	for _, want := range []string{"/EmbeddedFiles", "(transcript.json)", string(signedJSON), "(Roll No: S1)"} {
+		if !bytes.Contains(pdf, []byte(want)) {
+			t.Errorf("transcript PDF does not contain %q", want)
+		}
+	}
+}
+
+// transcriptRequestFor returns a transcript request for a student, signed by the caller unless it is nil
+func transcriptRequestFor(t *testing.T, method string, path string, caller *testCaller, studentID string) *http.Request {
+	t.Helper()
+	request := httptest.NewRequest(method, path+"?channelid=mychannel&chaincodeid=basic&args="+studentID, nil)
+	if caller != nil {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		request.Header.Set(certificateHeader, base64.StdEncoding.EncodeToString(caller.certificatePEM))
+		request.Header.Set(timestampHeader, timestamp)
+		request.Header.Set(signatureHeader, caller.sign(t, path, timestamp, []string{studentID}))
+	}
+	return request
+}
+
+func TestTranscriptRequestsAreAuthorized(t *testing.T) {
+	ca := newTestCA(t)
+	setup := ca.setup()
+	student := ca.enroll(t, map[string]string{"studentID": "S1"})
+	admin := ca.enroll(t, map[string]string{"role": "admin"})
+	faculty := ca.enroll(t, map[string]string{"facultyID": "F1", "role": "faculty"})
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		request *http.Request
+		want    int
+	}{
+		{"unsigned view", setup.GetTranscript, transcriptRequestFor(t, http.MethodGet, "/GetTranscript", nil, "S1"), http.StatusUnauthorized},
+		{"view of another student", setup.GetTranscript, transcriptRequestFor(t, http.MethodGet, "/GetTranscript", student, "S2"), http.StatusForbidden},
+		{"view by faculty", setup.GetTranscript, transcriptRequestFor(t, http.MethodGet, "/GetTranscript", faculty, "S1"), http.StatusForbidden},
+		{"issue with GET", setup.IssueTranscript, transcriptRequestFor(t, http.MethodGet, "/IssueTranscript", student, "S1"), http.StatusMethodNotAllowed},
+		{"unsigned issue", setup.IssueTranscript, transcriptRequestFor(t, http.MethodPost, "/IssueTranscript", nil, "S1"), http.StatusUnauthorized},
+		{"issue for another student", setup.IssueTranscript, transcriptRequestFor(t, http.MethodPost, "/IssueTranscript", student, "S2"), http.StatusForbidden},
+		{"without the channel", setup.GetTranscript, httptest.NewRequest(http.MethodGet, "/GetTranscript?args=S1", nil), http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		test.handler(recorder, test.request)
+		if recorder.Code != test.want {
+			t.Errorf("%s returned %d %q, want %d", test.name, recorder.Code, recorder.Body.String(), test.want)
+		}
+	}
+
+	// The student and admins are allowed, before the ledger is read
+	for _, caller := range []*testCaller{student, admin} {
+		recorder := httptest.NewRecorder()
+		_, _, studentID, ok := setup.transcriptRequest(recorder, transcriptRequestFor(t, http.MethodPost, "/IssueTranscript", caller, "S1"))
+		if !ok || studentID != "S1" {
+			t.Errorf("allowed caller is refused with %d %q", recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
+func TestPDFString(t *testing.T) {
+	tests := map[string]string{
+		"Grade (A)": `(Grade \(A\))`,
+		`a\b`:       `(a\\b)`,
+		"café":      `(caf\351)`,
+		"日本":        "(??)",
+	}
+	for text, want := range tests {
+		if got := pdfString(text); got != want {
+			t.Errorf("pdfString(%q) = %s, want %s", text, got, want)
+		}
+	}
+}
+
+func TestSemesterNumber(t *testing.T) {
+	if got := semesterNumber("Semester10"); got != 10 {
+		t.Errorf("semesterNumber(Semester10) = %d, want 10", got)
+	}
+	if got := semesterNumber("Summer"); got != 0 {
+		t.Errorf("semesterNumber(Summer) = %d, want 0", got)
+	}
+}
@@ -0,0 +1,152 @@
+package web
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+)
+
+// maxVerificationUpload is the largest document accepted for verification
+const maxVerificationUpload = 10 << 20
+
+// CredentialVerification is the response of the public verification endpoint.
+type CredentialVerification struct {
+	Verification   json.RawMessage `json:"verification"`             // Verification result from the chaincode
+	DocumentHash   string          `json:"documentHash,omitempty"`   // SHA-256 of the uploaded document
+	SignatureValid *bool           `json:"signatureValid,omitempty"` // Set when the uploaded document carries the organization's signature
+	SignatureError string          `json:"signatureError,omitempty"`
+}
+
+// Verify is a public, read-only endpoint that checks a credential against the verification record on the ledger.
+// Every request names the ledger with channelid and chaincodeid, like the other endpoints.
+// GET with id=<credentialID> and optionally hash=<sha256>, or hash=<sha256> alone.
+// POST a multipart form with the exported transcript, certificate or verifiable credential in the document field.
+func (setup OrgSetup) Verify(w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Received Verify request")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Method == http.MethodPost {
+		r.Body = http.MaxBytesReader(w, r.Body, maxVerificationUpload)
+	}
+	channelID, chainCodeName, err := requestChaincode(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	contract := setup.Gateway.GetNetwork(channelID).GetContract(chainCodeName)
+
+	var response *CredentialVerification
+	switch r.Method {
+	case http.MethodGet:
+		response, err = verifyByID(contract, r.URL.Query().Get("id"), r.URL.Query().Get("hash"))
+	case http.MethodPost:
+		file, _, formErr := r.FormFile("document")
+		if formErr != nil {
+			http.Error(w, fmt.Sprintf("Error: document upload is required: %s", formErr), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		document, readErr := io.ReadAll(file)
+		if readErr != nil {
+			http.Error(w, fmt.Sprintf("Error: %s", readErr), http.StatusBadRequest)
+			return
+		}
+		response, err = setup.verifyDocument(contract, document)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: %s", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(responseJSON)
+}
+
+// verifyByID checks a credential by its ID and optional document hash, or finds the credential of a document hash.
+func verifyByID(contract *client.Contract, credentialID string, documentHash string) (*CredentialVerification, error) {
+	var result []byte
+	var err error
+	switch {
+	case credentialID != "":
+		result, err = contract.EvaluateTransaction("VerifyCredential", credentialID, documentHash)
+	case documentHash != "":
+		result, err = contract.EvaluateTransaction("VerifyDocumentHash", documentHash)
+	default:
+		return nil, fmt.Errorf("id or hash is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &CredentialVerification{Verification: result}, nil
+}
+
+// verifyDocument checks an uploaded document by the hash of the file and, for a signed transcript or verifiable credential, by the hash of its signed content.
+func (setup OrgSetup) verifyDocument(contract *client.Contract, document []byte) (*CredentialVerification, error) {
+	fileHash := sha256.Sum256(document)
+	response := &CredentialVerification{DocumentHash: hex.EncodeToString(fileHash[:])}
+	hashes := []string{response.DocumentHash}
+
+	// A signed transcript may have been reformatted, so also check the signature and the digest of the transcript itself
+	var signed SignedTranscript
+	if json.Unmarshal(document, &signed) == nil && len(signed.Transcript) > 0 {
+		var transcript bytes.Buffer
+		if err := json.Compact(&transcript, signed.Transcript); err != nil {
+			return nil, err
+		}
+		signatureValid := true
+		if err := verifyDocumentSignature(transcript.Bytes(), signed.Signature, setup.CallerCAs); err != nil {
+			signatureValid = false
+			response.SignatureError = err.Error()
+		}
+		response.SignatureValid = &signatureValid
+		if signatureValid {
+			hashes = append(hashes, signed.Signature.Digest)
+		}
+	}
+
+	// A verifiable credential is a JWT, matched by its hash after checking its signature
+	if token := strings.TrimSpace(string(document)); strings.Count(token, ".") == 2 && !strings.ContainsAny(token, " \n{") {
+		credentialHash, err := setup.verifyCredentialJWT(token)
+		signatureValid := err == nil
+		if err != nil {
+			response.SignatureError = err.Error()
+		}
+		response.SignatureValid = &signatureValid
+		if signatureValid {
+			hashes = append(hashes, credentialHash)
+		}
+	}
+
+	for _, hash := range hashes {
+		result, err := contract.EvaluateTransaction("VerifyDocumentHash", hash)
+		if err != nil {
+			return nil, err
+		}
+		response.Verification = result
+
+		var verification struct {
+			Verified bool `json:"verified"`
+		}
+		if json.Unmarshal(result, &verification) == nil && verification.Verified {
+			break
+		}
+	}
+
+	return response, nil
+}